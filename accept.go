@@ -0,0 +1,112 @@
+package httpclient
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// MediaRange is a media range of an Accept header, like "application/json"
+// or "text/*", with its weight Q between 0 and 1. A zero Q means 1.
+type MediaRange struct {
+	Type string
+	Q    float64
+}
+
+// MediaType is a parsed Content-Type.
+type MediaType struct {
+	Type    string
+	Subtype string
+	Params  map[string]string
+}
+
+// UnacceptableTypeError is returned by the requests made WithAccept when the
+// Content-Type of the response matches none of the accepted media ranges.
+type UnacceptableTypeError struct {
+	ContentType string
+	Accept      string
+}
+
+func (e *UnacceptableTypeError) Error() string {
+	return fmt.Sprintf("unacceptable content type %q, accepted: %s", e.ContentType, e.Accept)
+}
+
+// AcceptHeader returns the value of an Accept header for ranges, e.g.
+// "application/json, application/yaml;q=0.5".
+func AcceptHeader(ranges ...MediaRange) string {
+	parts := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		if r.Q <= 0 || r.Q >= 1 {
+			parts = append(parts, r.Type)
+			continue
+		}
+		parts = append(parts, r.Type+";q="+strconv.FormatFloat(r.Q, 'f', -1, 64))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// ParseContentType parses a Content-Type header into type, subtype and
+// parameters, lowercasing the type, the subtype and the parameter names.
+func ParseContentType(contentType string) (MediaType, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return MediaType{}, err
+	}
+
+	i := strings.IndexByte(mediaType, '/')
+	if i < 0 {
+		return MediaType{}, fmt.Errorf("mime: missing subtype in %q", contentType)
+	}
+
+	return MediaType{Type: mediaType[:i], Subtype: mediaType[i+1:], Params: params}, nil
+}
+
+// Matches reports whether m matches mediaRange, like "text/html", "text/*" or "*/*".
+// The parameters of mediaRange are ignored.
+func (m MediaType) Matches(mediaRange string) bool {
+	if i := strings.IndexByte(mediaRange, ';'); i >= 0 {
+		mediaRange = mediaRange[:i]
+	}
+	mediaRange = strings.ToLower(strings.TrimSpace(mediaRange))
+
+	switch {
+	case mediaRange == "*/*":
+		return true
+	case strings.HasSuffix(mediaRange, "/*"):
+		return strings.TrimSuffix(mediaRange, "/*") == m.Type
+	default:
+		return mediaRange == m.Type+"/"+m.Subtype
+	}
+}
+
+// WithAccept sends the Accept header built from ranges, and makes the
+// successful responses whose Content-Type matches none of them fail with an
+// *UnacceptableTypeError before their body is read.
+func WithAccept(ranges ...MediaRange) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.setHeader("Accept", AcceptHeader(ranges...))
+		cfg.accept = ranges
+	}
+}
+
+// checkAccept returns an *UnacceptableTypeError if the Content-Type of resp
+// matches none of the ranges accepted by the request.
+func (cfg *requestConfig) checkAccept(resp *http.Response) error {
+	if len(cfg.accept) == 0 {
+		return nil
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if m, err := ParseContentType(contentType); err == nil {
+		for _, r := range cfg.accept {
+			if m.Matches(r.Type) {
+				return nil
+			}
+		}
+	}
+
+	return &UnacceptableTypeError{ContentType: contentType, Accept: AcceptHeader(cfg.accept...)}
+}
@@ -0,0 +1,67 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestAcceptHeader should test that the weights are rendered, omitting the default one.
+func TestAcceptHeader(t *testing.T) {
+	got := AcceptHeader(MediaRange{Type: "application/json"}, MediaRange{Type: "application/yaml", Q: 0.5}, MediaRange{Type: "*/*", Q: 0.1})
+	want := "application/json, application/yaml;q=0.5, */*;q=0.1"
+	if got != want {
+		t.Errorf("TestAcceptHeader was incorrect, got: %q, want: %q.", got, want)
+	}
+}
+
+// TestParseContentType should test that Content-Type headers are split into type, subtype and params.
+func TestParseContentType(t *testing.T) {
+	m, err := ParseContentType("Text/HTML; Charset=ISO-8859-1")
+	if err != nil {
+		t.Fatalf("TestParseContentType was incorrect, got error: %v", err)
+	}
+	if m.Type != "text" || m.Subtype != "html" || m.Params["charset"] != "ISO-8859-1" {
+		t.Errorf("TestParseContentType was incorrect, got: %+v, want: text/html with charset ISO-8859-1.", m)
+	}
+	if !m.Matches("text/*") || !m.Matches("*/*") || m.Matches("application/json") {
+		t.Errorf("TestParseContentType was incorrect, got wrong matches for %+v.", m)
+	}
+
+	if _, err := ParseContentType("text"); err == nil {
+		t.Errorf("TestParseContentType was incorrect, got no error for a type without subtype.")
+	}
+}
+
+// TestWithAccept should test that the Accept header is sent and the unaccepted types fail.
+func TestWithAccept(t *testing.T) {
+	var accept string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept = r.Header.Get("Accept")
+		if r.URL.Path == "/html" {
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		} else {
+			w.Header().Set("Content-Type", "application/yaml")
+		}
+		w.Write([]byte("name: software"))
+	}))
+	defer ts.Close()
+
+	yaml := WithAccept(MediaRange{Type: "application/yaml"}, MediaRange{Type: "text/yaml", Q: 0.9})
+	if _, err := GetURL(ts.URL, nil, yaml); err != nil {
+		t.Errorf("TestWithAccept was incorrect, got error: %v", err)
+	}
+	if accept != "application/yaml, text/yaml;q=0.9" {
+		t.Errorf("TestWithAccept was incorrect, got Accept: %q, want: %q.", accept, "application/yaml, text/yaml;q=0.9")
+	}
+
+	resp, err := GetURL(ts.URL+"/html", nil, yaml)
+	var typeErr *UnacceptableTypeError
+	if !errors.As(err, &typeErr) || typeErr.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("TestWithAccept was incorrect, got error: %v, want: an *UnacceptableTypeError.", err)
+	}
+	if resp.Body != nil {
+		t.Errorf("TestWithAccept was incorrect, got body: %q, want: nil.", resp.Body)
+	}
+}
@@ -0,0 +1,143 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+)
+
+// defaultAsyncWorkers is the default number of workers serving Async.
+const defaultAsyncWorkers = 8
+
+// AsyncRequest is a request for Async.
+type AsyncRequest struct {
+	// Context cancels the request, context.Background() if nil.
+	Context context.Context
+	Method  string
+	URL     string
+	Headers map[string]string
+	Body    []byte
+	Options []RequestOption
+}
+
+// Result is the outcome of an AsyncRequest.
+type Result struct {
+	Request  AsyncRequest
+	Response HTTPResponse
+	Err      error
+}
+
+// asyncPool is the pool of workers serving Async, started by the first request.
+type asyncPool struct {
+	workers int
+	once    sync.Once
+	wake    chan struct{}
+
+	mu      sync.Mutex
+	queue   []asyncJob
+	stopped bool
+}
+
+type asyncJob struct {
+	req    AsyncRequest
+	result chan Result
+}
+
+// WithAsyncWorkers sets the number of the background workers sending the
+// requests of Async. Default is 8.
+func WithAsyncWorkers(n int) Option {
+	return func(c *Client) {
+		c.async.workers = n
+	}
+}
+
+// Async queues req to be sent by the background workers of the Client,
+// see WithAsyncWorkers, and returns the channel receiving its Result.
+// It never blocks: the requests wait in the queue for a free worker, then
+// for the limits of the Client as every request does. The queued requests
+// fail with ErrClientClosed once the Client is closed.
+func (c *Client) Async(req AsyncRequest) <-chan Result {
+	p := &c.async
+	p.once.Do(func() {
+		if p.workers <= 0 {
+			p.workers = defaultAsyncWorkers
+		}
+		p.wake = make(chan struct{}, 1)
+		for i := 0; i < p.workers; i++ {
+			go c.asyncWorker()
+		}
+	})
+
+	result := make(chan Result, 1)
+
+	p.mu.Lock()
+	if p.stopped {
+		p.mu.Unlock()
+		result <- Result{Request: req, Response: HTTPResponse{Status: ResponseStatus{Text: ErrClientClosed.Error(), Code: -1}}, Err: ErrClientClosed}
+		return result
+	}
+	p.queue = append(p.queue, asyncJob{req: req, result: result})
+	p.mu.Unlock()
+
+	p.signal()
+
+	return result
+}
+
+// signal wakes up a waiting worker, if any.
+func (p *asyncPool) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pop returns the next job of the queue, false if the queue is empty.
+// On an empty queue of a closed Client the pool is stopped.
+func (p *asyncPool) pop(closed bool) (asyncJob, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.queue) == 0 {
+		p.stopped = p.stopped || closed
+		return asyncJob{}, false
+	}
+	job := p.queue[0]
+	p.queue[0] = asyncJob{}
+	p.queue = p.queue[1:]
+	if len(p.queue) > 0 {
+		// Let another worker take the next one.
+		p.signal()
+	}
+
+	return job, true
+}
+
+// asyncWorker sends the queued requests until the Client is closed.
+func (c *Client) asyncWorker() {
+	for {
+		job, ok := c.async.pop(c.isClosed())
+		if !ok {
+			if c.isClosed() {
+				return
+			}
+			select {
+			case <-c.async.wake:
+			case <-c.closed:
+			}
+			continue
+		}
+
+		ctx := job.req.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		var body io.Reader
+		if job.req.Body != nil {
+			body = bytes.NewReader(job.req.Body)
+		}
+		resp, err := c.RequestContext(ctx, job.req.URL, job.req.Method, job.req.Headers, body, job.req.Options...)
+		job.result <- Result{Request: job.req, Response: resp, Err: err}
+	}
+}
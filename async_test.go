@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestAsync should test that the requests are sent by the workers, at most one per worker at a time.
+func TestAsync(t *testing.T) {
+	var current, peak int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		w.Write([]byte(r.URL.Path))
+	}))
+	defer ts.Close()
+
+	c := New(WithAsyncWorkers(4))
+	var results []<-chan Result
+	for i := 0; i < 40; i++ {
+		results = append(results, c.Async(AsyncRequest{Method: "GET", URL: fmt.Sprintf("%s/%d", ts.URL, i)}))
+	}
+
+	for i, ch := range results {
+		select {
+		case r := <-ch:
+			if r.Err != nil || string(r.Response.Body) != fmt.Sprintf("/%d", i) {
+				t.Errorf("TestAsync was incorrect, got: %q, %v, want: /%d.", r.Response.Body, r.Err, i)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("TestAsync was incorrect, got no result for the request %d.", i)
+		}
+	}
+	if peak > 4 {
+		t.Errorf("TestAsync was incorrect, got: %d concurrent requests, want: at most 4.", peak)
+	}
+
+	c.Close()
+	r := <-c.Async(AsyncRequest{Method: "GET", URL: ts.URL})
+	if !errors.Is(r.Err, ErrClientClosed) {
+		t.Errorf("TestAsync was incorrect, got error: %v after Close, want: %v.", r.Err, ErrClientClosed)
+	}
+}
@@ -0,0 +1,332 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Challenge is an authentication challenge of a WWW-Authenticate header
+// (RFC 7235), e.g. `Bearer realm="https://auth.docker.io/token"`.
+type Challenge struct {
+	// Scheme is the authentication scheme as sent, e.g. "Bearer".
+	Scheme string
+	// Params are the parameters of the challenge, by lowercased name.
+	Params map[string]string
+	// Token68 is the token sent in place of the parameters by some schemes.
+	Token68 string
+}
+
+// Authenticator answers the authentication challenges of a scheme, see
+// WithAuthenticator. It returns the value of the Authorization header of the
+// request sent again, for method and URL.
+type Authenticator interface {
+	Authenticate(ctx context.Context, ch Challenge, method, URL string) (string, error)
+}
+
+// AuthenticatorFunc is a function used as an Authenticator.
+type AuthenticatorFunc func(ctx context.Context, ch Challenge, method, URL string) (string, error)
+
+// Authenticate calls f(ctx, ch, method, URL).
+func (f AuthenticatorFunc) Authenticate(ctx context.Context, ch Challenge, method, URL string) (string, error) {
+	return f(ctx, ch, method, URL)
+}
+
+// WithAuthenticator registers a to answer the challenges of scheme, e.g.
+// "Basic" or "Bearer". A 401 (Unauthorized) response is answered with the
+// first of its challenges having an Authenticator, sending the request again
+// once, with the Authorization returned. The streamed bodies aren't sent again.
+func WithAuthenticator(scheme string, a Authenticator) Option {
+	return func(c *Client) {
+		if c.authenticators == nil {
+			c.authenticators = make(map[string]Authenticator)
+		}
+		c.authenticators[strings.ToLower(scheme)] = a
+	}
+}
+
+// BasicAuth returns an Authenticator answering with username and password, for
+// the "Basic" scheme.
+func BasicAuth(username, password string) Authenticator {
+	authorization := "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+
+	return AuthenticatorFunc(func(context.Context, Challenge, string, string) (string, error) {
+		return authorization, nil
+	})
+}
+
+// TokenAuthenticator answers the "Bearer" challenges of the container
+// registries (the Docker Registry token authentication), getting a token from
+// the realm of the challenge for its service and scope. The tokens are reused
+// until they expire.
+//
+// As the realm is chosen by the server challenging the request, the username
+// and password are sent to the realms over HTTPS only, unless
+// WithTokenRealmHTTP, and to any host unless WithTokenRealms restricts them.
+// The realms not allowed fail the authentication.
+type TokenAuthenticator struct {
+	client    *Client
+	username  string
+	password  string
+	realms    [][]string
+	allowHTTP bool
+
+	mu     sync.Mutex
+	tokens map[string]registryToken
+}
+
+// TokenOption configures a TokenAuthenticator.
+type TokenOption func(*TokenAuthenticator)
+
+// WithTokenRealms sends the username and password only to the realms whose
+// host matches one of patterns, as by WithHostOverride, e.g. "auth.docker.io"
+// or "*.example.com".
+func WithTokenRealms(patterns ...string) TokenOption {
+	return func(a *TokenAuthenticator) {
+		for _, pattern := range patterns {
+			a.realms = append(a.realms, strings.Split(strings.ToLower(pattern), "."))
+		}
+	}
+}
+
+// WithTokenRealmHTTP sends the username and password to the realms over plain
+// HTTP as well, e.g. to a registry of the local network.
+func WithTokenRealmHTTP() TokenOption {
+	return func(a *TokenAuthenticator) {
+		a.allowHTTP = true
+	}
+}
+
+type registryToken struct {
+	token   string
+	expires time.Time
+}
+
+// NewTokenAuthenticator returns a TokenAuthenticator getting the tokens through
+// c, the default Client if nil, authenticated with username and password if
+// username isn't empty, anonymously otherwise.
+func NewTokenAuthenticator(c *Client, username, password string, opts ...TokenOption) *TokenAuthenticator {
+	if c == nil {
+		c = defaultClient
+	}
+
+	a := &TokenAuthenticator{client: c, username: username, password: password, tokens: make(map[string]registryToken)}
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a
+}
+
+// allowedRealm returns an error if the credentials mustn't be sent to u.
+func (a *TokenAuthenticator) allowedRealm(u *url.URL) error {
+	if u.Scheme != "https" && !a.allowHTTP {
+		return fmt.Errorf("realm of %s not over https", u.Host)
+	}
+	if len(a.realms) == 0 {
+		return nil
+	}
+
+	host := strings.Split(strings.ToLower(strings.TrimSuffix(u.Hostname(), ".")), ".")
+	for _, pattern := range a.realms {
+		if matchHost(pattern, host) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("realm of %s not allowed", u.Host)
+}
+
+// Authenticate returns the Bearer authorization with a token for ch.
+func (a *TokenAuthenticator) Authenticate(ctx context.Context, ch Challenge, _, _ string) (string, error) {
+	realm := ch.Params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("bearer challenge without realm")
+	}
+
+	u, err := url.Parse(realm)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	for _, name := range []string{"service", "scope"} {
+		if v := ch.Params[name]; v != "" {
+			q.Set(name, v)
+		}
+	}
+	u.RawQuery = q.Encode()
+	key := u.String()
+
+	a.mu.Lock()
+	t, ok := a.tokens[key]
+	a.mu.Unlock()
+	if ok && a.client.clock.Now().Before(t.expires) {
+		return "Bearer " + t.token, nil
+	}
+
+	headers := map[string]string{"Accept": "application/json"}
+	if a.username != "" {
+		if err := a.allowedRealm(u); err != nil {
+			return "", err
+		}
+		headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(a.username+":"+a.password))
+	}
+	resp, err := a.client.RequestContext(ctx, key, "GET", headers, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(resp.Body, &body); err != nil {
+		return "", err
+	}
+	token := body.Token
+	if token == "" {
+		token = body.AccessToken
+	}
+	if token == "" {
+		return "", fmt.Errorf("no token from %s", realm)
+	}
+	// The default lifetime of the specification, 60 seconds.
+	if body.ExpiresIn <= 0 {
+		body.ExpiresIn = 60
+	}
+
+	a.mu.Lock()
+	a.tokens[key] = registryToken{token: token, expires: a.client.clock.Now().Add(time.Duration(body.ExpiresIn) * time.Second)}
+	a.mu.Unlock()
+
+	return "Bearer " + token, nil
+}
+
+// authenticator returns the first challenge of resp with an Authenticator of
+// the Client, and the Authenticator, nil if none.
+func (c *Client) authenticator(resp *http.Response) (Challenge, Authenticator) {
+	if len(c.authenticators) == 0 {
+		return Challenge{}, nil
+	}
+
+	for _, ch := range ParseChallenges(resp.Header) {
+		if a := c.authenticators[strings.ToLower(ch.Scheme)]; a != nil {
+			return ch, a
+		}
+	}
+
+	return Challenge{}, nil
+}
+
+// ParseChallenges returns the challenges of the WWW-Authenticate headers of h.
+func ParseChallenges(h http.Header) []Challenge {
+	var challenges []Challenge
+	for _, v := range h.Values("WWW-Authenticate") {
+		challenges = append(challenges, parseChallenges(v)...)
+	}
+
+	return challenges
+}
+
+// parseChallenges parses the comma separated challenges of a WWW-Authenticate
+// header, stopping at the first malformed one.
+func parseChallenges(s string) []Challenge {
+	var challenges []Challenge
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			return challenges
+		}
+		name, rest := splitToken(s)
+		if name == "" {
+			return challenges
+		}
+
+		// An auth-param of the current challenge.
+		if after := strings.TrimLeft(rest, " \t"); strings.HasPrefix(after, "=") {
+			if len(challenges) == 0 {
+				return challenges
+			}
+			value, rest, ok := parseParamValue(strings.TrimLeft(after[1:], " \t"))
+			if !ok {
+				return challenges
+			}
+			challenges[len(challenges)-1].Params[strings.ToLower(name)] = value
+			s = rest
+			continue
+		}
+
+		// A new challenge, possibly with a token68.
+		ch := Challenge{Scheme: name, Params: make(map[string]string)}
+		rest = strings.TrimLeft(rest, " \t")
+		if token, after := splitToken68(rest); token != "" {
+			if after = strings.TrimLeft(after, " \t"); after == "" || after[0] == ',' {
+				ch.Token68, rest = token, after
+			}
+		}
+		challenges = append(challenges, ch)
+		s = rest
+	}
+}
+
+// parseParamValue parses the token or quoted string at the start of s.
+func parseParamValue(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, `"`) {
+		value, rest := splitToken(s)
+		return value, rest, value != ""
+	}
+
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				b.WriteByte(s[i])
+			}
+		case '"':
+			return b.String(), s[i+1:], true
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return "", "", false
+}
+
+// splitToken splits s after the token (RFC 7230) at its start.
+func splitToken(s string) (string, string) {
+	i := 0
+	for i < len(s) && (isAlphaNum(s[i]) || strings.IndexByte("!#$%&'*+-.^_`|~", s[i]) >= 0) {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+// splitToken68 splits s after the token68 (RFC 7235) at its start.
+func splitToken68(s string) (string, string) {
+	i := 0
+	for i < len(s) && (isAlphaNum(s[i]) || strings.IndexByte("-._~+/", s[i]) >= 0) {
+		i++
+	}
+	if i == 0 {
+		return "", s
+	}
+	for i < len(s) && s[i] == '=' {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+func isAlphaNum(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
@@ -0,0 +1,120 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// TestParseChallenges should test the parsing of the WWW-Authenticate headers.
+func TestParseChallenges(t *testing.T) {
+	h := http.Header{}
+	h.Add("WWW-Authenticate", `Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:library/ubuntu:pull"`)
+	h.Add("WWW-Authenticate", `Negotiate YII7bA==, Basic realm="say \"hi\"", charset=UTF-8`)
+
+	got := ParseChallenges(h)
+	want := []Challenge{
+		{Scheme: "Bearer", Params: map[string]string{
+			"realm":   "https://auth.docker.io/token",
+			"service": "registry.docker.io",
+			"scope":   "repository:library/ubuntu:pull",
+		}},
+		{Scheme: "Negotiate", Params: map[string]string{}, Token68: "YII7bA=="},
+		{Scheme: "Basic", Params: map[string]string{"realm": `say "hi"`, "charset": "UTF-8"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TestParseChallenges was incorrect, got: %+v, want: %+v.", got, want)
+	}
+}
+
+// TestTokenAuthenticator should test the token dance of the container registries.
+func TestTokenAuthenticator(t *testing.T) {
+	tokens := 0
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			user, pass, _ := r.BasicAuth()
+			if user != "italia" || pass != "secret" || r.URL.Query().Get("scope") != "repository:library/ubuntu:pull" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			tokens++
+			fmt.Fprint(w, `{"token":"t0k3n","expires_in":300}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer t0k3n" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry",scope="repository:library/ubuntu:pull"`, ts.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "manifest")
+	}))
+	defer ts.Close()
+
+	c := New(WithAuthenticator("bearer", NewTokenAuthenticator(New(), "italia", "secret", WithTokenRealmHTTP(), WithTokenRealms("127.0.0.1"))))
+	for i := 0; i < 2; i++ {
+		resp, err := c.GetURL(ts.URL+"/v2/library/ubuntu/manifests/latest", nil)
+		if err != nil || string(resp.Body) != "manifest" || resp.Attempts != 2 {
+			t.Fatalf("TestTokenAuthenticator was incorrect, got: %q after %d attempts, %v, want: manifest after 2.", resp.Body, resp.Attempts, err)
+		}
+	}
+	if tokens != 1 {
+		t.Errorf("TestTokenAuthenticator was incorrect, got: %d tokens, want: 1.", tokens)
+	}
+}
+
+// TestTokenAuthenticatorRealm should test that the credentials aren't sent to the realms
+// over plain HTTP or to the hosts not allowed.
+func TestTokenAuthenticatorRealm(t *testing.T) {
+	tokens := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokens++
+		fmt.Fprint(w, `{"token":"t0k3n"}`)
+	}))
+	defer ts.Close()
+
+	authenticators := map[string]*TokenAuthenticator{
+		"http":    NewTokenAuthenticator(New(), "italia", "secret"),
+		"realms":  NewTokenAuthenticator(New(), "italia", "secret", WithTokenRealmHTTP(), WithTokenRealms("auth.docker.io")),
+		"pattern": NewTokenAuthenticator(New(), "italia", "secret", WithTokenRealmHTTP(), WithTokenRealms("*.0.0.1")),
+	}
+	for name, a := range authenticators {
+		_, err := a.Authenticate(context.Background(), Challenge{Scheme: "Bearer", Params: map[string]string{"realm": ts.URL + "/token"}}, "GET", ts.URL)
+		if wantErr := name != "pattern"; (err != nil) != wantErr {
+			t.Errorf("TestTokenAuthenticatorRealm was incorrect for %s, got: %v, want error: %v.", name, err, wantErr)
+		}
+	}
+	if tokens != 1 {
+		t.Errorf("TestTokenAuthenticatorRealm was incorrect, got: %d token requests, want: 1.", tokens)
+	}
+}
+
+// TestBasicAuth should test that a wrong answer to a challenge isn't retried again.
+func TestBasicAuth(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if user, pass, _ := r.BasicAuth(); user != "italia" || pass != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="api"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	resp, err := New(WithAuthenticator("Basic", BasicAuth("italia", "secret"))).GetURL(ts.URL, nil)
+	if err != nil || string(resp.Body) != "ok" {
+		t.Errorf("TestBasicAuth was incorrect, got: %q, %v, want: ok.", resp.Body, err)
+	}
+
+	hits = 0
+	_, err = New(WithAuthenticator("Basic", BasicAuth("italia", "wrong"))).GetURL(ts.URL, nil)
+	if !errors.Is(err, ErrInvalidStatus) || hits != 2 {
+		t.Errorf("TestBasicAuth was incorrect, got: %v after %d hits, want: %v after 2.", err, hits, ErrInvalidStatus)
+	}
+}
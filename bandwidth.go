@@ -0,0 +1,121 @@
+package httpclient
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// bandwidthLimiter spaces the bytes transferred to rate bytes per second.
+type bandwidthLimiter struct {
+	rate int64
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newBandwidthLimiter returns a bandwidthLimiter of bytesPerSecond, nil if
+// not positive, as unlimited.
+func newBandwidthLimiter(bytesPerSecond int64) *bandwidthLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+
+	return &bandwidthLimiter{rate: bytesPerSecond}
+}
+
+// reserve reserves the transfer of n bytes, returning how long to wait before
+// they are allowed.
+func (l *bandwidthLimiter) reserve(now time.Time, n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(int64(n) * int64(time.Second) / l.rate))
+
+	return l.next.Sub(now)
+}
+
+// WithBandwidthLimit limits the bodies sent and received by all the requests
+// of the Client, together, to bytesPerSecond. A bytesPerSecond of 0 or less
+// means unlimited.
+func WithBandwidthLimit(bytesPerSecond int64) Option {
+	return func(c *Client) {
+		c.bandwidth = newBandwidthLimiter(bytesPerSecond)
+	}
+}
+
+// WithRequestBandwidthLimit limits the bodies sent and received by the request
+// to bytesPerSecond, within the limit of the Client if any. A bytesPerSecond
+// of 0 or less means unlimited.
+func WithRequestBandwidthLimit(bytesPerSecond int64) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.bandwidth = newBandwidthLimiter(bytesPerSecond)
+	}
+}
+
+// throttledBody is a body read within the rate of its limiters.
+type throttledBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	client   *Client
+	limiters []*bandwidthLimiter
+	// stall is the stall timeout of the body, paused during the waits.
+	stall *stallBody
+}
+
+// throttle returns body throttled by the limiters of the Client and of cfg, if
+// any, pausing the stall timeout of stall, if not nil, while waiting.
+func (c *Client) throttle(ctx context.Context, cfg *requestConfig, body io.ReadCloser, stall *stallBody) io.ReadCloser {
+	var limiters []*bandwidthLimiter
+	for _, l := range []*bandwidthLimiter{cfg.bandwidth, c.bandwidth} {
+		if l != nil {
+			limiters = append(limiters, l)
+		}
+	}
+	if len(limiters) == 0 {
+		return body
+	}
+
+	return &throttledBody{ReadCloser: body, ctx: ctx, client: c, limiters: limiters, stall: stall}
+}
+
+func (b *throttledBody) Read(p []byte) (int, error) {
+	// Read at most a second worth of bytes at a time.
+	for _, l := range b.limiters {
+		if int64(len(p)) > l.rate {
+			p = p[:l.rate]
+		}
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		// Wait for the slowest limiter.
+		now := b.client.clock.Now()
+		var wait time.Duration
+		for _, l := range b.limiters {
+			if d := l.reserve(now, n); d > wait {
+				wait = d
+			}
+		}
+		if wait > 0 {
+			sleep := func() error {
+				return b.client.sleepContext(b.ctx, wait)
+			}
+			var waitErr error
+			if b.stall != nil {
+				waitErr = b.stall.pause(sleep)
+			} else {
+				waitErr = sleep()
+			}
+			if waitErr != nil {
+				return n, waitErr
+			}
+		}
+	}
+
+	return n, err
+}
@@ -0,0 +1,65 @@
+package httpclient
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestBandwidthLimit should test that the bodies are transferred within the bandwidth limits.
+func TestBandwidthLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(make([]byte, 100))
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	c := New(WithClock(clock), WithBandwidthLimit(50))
+	resp, err := c.PostURL(ts.URL, nil, bytes.NewReader(make([]byte, 50)), WithRequestBandwidthLimit(10))
+	if err != nil || len(resp.Body) != 100 {
+		t.Fatalf("TestBandwidthLimit was incorrect, got: %d bytes (%v), want: 100 bytes.", len(resp.Body), err)
+	}
+
+	// 50 bytes at 10 B/s, then 100 bytes at 10 B/s.
+	var slept time.Duration
+	for _, d := range clock.Sleeps() {
+		slept += d
+	}
+	if slept != 15*time.Second {
+		t.Errorf("TestBandwidthLimit was incorrect, got: %v, want: 15s.", slept)
+	}
+}
+
+// TestBandwidthUnlimited should test that the limits of 0 or less don't limit the bodies.
+func TestBandwidthUnlimited(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(make([]byte, 100))
+	}))
+	defer ts.Close()
+
+	for _, rate := range []int64{0, -1} {
+		clock := newTestClock()
+		c := New(WithClock(clock), WithBandwidthLimit(rate))
+		resp, err := c.PostURL(ts.URL, nil, bytes.NewReader(make([]byte, 50)), WithRequestBandwidthLimit(rate))
+		if err != nil || len(resp.Body) != 100 || len(clock.Sleeps()) != 0 {
+			t.Errorf("TestBandwidthUnlimited was incorrect for %d, got: %d bytes (%v), waits %v, want: 100 bytes, no waits.", rate, len(resp.Body), err, clock.Sleeps())
+		}
+	}
+}
+
+// TestBandwidthLimitStallTimeout should test that the waits for the bandwidth limits
+// don't count as stalls of the body.
+func TestBandwidthLimitStallTimeout(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(make([]byte, 30))
+	}))
+	defer ts.Close()
+
+	c := New(WithBandwidthLimit(100), WithMaxRetries(0))
+	resp, err := c.GetURL(ts.URL, nil, WithStallTimeout(50*time.Millisecond))
+	if err != nil || len(resp.Body) != 30 {
+		t.Errorf("TestBandwidthLimitStallTimeout was incorrect, got: %d bytes (%v), want: 30 bytes.", len(resp.Body), err)
+	}
+}
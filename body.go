@@ -0,0 +1,116 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// maxDrainSize is the most read of a body being closed unread, so that its
+// connection can be reused.
+const maxDrainSize = 64 << 10
+
+// ErrBodyTooLarge is returned when a response body exceeds the size set by WithMaxBodySize.
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// ErrTruncatedBody is returned when a response body is shorter than its Content-Length.
+var ErrTruncatedBody = errors.New("truncated response body")
+
+// limitedBody is a response body failing with ErrBodyTooLarge past remaining bytes.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrBodyTooLarge
+	}
+	// Read one more byte than allowed, to tell a body of the exact size from a larger one.
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, ErrBodyTooLarge
+	}
+
+	return n, err
+}
+
+// WithProgress calls fn as the body of the request is sent and as the body of
+// the response is read, with the bytes transferred so far and the total, -1
+// if unknown. It is called for the request body first, if any, then again
+// from zero for the response body.
+func WithProgress(fn func(transferred, total int64)) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.progress = fn
+	}
+}
+
+// progressBody is a body reporting the bytes read to fn.
+type progressBody struct {
+	io.ReadCloser
+	transferred int64
+	total       int64
+	fn          func(transferred, total int64)
+}
+
+func (b *progressBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.transferred += int64(n)
+		b.fn(b.transferred, b.total)
+	}
+
+	return n, err
+}
+
+// WithRetryTruncated retries with the usual backoff the requests whose
+// response body turns out shorter than its Content-Length, even if not
+// idempotent like the requests retried by default.
+func WithRetryTruncated() RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.retryTruncated = true
+	}
+}
+
+// lengthCheckedBody is a response body failing with ErrTruncatedBody if it
+// ends before its Content-Length, which transports don't always report.
+type lengthCheckedBody struct {
+	io.ReadCloser
+	read   int64
+	length int64
+}
+
+// checkLength returns the body of resp to a request with method checked
+// against its Content-Length, if any.
+func checkLength(resp *http.Response, method string) io.ReadCloser {
+	// The Content-Length of a decompressed body is the one of the compressed body.
+	if resp.ContentLength < 0 || resp.Uncompressed || method == "HEAD" {
+		return resp.Body
+	}
+
+	return &lengthCheckedBody{ReadCloser: resp.Body, length: resp.ContentLength}
+}
+
+func (b *lengthCheckedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if (err == io.EOF && b.read < b.length) || err == io.ErrUnexpectedEOF {
+		return n, fmt.Errorf("%w: read %d of %d bytes", ErrTruncatedBody, b.read, b.length)
+	}
+
+	return n, err
+}
+
+// drainAndClose reads what's left of body, up to maxDrainSize, and closes it,
+// so that the transport reuses its connection.
+func drainAndClose(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, io.LimitReader(body, maxDrainSize))
+	body.Close()
+}
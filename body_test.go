@@ -0,0 +1,156 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithProgress should test that the progress of the request and response bodies is reported.
+func TestWithProgress(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Length", "8")
+		w.Write([]byte("response"))
+	}))
+	defer ts.Close()
+
+	type progress struct{ transferred, total int64 }
+	var got []progress
+	_, err := PostURL(ts.URL, nil, strings.NewReader("request body"), WithProgress(func(transferred, total int64) {
+		got = append(got, progress{transferred, total})
+	}))
+	if err != nil {
+		t.Fatalf("TestWithProgress was incorrect, got error: %v", err)
+	}
+
+	if len(got) < 2 || got[0] != (progress{12, 12}) || got[len(got)-1] != (progress{8, 8}) {
+		t.Errorf("TestWithProgress was incorrect, got: %v, want: {12 12} first and {8 8} last.", got)
+	}
+}
+
+// TestTruncatedBody should test that a body shorter than its Content-Length is an error, retried with WithRetryTruncated.
+func TestTruncatedBody(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		w.Header().Set("Content-Length", "10")
+		if hits == 1 {
+			// Close the connection after half the body.
+			w.Write([]byte("trunc"))
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Write([]byte("0123456789"))
+	}))
+	defer ts.Close()
+
+	if _, err := PostURL(ts.URL, nil, nil); !errors.Is(err, ErrTruncatedBody) {
+		t.Errorf("TestTruncatedBody was incorrect, got: %v, want: %v.", err, ErrTruncatedBody)
+	}
+
+	hits = 0
+	resp, err := New(WithClock(newTestClock())).PostURL(ts.URL, nil, nil, WithRetryTruncated())
+	if err != nil || string(resp.Body) != "0123456789" || resp.Attempts != 2 {
+		t.Errorf("TestTruncatedBody was incorrect, got: %q in %d attempts (%v), want: the full body.", resp.Body, resp.Attempts, err)
+	}
+}
+
+// TestBodyReadRetry should test that the idempotent requests are retried when reading the body fails.
+func TestBodyReadRetry(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if hits++; hits == 1 {
+			// Reset the connection in the middle of a chunked body.
+			w.Write([]byte("partial"))
+			w.(http.Flusher).Flush()
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+		w.Write([]byte("complete"))
+	}))
+	defer ts.Close()
+
+	resp, err := New(WithClock(newTestClock())).GetURL(ts.URL, nil)
+	if err != nil || string(resp.Body) != "complete" || resp.Attempts != 2 {
+		t.Errorf("TestBodyReadRetry was incorrect, got: %q in %d attempts (%v), want: \"complete\".", resp.Body, resp.Attempts, err)
+	}
+}
+
+// TestRetryConnectionReuse should test that the bodies of the retried attempts
+// are drained and closed, reusing the connection, instead of piling up until
+// the return of the request.
+func TestRetryConnectionReuse(t *testing.T) {
+	hits := 0
+	var remotes []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remotes = append(remotes, r.RemoteAddr)
+		if r.Method == "HEAD" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if hits++; hits < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write(bytes.Repeat([]byte("slow down "), 1000))
+			return
+		}
+	}))
+	defer ts.Close()
+
+	// A single slot per host: an attempt holding its body open would block the next one.
+	c := New(WithClock(newTestClock()), WithMaxInflightPerHost(1))
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Head(ts.URL, nil)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("TestRetryConnectionReuse was incorrect, got error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("TestRetryConnectionReuse was incorrect, the retries are blocked by the bodies left open.")
+	}
+
+	for _, remote := range remotes {
+		if remote != remotes[0] {
+			t.Errorf("TestRetryConnectionReuse was incorrect, got connections: %v, want: a single one.", remotes)
+			break
+		}
+	}
+}
+
+// TestResponseWithoutRequest should test the responses of a transport not setting their Request.
+func TestResponseWithoutRequest(t *testing.T) {
+	hits := 0
+	rt := SchemeHandlerFunc(func(req *http.Request) (*http.Response, error) {
+		hits++
+		resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: ioutil.NopCloser(strings.NewReader("ok")), ContentLength: 2}
+		switch hits {
+		case 1:
+			resp.StatusCode = http.StatusForbidden
+			resp.Header.Set("Retry-After", "1")
+			resp.Body = ioutil.NopCloser(strings.NewReader("You have exceeded a secondary rate limit"))
+			resp.ContentLength = -1
+		case 2:
+			resp.StatusCode = http.StatusTooManyRequests
+			resp.Header.Set("X-RateLimit-Remaining", "0")
+			resp.Header.Set("Retry-After", "1")
+		}
+		return resp, nil
+	})
+
+	resp, err := New(WithTransport(rt), WithClock(newTestClock())).GetURL("http://example.org/", nil)
+	if err != nil || string(resp.Body) != "ok" || hits != 3 {
+		t.Errorf("TestResponseWithoutRequest was incorrect, got: %q, %v, %d hits, want: ok after 3 hits.", resp.Body, err, hits)
+	}
+}
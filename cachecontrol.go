@@ -0,0 +1,70 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheControl are the directives of a Cache-Control header.
+type CacheControl struct {
+	// MaxAge is the max-age directive, -1 if missing.
+	MaxAge         time.Duration
+	NoCache        bool
+	NoStore        bool
+	Private        bool
+	Public         bool
+	MustRevalidate bool
+	// Directives are all the directives, the ones without value mapped to "".
+	Directives map[string]string
+}
+
+// ParseCacheControl parses the value of a Cache-Control header.
+func ParseCacheControl(value string) CacheControl {
+	cc := CacheControl{MaxAge: -1}
+	for _, directive := range strings.Split(value, ",") {
+		directive = strings.TrimSpace(directive)
+		if directive == "" {
+			continue
+		}
+		name, arg := directive, ""
+		if i := strings.IndexByte(directive, '='); i >= 0 {
+			name, arg = directive[:i], strings.Trim(directive[i+1:], `"`)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if cc.Directives == nil {
+			cc.Directives = make(map[string]string)
+		}
+		cc.Directives[name] = arg
+
+		switch name {
+		case "max-age":
+			if seconds, err := strconv.Atoi(arg); err == nil {
+				cc.MaxAge = time.Duration(seconds) * time.Second
+			}
+		case "no-cache":
+			cc.NoCache = true
+		case "no-store":
+			cc.NoStore = true
+		case "private":
+			cc.Private = true
+		case "public":
+			cc.Public = true
+		case "must-revalidate":
+			cc.MustRevalidate = true
+		}
+	}
+
+	return cc
+}
+
+// setCacheValidators sets on r the cache validators parsed from h.
+// Invalid dates are left to the zero value.
+func (r *HTTPResponse) setCacheValidators(h http.Header) {
+	r.ETag = h.Get("ETag")
+	r.LastModified, _ = http.ParseTime(h.Get("Last-Modified"))
+	r.Expires, _ = http.ParseTime(h.Get("Expires"))
+	r.CacheControl = ParseCacheControl(h.Get("Cache-Control"))
+}
@@ -0,0 +1,39 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestCacheValidators should test the cache validators parsed from the response headers.
+func TestCacheValidators(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("ETag", `W/"abc"`)
+		w.Header().Set("Last-Modified", "Wed, 21 Oct 2015 07:28:00 GMT")
+		w.Header().Set("Expires", "not a date")
+		w.Header().Set("Cache-Control", `private, max-age=60, must-revalidate, community="ucsc"`)
+	}))
+	defer ts.Close()
+
+	resp, err := GetURL(ts.URL, nil)
+	if err != nil {
+		t.Fatalf("TestCacheValidators was incorrect, got error: %v", err)
+	}
+
+	if resp.ETag != `W/"abc"` || !resp.LastModified.Equal(time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC)) || !resp.Expires.IsZero() {
+		t.Errorf("TestCacheValidators was incorrect, got: %s %v %v.", resp.ETag, resp.LastModified, resp.Expires)
+	}
+	cc := resp.CacheControl
+	if cc.MaxAge != time.Minute || !cc.Private || !cc.MustRevalidate || cc.NoStore || cc.Directives["community"] != "ucsc" {
+		t.Errorf("TestCacheValidators was incorrect, got: %+v.", cc)
+	}
+}
+
+// TestParseCacheControl should test that a missing max-age is -1.
+func TestParseCacheControl(t *testing.T) {
+	if cc := ParseCacheControl("no-store"); cc.MaxAge != -1 || !cc.NoStore {
+		t.Errorf("TestParseCacheControl was incorrect, got: %+v, want: no-store without max-age.", cc)
+	}
+}
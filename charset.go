@@ -0,0 +1,144 @@
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// charsetSniffLen is the number of bytes of the body searched for charset hints.
+const charsetSniffLen = 1024
+
+var (
+	metaCharset = regexp.MustCompile(`(?i)<meta[^>]+charset\s*=\s*["']?\s*([a-z0-9_.:-]+)`)
+	xmlEncoding = regexp.MustCompile(`(?i)<\?xml[^>]+encoding\s*=\s*["']([a-z0-9_.:-]+)`)
+)
+
+// cp1252 maps the bytes 0x80-0x9F of Windows-1252 to runes. Undefined bytes map to themselves.
+var cp1252 = [32]rune{
+	'€', 0x81, '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', 0x8D, 'Ž', 0x8F,
+	0x90, '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', 0x9D, 'ž', 'Ÿ',
+}
+
+// iso885915 maps the bytes of ISO-8859-15 differing from ISO-8859-1.
+var iso885915 = map[byte]rune{
+	0xA4: '€', 0xA6: 'Š', 0xA8: 'š', 0xB4: 'Ž', 0xB8: 'ž', 0xBC: 'Œ', 0xBD: 'œ', 0xBE: 'Ÿ',
+}
+
+// WithUTF8 transcodes the body of the response to UTF-8, detecting its charset
+// as DetectCharset does.
+func WithUTF8() RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.utf8 = true
+	}
+}
+
+// DetectCharset returns the lowercased charset of body, detected from its
+// byte order mark, the charset parameter of contentType, or the meta and
+// XML declarations at the beginning of the body, in this order.
+// It returns "utf-8" when there are no hints.
+func DetectCharset(body []byte, contentType string) string {
+	switch {
+	case bytes.HasPrefix(body, []byte{0xEF, 0xBB, 0xBF}):
+		return "utf-8"
+	case bytes.HasPrefix(body, []byte{0xFF, 0xFE}):
+		return "utf-16le"
+	case bytes.HasPrefix(body, []byte{0xFE, 0xFF}):
+		return "utf-16be"
+	}
+
+	if _, params, err := mime.ParseMediaType(contentType); err == nil && params["charset"] != "" {
+		return strings.ToLower(params["charset"])
+	}
+
+	head := body
+	if len(head) > charsetSniffLen {
+		head = head[:charsetSniffLen]
+	}
+	for _, re := range []*regexp.Regexp{metaCharset, xmlEncoding} {
+		if m := re.FindSubmatch(head); m != nil {
+			return strings.ToLower(string(m[1]))
+		}
+	}
+
+	return "utf-8"
+}
+
+// ToUTF8 transcodes body to UTF-8 from the charset detected by DetectCharset.
+// Bodies declared as UTF-8 which are not valid UTF-8 are decoded as Windows-1252,
+// since files served as UTF-8 by forges often are ISO-8859-1 instead.
+func ToUTF8(body []byte, contentType string) ([]byte, error) {
+	switch charset := DetectCharset(body, contentType); charset {
+	case "utf-8", "utf8", "us-ascii", "ascii":
+		body = bytes.TrimPrefix(body, []byte{0xEF, 0xBB, 0xBF})
+		if utf8.Valid(body) {
+			return body, nil
+		}
+		return decodeSingleByte(body, cp1252Rune), nil
+	case "iso-8859-1", "latin1", "l1", "iso8859-1", "iso_8859-1":
+		return decodeSingleByte(body, func(b byte) rune { return rune(b) }), nil
+	case "windows-1252", "cp1252":
+		return decodeSingleByte(body, cp1252Rune), nil
+	case "iso-8859-15", "latin-9", "iso8859-15", "iso_8859-15":
+		return decodeSingleByte(body, func(b byte) rune {
+			if r, ok := iso885915[b]; ok {
+				return r
+			}
+			return rune(b)
+		}), nil
+	case "utf-16le", "utf-16be", "utf-16":
+		// UTF-16 without a byte order mark is big endian (RFC 2781).
+		return decodeUTF16(body, charset != "utf-16le"), nil
+	default:
+		return body, fmt.Errorf("unsupported charset %s", charset)
+	}
+}
+
+func cp1252Rune(b byte) rune {
+	if b >= 0x80 && b <= 0x9F {
+		return cp1252[b-0x80]
+	}
+
+	return rune(b)
+}
+
+// decodeSingleByte decodes body mapping each byte to a rune with toRune.
+func decodeSingleByte(body []byte, toRune func(byte) rune) []byte {
+	var buf bytes.Buffer
+	buf.Grow(len(body))
+	for _, b := range body {
+		if b < utf8.RuneSelf {
+			buf.WriteByte(b)
+			continue
+		}
+		buf.WriteRune(toRune(b))
+	}
+
+	return buf.Bytes()
+}
+
+// decodeUTF16 decodes body from UTF-16, big endian if bigEndian, unless told
+// otherwise by the byte order mark.
+func decodeUTF16(body []byte, bigEndian bool) []byte {
+	switch {
+	case bytes.HasPrefix(body, []byte{0xFF, 0xFE}):
+		body, bigEndian = body[2:], false
+	case bytes.HasPrefix(body, []byte{0xFE, 0xFF}):
+		body, bigEndian = body[2:], true
+	}
+
+	units := make([]uint16, len(body)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(body[2*i])<<8 | uint16(body[2*i+1])
+		} else {
+			units[i] = uint16(body[2*i+1])<<8 | uint16(body[2*i])
+		}
+	}
+
+	return []byte(string(utf16.Decode(units)))
+}
@@ -0,0 +1,50 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestToUTF8 should test the transcoding of the supported charsets.
+func TestToUTF8(t *testing.T) {
+	tests := []struct {
+		body        []byte
+		contentType string
+		want        string
+	}{
+		{[]byte("città"), "text/plain; charset=utf-8", "città"},
+		{[]byte{'c', 'i', 't', 't', 0xE0}, "text/plain; charset=ISO-8859-1", "città"},
+		{[]byte{'c', 'i', 't', 't', 0xE0}, "text/plain; charset=utf-8", "città"},
+		{[]byte{0x80, ' ', '1'}, "text/html; charset=windows-1252", "€ 1"},
+		{[]byte{0xA4, ' ', '1'}, "text/plain; charset=iso-8859-15", "€ 1"},
+		{append([]byte("<html><head><meta charset=\"iso-8859-1\">"), 0xE8), "text/html", "<html><head><meta charset=\"iso-8859-1\">è"},
+		{append([]byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><a>"), 0xE8), "", "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><a>è"},
+		{[]byte{0xEF, 0xBB, 0xBF, 'o', 'k'}, "", "ok"},
+		{[]byte{0xFF, 0xFE, 'o', 0, 'k', 0}, "", "ok"},
+		{[]byte{0, 'o', 0, 'k'}, "text/plain; charset=utf-16", "ok"},
+		{[]byte{'o', 0, 'k', 0}, "text/plain; charset=utf-16le", "ok"},
+		{[]byte{0xFF, 0xFE, 'o', 0, 'k', 0}, "text/plain; charset=utf-16", "ok"},
+	}
+
+	for _, test := range tests {
+		got, err := ToUTF8(test.body, test.contentType)
+		if err != nil || string(got) != test.want {
+			t.Errorf("TestToUTF8 was incorrect, got: %q (%v), want: %q.", got, err, test.want)
+		}
+	}
+}
+
+// TestWithUTF8 should test that the body of a response is transcoded on request.
+func TestWithUTF8(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=ISO-8859-1")
+		w.Write([]byte{'n', 'a', 'm', 'e', ':', ' ', 'c', 'i', 't', 't', 0xE0})
+	}))
+	defer ts.Close()
+
+	resp, err := GetURL(ts.URL, nil, WithUTF8())
+	if err != nil || string(resp.Body) != "name: città" {
+		t.Errorf("TestWithUTF8 was incorrect, got: %q (%v), want: %q.", resp.Body, err, "name: città")
+	}
+}
@@ -0,0 +1,409 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"expvar"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Client performs HTTP requests, slowing them down when the server asks to.
+// A Client is safe for concurrent use by multiple goroutines.
+type Client struct {
+	httpClient *http.Client
+	transport  http.RoundTripper
+	clock      Clock
+
+	maxInflightPerHost int
+	dnsCache           *dnsCache
+	dnsLookup          DNSLookupFunc
+	robots             *robots
+	errorBodyExcerpt   int
+	minBackoff         time.Duration
+	maxBackoff         time.Duration
+	headers            http.Header
+	token              string
+	proxy              *url.URL
+	maxRetries         int
+	bandwidth          *bandwidthLimiter
+	fallbackDelay      time.Duration
+	network            string
+	dial               func(ctx context.Context, network, addr string) (net.Conn, error)
+	localAddr          *net.TCPAddr
+	localInterface     string
+	conns              connTracker
+	tlsServerName      string
+	ssrfGuard          bool
+	disableKeepAlives  bool
+	timeouts           *Timeouts
+	concurrency        *prioritySemaphore
+	pauseFailFast      bool
+	politeness         *politeness
+
+	statusHandlers  map[int]StatusHandler
+	transformers    []ResponseTransformer
+	reqTransformers []RequestTransformer
+	quotaParsers    []QuotaParser
+	schemes         map[string]http.RoundTripper
+	authenticators  map[string]Authenticator
+	onDeprecation   func(resp HTTPResponse)
+	notFound        *negativeCache
+	overrides       []hostOverride
+	credentials     []hostCredential
+
+	onSecondaryRateLimit func(host string, wait time.Duration)
+	secondaryRateLimits  int64
+
+	// closed is closed by Close, stopping the background goroutines.
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	// lifeMu guards the tracking of the requests in flight, see begin.
+	lifeMu      sync.Mutex
+	inflight    sync.WaitGroup
+	cancels     map[uint64]context.CancelFunc
+	nextRequest uint64
+
+	// resumed is closed by Resume, nil if the Client isn't paused.
+	pauseMu sync.Mutex
+	resumed chan struct{}
+
+	rateLimitsMu sync.Mutex
+	rateLimits   map[string]rateLimitEntry
+	// rateLimitsSwept is the time of the last sweep of rateLimits.
+	rateLimitsSwept time.Time
+
+	stats              stats
+	degradedErrorRate  float64
+	unhealthyErrorRate float64
+
+	expectContinue        int64
+	expectContinueTimeout time.Duration
+
+	logger  Logger
+	metrics MetricsRecorder
+	expvars *expvar.Map
+
+	traceContext    bool
+	failureReporter func(ctx context.Context, r FailureReport)
+
+	async asyncPool
+
+	// debugDump receives the dumps of the requests, see SetDebugDump.
+	debugMu   sync.Mutex
+	debugDump *lockedWriter
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// RequestOption configures a single request.
+type RequestOption func(*requestConfig)
+
+// requestConfig holds the settings of a single request.
+type requestConfig struct {
+	dump    io.Writer
+	timeout time.Duration
+	// returnStatus reports the statuses returned as they are to the caller
+	// handling them, without being retried or turned into errors.
+	returnStatus  func(code int) bool
+	utf8          bool
+	maxBodySize   int64
+	errorOnNon2xx bool
+	header        http.Header
+	trailers      []requestTrailer
+	// onChunk is set for the streamed bodies, see UploadStream.
+	onChunk   func(n int, sent int64)
+	progress  func(transferred, total int64)
+	bandwidth *bandwidthLimiter
+	// sink receives the body of a successful response, see GetToWriter.
+	sink     io.Writer
+	checksum *checksum
+	mirrors  []string
+	// failFast returns the retryable statuses without waiting and retrying.
+	failFast     bool
+	headFallback bool
+	hedgeDelay   time.Duration
+	priority     Priority
+	// retryTruncated retries the bodies failing with ErrTruncatedBody.
+	retryTruncated bool
+	sniffer        *sniffer
+	accept         []MediaRange
+	soft404        func(resp HTTPResponse) bool
+	watchInterval  time.Duration
+	stallTimeout   time.Duration
+	// host is the Host header sent, see WithHost.
+	host string
+	// idempotent marks as idempotent a request whose method isn't, see PatchJSON.
+	idempotent bool
+	// inner marks the requests sent for a mirrored or hedged one, which emits
+	// their events and reports their failure once, see requestOuter.
+	inner bool
+}
+
+// newRequestConfig returns the requestConfig resulting from opts.
+func newRequestConfig(opts []RequestOption) *requestConfig {
+	cfg := &requestConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return cfg
+}
+
+// complete applies the settings of the request to a response being returned.
+func (cfg *requestConfig) complete(resp HTTPResponse, err error) (HTTPResponse, error) {
+	if err != nil {
+		return resp, err
+	}
+
+	if cfg.utf8 && resp.Body != nil {
+		body, err := ToUTF8(resp.Body, resp.Headers.Get("Content-Type"))
+		if err != nil {
+			return resp, err
+		}
+		resp.Body = body
+	}
+
+	return resp, nil
+}
+
+// retryBody reports whether the request is retried after reading the body
+// failed with err: the idempotent requests are retried, like the truncated
+// bodies with WithRetryTruncated, unless ctx is done or the body is too large.
+func (cfg *requestConfig) retryBody(ctx context.Context, method string, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, ErrBodyTooLarge) {
+		return false
+	}
+	if cfg.retryTruncated && errors.Is(err, ErrTruncatedBody) {
+		return true
+	}
+
+	return cfg.idempotent || idempotent(method)
+}
+
+// idempotent reports whether the requests with method can be safely repeated.
+func idempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS", "TRACE", "PUT", "DELETE":
+		return true
+	}
+
+	return false
+}
+
+// WithMaxBodySize limits to n bytes the body read from the response:
+// reading a larger body fails with ErrBodyTooLarge.
+func WithMaxBodySize(n int64) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.maxBodySize = n
+	}
+}
+
+// WithTimeout sets the timeout of each attempt of the request, reading the body
+// included, in place of the one of the Client. The waits between the attempts
+// are not counted.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.timeout = d
+	}
+}
+
+// defaultClient is used by the package level functions.
+var defaultClient = New()
+
+// New returns a Client configured with the given options.
+func New(opts ...Option) *Client {
+	const timeout = 60 * time.Second
+
+	c := &Client{
+		clock:              realClock{},
+		errorBodyExcerpt:   defaultErrorBodyExcerpt,
+		closed:             make(chan struct{}),
+		cancels:            make(map[uint64]context.CancelFunc),
+		degradedErrorRate:  defaultDegradedErrorRate,
+		unhealthyErrorRate: defaultUnhealthyErrorRate,
+		maxRetries:         -1,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	// The delays of the overrides space the requests as the politeness does.
+	for _, o := range c.overrides {
+		if o.Delay > 0 && c.politeness == nil {
+			c.politeness = &politeness{next: make(map[string]time.Time)}
+			break
+		}
+	}
+
+	dialer := &net.Dialer{
+		Timeout:       30 * time.Second,
+		KeepAlive:     30 * time.Second,
+		FallbackDelay: c.fallbackDelay,
+	}
+	if c.timeouts != nil && c.timeouts.Dial > 0 {
+		dialer.Timeout = c.timeouts.Dial
+	}
+
+	if c.localAddr != nil {
+		dialer.LocalAddr = c.localAddr
+	}
+	if c.ssrfGuard {
+		dialer.Control = ssrfControl
+	}
+	var dialErr error
+	if c.localInterface != "" {
+		if addr, err := interfaceAddr(c.localInterface, c.network); err == nil {
+			dialer.LocalAddr = addr
+		} else {
+			dialErr = err
+		}
+	}
+
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.DialContext = dialer.DialContext
+	if dialErr != nil {
+		base.DialContext = failingDial(dialErr)
+	}
+	if c.dial != nil {
+		base.DialContext = c.dial
+	}
+	if c.expectContinueTimeout > 0 {
+		base.ExpectContinueTimeout = c.expectContinueTimeout
+	}
+	if c.dnsCache != nil {
+		if c.dnsLookup != nil {
+			c.dnsCache.lookup = c.dnsLookup
+		}
+		c.dnsCache.clock = c.clock
+		base.DialContext = c.dnsCache.dialContext(base.DialContext)
+	}
+	if c.network != "" {
+		base.DialContext = dialNetwork(c.network, base.DialContext)
+	}
+	if c.conns.max > 0 {
+		base.MaxIdleConns = c.conns.max
+		base.MaxIdleConnsPerHost = c.conns.max
+	}
+	c.conns.clock = c.clock
+	c.conns.closeIdle = base.CloseIdleConnections
+	base.DialContext = c.conns.dialContext(base.DialContext)
+	base.DisableKeepAlives = c.disableKeepAlives
+	if c.proxy != nil {
+		base.Proxy = http.ProxyURL(c.proxy)
+	}
+	if c.ssrfGuard && base.Proxy != nil {
+		resolve := resolveIP
+		if c.dnsCache != nil {
+			resolve = c.dnsCache.resolve
+		}
+		base.Proxy = ssrfProxy(base.Proxy, resolve)
+	}
+	if c.tlsServerName != "" {
+		base.TLSClientConfig = &tls.Config{ServerName: c.tlsServerName}
+	}
+	if c.timeouts != nil {
+		if c.timeouts.TLSHandshake > 0 {
+			base.TLSHandshakeTimeout = c.timeouts.TLSHandshake
+		}
+		if c.timeouts.ResponseHeader > 0 {
+			base.ResponseHeaderTimeout = c.timeouts.ResponseHeader
+		}
+		if c.timeouts.IdleConn > 0 {
+			base.IdleConnTimeout = c.timeouts.IdleConn
+		}
+	}
+
+	var transport http.RoundTripper = base
+	if c.transport != nil {
+		transport = c.transport
+	}
+	if c.maxInflightPerHost > 0 {
+		transport = newInflightTransport(transport, c.maxInflightPerHost)
+	}
+	if len(c.credentials) > 0 {
+		transport = &credentialsTransport{next: transport, credentials: c.credentials}
+	}
+	if c.notFound != nil {
+		c.notFound.clock = c.clock
+		transport = &negativeCacheTransport{next: transport, cache: c.notFound}
+	}
+	if c.schemes != nil {
+		transport = &schemeTransport{base: transport, schemes: c.schemes}
+	}
+
+	c.httpClient = &http.Client{
+		// Request Timeout.
+		Timeout:   timeout,
+		Transport: transport,
+	}
+	if c.timeouts != nil {
+		c.httpClient.Timeout = c.timeouts.Total
+	}
+	if c.schemes != nil {
+		c.httpClient.CheckRedirect = c.checkRedirect
+	}
+
+	return c
+}
+
+// WithTransport sets the http.RoundTripper performing the requests in place of
+// the default transport, e.g. a recorder in tests.
+// Options configuring the default transport, like WithDNSCache, are ignored.
+func WithTransport(rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = rt
+	}
+}
+
+// WithDisableKeepAlives closes the connection after each request, sending
+// "Connection: close", for the crawls touching each host once, where idle
+// connections only hold file descriptors.
+func WithDisableKeepAlives() Option {
+	return func(c *Client) {
+		c.disableKeepAlives = true
+	}
+}
+
+// WithProxy sends the requests through the proxy at proxyURL, in place of
+// the one of the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables.
+func WithProxy(proxyURL *url.URL) Option {
+	return func(c *Client) {
+		c.proxy = proxyURL
+	}
+}
+
+// WithErrorBodyExcerpt sets to n bytes the size of the body excerpt carried
+// by the HTTPError of unsuccessful responses. Default is 1 KB.
+func WithErrorBodyExcerpt(n int) Option {
+	return func(c *Client) {
+		c.errorBodyExcerpt = n
+	}
+}
+
+// WithMaxInflightPerHost limits to n the requests simultaneously outstanding
+// to the same host. A request is outstanding until its response body is closed.
+func WithMaxInflightPerHost(n int) Option {
+	return func(c *Client) {
+		c.maxInflightPerHost = n
+	}
+}
+
+// GetURL retrieves data, status and response headers from an URL.
+// It uses some technique to slow down the requests if it get a 429 (Too Many Requests) response.
+func (c *Client) GetURL(URL string, headers map[string]string, opts ...RequestOption) (HTTPResponse, error) {
+	return c.Request(URL, "GET", headers, nil, opts...)
+}
+
+// PostURL retrieves data, status and response headers from an URL.
+// It uses some technique to slow down the requests if it get a 429 (Too Many Requests) response.
+func (c *Client) PostURL(URL string, headers map[string]string, body io.Reader, opts ...RequestOption) (HTTPResponse, error) {
+	return c.Request(URL, "POST", headers, body, opts...)
+}
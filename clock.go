@@ -0,0 +1,48 @@
+package httpclient
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and waits for the Client, so that tests of the
+// backoff logic can run without sleeping for real.
+type Clock interface {
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time
+	// on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// realClock is the Clock backed by the time package.
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// WithClock sets the Clock used by the Client in place of the time package.
+func WithClock(clock Clock) Option {
+	return func(c *Client) {
+		c.clock = clock
+	}
+}
+
+// sleep pauses the current goroutine for at least the duration d.
+func (c *Client) sleep(d time.Duration) {
+	<-c.clock.After(d)
+}
+
+// sleepContext is like sleep, but returns ctx.Err() as soon as ctx is done.
+func (c *Client) sleepContext(ctx context.Context, d time.Duration) error {
+	select {
+	case <-c.clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,104 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrClientClosed is returned by the requests of a closed Client.
+var ErrClientClosed = errors.New("client closed")
+
+// trackedKey is the context key marking the requests already tracked by begin,
+// e.g. the attempts on the mirrors of a request.
+type trackedKey struct{}
+
+// Close closes the idle connections of the Client and stops its background
+// goroutines. Further requests fail with ErrClientClosed, while the ones in
+// flight complete.
+func (c *Client) Close() error {
+	c.stop()
+	c.httpClient.CloseIdleConnections()
+
+	return nil
+}
+
+// Shutdown stops accepting requests, failing them with ErrClientClosed like
+// Close, and waits for the requests in flight to complete, retries included.
+// If ctx is done first, the requests still in flight are canceled and
+// ctx.Err() is returned.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.stop()
+
+	drained := make(chan struct{})
+	go func() {
+		c.inflight.Wait()
+		close(drained)
+	}()
+
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+		c.lifeMu.Lock()
+		for _, cancel := range c.cancels {
+			cancel()
+		}
+		c.lifeMu.Unlock()
+		<-drained
+	}
+	c.httpClient.CloseIdleConnections()
+
+	return err
+}
+
+// stop makes the Client refuse new requests.
+func (c *Client) stop() {
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+}
+
+// isClosed reports whether the Client has been closed.
+func (c *Client) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// begin tracks a request until the returned function is called, returning
+// the context canceled by Shutdown or ErrClientClosed if the Client is closed.
+func (c *Client) begin(ctx context.Context) (context.Context, func(), error) {
+	if ctx.Value(trackedKey{}) != nil {
+		return ctx, func() {}, nil
+	}
+
+	c.lifeMu.Lock()
+	defer c.lifeMu.Unlock()
+
+	if c.isClosed() {
+		return ctx, nil, ErrClientClosed
+	}
+
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, trackedKey{}, true))
+	c.nextRequest++
+	id := c.nextRequest
+	c.cancels[id] = cancel
+	c.inflight.Add(1)
+
+	end := func() {
+		c.lifeMu.Lock()
+		delete(c.cancels, id)
+		c.lifeMu.Unlock()
+		cancel()
+		c.inflight.Done()
+	}
+
+	return ctx, end, nil
+}
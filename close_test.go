@@ -0,0 +1,123 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestClose should test that a closed Client fails the requests.
+func TestClose(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerOneRepoList))
+	defer ts.Close()
+
+	c := New()
+	if _, err := c.GetURL(ts.URL, nil); err != nil {
+		t.Fatalf("TestClose was incorrect, got error: %v", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("TestClose was incorrect, got: %v, want: <nil>.", err)
+	}
+	c.Close()
+
+	if _, err := c.GetURL(ts.URL, nil); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("TestClose was incorrect, got: %v, want: %v.", err, ErrClientClosed)
+	}
+}
+
+// TestShutdown should test that Shutdown waits for the requests in flight.
+func TestShutdown(t *testing.T) {
+	started := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, "done")
+	}))
+	defer ts.Close()
+
+	c := New()
+	result := make(chan error, 1)
+	go func() {
+		resp, err := c.GetURL(ts.URL, nil)
+		if err == nil && string(resp.Body) != "done" {
+			err = fmt.Errorf("body %q", resp.Body)
+		}
+		result <- err
+	}()
+	<-started
+
+	if err := c.Shutdown(context.Background()); err != nil {
+		t.Errorf("TestShutdown was incorrect, got: %v, want: <nil>.", err)
+	}
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Errorf("TestShutdown was incorrect, got: %v, want: the request completed.", err)
+		}
+	default:
+		t.Errorf("TestShutdown was incorrect, returned before the request completed.")
+	}
+	if _, err := c.GetURL(ts.URL, nil); !errors.Is(err, ErrClientClosed) {
+		t.Errorf("TestShutdown was incorrect, got: %v, want: %v.", err, ErrClientClosed)
+	}
+}
+
+// TestShutdownDeadline should test that the requests still in flight at the deadline are canceled.
+func TestShutdownDeadline(t *testing.T) {
+	started := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-r.Context().Done()
+	}))
+	defer ts.Close()
+
+	c := New()
+	result := make(chan error, 1)
+	go func() {
+		_, err := c.GetURL(ts.URL, nil)
+		result <- err
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.Shutdown(ctx); err != context.DeadlineExceeded {
+		t.Errorf("TestShutdownDeadline was incorrect, got: %v, want: %v.", err, context.DeadlineExceeded)
+	}
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Errorf("TestShutdownDeadline was incorrect, got: %v, want: %v.", err, context.Canceled)
+	}
+}
+
+// TestShutdownDuringBackoff should test that the requests waiting to retry are canceled at the deadline.
+func TestShutdownDuringBackoff(t *testing.T) {
+	waiting := make(chan struct{}, 1)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+		waiting <- struct{}{}
+	}))
+	defer ts.Close()
+
+	c := New()
+	result := make(chan error, 1)
+	go func() {
+		_, err := c.GetURL(ts.URL, nil)
+		result <- err
+	}()
+	<-waiting
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := c.Shutdown(ctx); err != context.DeadlineExceeded || time.Since(start) > 5*time.Second {
+		t.Errorf("TestShutdownDuringBackoff was incorrect, got: %v after %v, want: %v at the deadline.", err, time.Since(start), context.DeadlineExceeded)
+	}
+	if err := <-result; !errors.Is(err, context.Canceled) {
+		t.Errorf("TestShutdownDuringBackoff was incorrect, got: %v, want: %v.", err, context.Canceled)
+	}
+}
@@ -0,0 +1,43 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Timings are the times spent by a request besides the exchanges with the server.
+type Timings struct {
+	// QueueWait is the time waited for a slot of WithMaxConcurrentRequests.
+	QueueWait time.Duration
+}
+
+// WithMaxConcurrentRequests limits to n the requests in flight at the same
+// time across all the goroutines using the Client, each holding its slot
+// until its response body is read. The requests waiting for a slot get it as
+// by their priority, see WithPriority.
+func WithMaxConcurrentRequests(n int) Option {
+	return func(c *Client) {
+		c.concurrency = newPrioritySemaphore(n)
+	}
+}
+
+// acquireSlot waits for a slot of WithMaxConcurrentRequests, if set, returning
+// the function releasing it and the time waited.
+func (c *Client) acquireSlot(ctx context.Context, p Priority) (func(), time.Duration, error) {
+	if c.concurrency == nil {
+		return func() {}, 0, nil
+	}
+
+	start := c.clock.Now()
+	if err := c.concurrency.acquire(ctx, p); err != nil {
+		return nil, c.clock.Now().Sub(start), err
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(c.concurrency.release)
+	}
+
+	return release, c.clock.Now().Sub(start), nil
+}
@@ -0,0 +1,51 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMaxConcurrentRequests should test that no more than n requests are in flight across hosts.
+func TestMaxConcurrentRequests(t *testing.T) {
+	var current, peak int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(30 * time.Millisecond)
+	})
+	hosts := []*httptest.Server{httptest.NewServer(handler), httptest.NewServer(handler)}
+	for _, ts := range hosts {
+		defer ts.Close()
+	}
+
+	c := New(WithMaxConcurrentRequests(2))
+
+	var wg sync.WaitGroup
+	var queued int64
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resp, err := c.GetURL(hosts[i%2].URL, nil)
+			if err != nil {
+				t.Errorf("TestMaxConcurrentRequests was incorrect, got error: %v", err)
+			}
+			atomic.AddInt64(&queued, int64(resp.Timings.QueueWait))
+		}(i)
+	}
+	wg.Wait()
+
+	if peak > 2 || queued == 0 {
+		t.Errorf("TestMaxConcurrentRequests was incorrect, got: %d in flight and %v queued, want: at most 2 and some wait.", peak, time.Duration(queued))
+	}
+}
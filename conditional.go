@@ -0,0 +1,112 @@
+package httpclient
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Validators are the cache validators of a resource.
+type Validators struct {
+	ETag         string
+	LastModified string
+}
+
+// ValidatorStore persists the Validators of the resources fetched by FetchIfChanged.
+type ValidatorStore interface {
+	// Get returns the Validators of URL, the zero value if there are none.
+	Get(URL string) (Validators, error)
+	Set(URL string, v Validators) error
+}
+
+// MemoryValidatorStore is a ValidatorStore keeping the validators in memory.
+type MemoryValidatorStore struct {
+	mu         sync.Mutex
+	validators map[string]Validators
+}
+
+// NewMemoryValidatorStore returns an empty MemoryValidatorStore.
+func NewMemoryValidatorStore() *MemoryValidatorStore {
+	return &MemoryValidatorStore{validators: make(map[string]Validators)}
+}
+
+// Get implements ValidatorStore.
+func (s *MemoryValidatorStore) Get(URL string) (Validators, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.validators[URL], nil
+}
+
+// Set implements ValidatorStore.
+func (s *MemoryValidatorStore) Set(URL string, v Validators) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.validators[URL] = v
+
+	return nil
+}
+
+// FetchIfChanged retrieves URL with the validators kept in store, reporting
+// whether the resource changed since the last fetch. The body is nil when
+// it didn't change; otherwise the new validators are saved in store.
+func FetchIfChanged(URL string, headers map[string]string, store ValidatorStore) ([]byte, bool, error) {
+	return defaultClient.FetchIfChanged(URL, headers, store)
+}
+
+// FetchIfChanged retrieves URL with the validators kept in store, reporting
+// whether the resource changed since the last fetch. The body is nil when
+// it didn't change; otherwise the new validators are saved in store.
+func (c *Client) FetchIfChanged(URL string, headers map[string]string, store ValidatorStore) ([]byte, bool, error) {
+	v, err := store.Get(URL)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err := c.Request(URL, "GET", v.headers(headers), nil, returnNotModified)
+	if err != nil {
+		return nil, false, err
+	}
+	if resp.Status.Code == http.StatusNotModified {
+		return nil, false, nil
+	}
+
+	if updated := responseValidators(resp); updated != (Validators{}) {
+		if err := store.Set(URL, updated); err != nil {
+			return resp.Body, true, err
+		}
+	}
+
+	return resp.Body, true, nil
+}
+
+// headers returns headers with the conditional headers of v added.
+func (v Validators) headers(headers map[string]string) map[string]string {
+	h := make(map[string]string, len(headers)+2)
+	for k, val := range headers {
+		h[k] = val
+	}
+	if v.ETag != "" {
+		h["If-None-Match"] = v.ETag
+	}
+	if v.LastModified != "" {
+		h["If-Modified-Since"] = v.LastModified
+	}
+
+	return h
+}
+
+// responseValidators returns the Validators of resp.
+func responseValidators(resp HTTPResponse) Validators {
+	return Validators{
+		ETag:         resp.Headers.Get("ETag"),
+		LastModified: resp.Headers.Get("Last-Modified"),
+	}
+}
+
+// returnNotModified returns the 304 (Not Modified) responses to the caller.
+func returnNotModified(cfg *requestConfig) {
+	cfg.returnStatus = func(code int) bool {
+		return code == http.StatusNotModified
+	}
+}
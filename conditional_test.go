@@ -0,0 +1,40 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestFetchIfChanged should test that an unchanged resource is reported as such.
+func TestFetchIfChanged(t *testing.T) {
+	etag := `"v1"`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, "content "+etag)
+	}))
+	defer ts.Close()
+
+	store := NewMemoryValidatorStore()
+
+	body, changed, err := FetchIfChanged(ts.URL, nil, store)
+	if err != nil || !changed || string(body) != `content "v1"` {
+		t.Errorf("TestFetchIfChanged was incorrect on first fetch, got: %s %v (%v)", body, changed, err)
+	}
+
+	body, changed, err = FetchIfChanged(ts.URL, nil, store)
+	if err != nil || changed || body != nil {
+		t.Errorf("TestFetchIfChanged was incorrect on unchanged fetch, got: %s %v (%v)", body, changed, err)
+	}
+
+	etag = `"v2"`
+	body, changed, err = FetchIfChanged(ts.URL, nil, store)
+	if err != nil || !changed || string(body) != `content "v2"` {
+		t.Errorf("TestFetchIfChanged was incorrect on changed fetch, got: %s %v (%v)", body, changed, err)
+	}
+}
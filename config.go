@@ -0,0 +1,334 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Config is the configuration of a Client, loaded from a YAML or JSON file
+// by NewFromConfig, so that several services share one. The zero fields keep
+// the defaults of the Client. An example in YAML:
+//
+//	timeouts:
+//	  dial: 5s
+//	  total: 1m
+//	max_retries: 3
+//	user_agent: crawler/1.0
+//	limits:
+//	  max_concurrent: 32
+//	  max_per_host: 4
+//	  politeness_delay: 500ms
+//	robots: crawler
+//	auth:
+//	  token: s3cr3t
+//	cache:
+//	  dns_min_ttl: 30s
+//	  dns_max_ttl: 5m
+//	  not_found_ttl: 1h
+//	hosts:
+//	  - pattern: "*.github.com"
+//	    token: s3cr3t
+//	    rate: 5000/h
+//	  - pattern: "*.comune.*.it"
+//	    rate: 1/2s
+//	    timeout: 10s
+//	credentials:
+//	  - pattern: gitlab.com
+//	    header: Private-Token
+//	    token: s3cr3t
+type Config struct {
+	Timeouts TimeoutsConfig `yaml:"timeouts" json:"timeouts"`
+	// MaxRetries bounds the retries of each request, see WithMaxRetries.
+	MaxRetries *int `yaml:"max_retries" json:"max_retries"`
+	// Proxy is the URL of the proxy of the requests, see WithProxy.
+	Proxy     string            `yaml:"proxy" json:"proxy"`
+	UserAgent string            `yaml:"user_agent" json:"user_agent"`
+	Headers   map[string]string `yaml:"headers" json:"headers"`
+	Limits    LimitsConfig      `yaml:"limits" json:"limits"`
+	// Robots is the user agent for which the robots.txt are honored, see WithRobots.
+	Robots string      `yaml:"robots" json:"robots"`
+	Auth   AuthConfig  `yaml:"auth" json:"auth"`
+	Cache  CacheConfig `yaml:"cache" json:"cache"`
+	// Hosts override the settings for the hosts matching their patterns,
+	// see WithHostOverride.
+	Hosts []HostConfig `yaml:"hosts" json:"hosts"`
+	// Credentials authenticate the requests to the hosts matching their
+	// patterns only, see WithCredentials.
+	Credentials []CredentialConfig `yaml:"credentials" json:"credentials"`
+}
+
+// TimeoutsConfig are the Timeouts of a Config.
+type TimeoutsConfig struct {
+	Dial           Duration `yaml:"dial" json:"dial"`
+	TLSHandshake   Duration `yaml:"tls_handshake" json:"tls_handshake"`
+	ResponseHeader Duration `yaml:"response_header" json:"response_header"`
+	IdleConn       Duration `yaml:"idle_conn" json:"idle_conn"`
+	IdleRead       Duration `yaml:"idle_read" json:"idle_read"`
+	Total          Duration `yaml:"total" json:"total"`
+}
+
+// LimitsConfig are the limits of the requests of a Config, the per-host
+// ones applying to each host on its own.
+type LimitsConfig struct {
+	// MaxConcurrent limits the requests in flight, see WithMaxConcurrentRequests.
+	MaxConcurrent int `yaml:"max_concurrent" json:"max_concurrent"`
+	// MaxPerHost limits the requests in flight to a host, see WithMaxInflightPerHost.
+	MaxPerHost int `yaml:"max_per_host" json:"max_per_host"`
+	// PolitenessDelay spaces the requests to a host, see WithPolitenessDelay.
+	PolitenessDelay Duration `yaml:"politeness_delay" json:"politeness_delay"`
+	// BandwidthLimit limits the bytes per second read, see WithBandwidthLimit.
+	BandwidthLimit int64 `yaml:"bandwidth_limit" json:"bandwidth_limit"`
+}
+
+// AuthConfig are the credentials of a Config: a bearer token sent with every
+// request, see WithBearerToken, or a username and password answering the
+// Basic challenges, see BasicAuth.
+type AuthConfig struct {
+	Token    string `yaml:"token" json:"token"`
+	Username string `yaml:"username" json:"username"`
+	Password string `yaml:"password" json:"password"`
+}
+
+// CacheConfig are the caches of a Config, see WithDNSCache and WithNegativeCache.
+type CacheConfig struct {
+	DNSMinTTL   Duration `yaml:"dns_min_ttl" json:"dns_min_ttl"`
+	DNSMaxTTL   Duration `yaml:"dns_max_ttl" json:"dns_max_ttl"`
+	NotFoundTTL Duration `yaml:"not_found_ttl" json:"not_found_ttl"`
+}
+
+// HostConfig is a HostOverride of a Config, for the hosts matching Pattern.
+type HostConfig struct {
+	Pattern string            `yaml:"pattern" json:"pattern"`
+	Token   string            `yaml:"token" json:"token"`
+	Headers map[string]string `yaml:"headers" json:"headers"`
+	// Rate is the number of requests to each host per period, like
+	// "5000/h" or "1/2s", as by RateDelay.
+	Rate       string   `yaml:"rate" json:"rate"`
+	Timeout    Duration `yaml:"timeout" json:"timeout"`
+	MaxRetries *int     `yaml:"max_retries" json:"max_retries"`
+}
+
+// CredentialConfig is a Credential of a Config, for the hosts matching Pattern.
+type CredentialConfig struct {
+	Pattern   string `yaml:"pattern" json:"pattern"`
+	Token     string `yaml:"token" json:"token"`
+	Username  string `yaml:"username" json:"username"`
+	Password  string `yaml:"password" json:"password"`
+	Header    string `yaml:"header" json:"header"`
+	AllowHTTP bool   `yaml:"allow_http" json:"allow_http"`
+}
+
+// Duration is a time.Duration of a Config, written as a Go duration, like
+// "1m30s", or as a number of seconds.
+type Duration time.Duration
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var v string
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+
+	return d.parse(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	return d.parse(fmt.Sprint(v))
+}
+
+// parse sets d to the duration v, as by parseEnvDuration.
+func (d *Duration) parse(v string) error {
+	parsed, err := parseEnvDuration(v)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+
+	return nil
+}
+
+// LoadConfig reads the Config of the file at path, JSON if its extension is
+// .json, else YAML. The unknown keys fail.
+func LoadConfig(path string) (Config, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	var cfg Config
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(&cfg)
+	} else {
+		err = yaml.UnmarshalStrict(b, &cfg)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// NewFromConfig returns a Client configured by the Config of the file at
+// path, see LoadConfig. opts are applied after the Config, overriding it.
+func NewFromConfig(path string, opts ...Option) (*Client, error) {
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	cfgOpts, err := cfg.Options()
+	if err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+
+	return New(append(cfgOpts, opts...)...), nil
+}
+
+// Options returns the options configuring a Client as by cfg.
+func (cfg Config) Options() ([]Option, error) {
+	var opts []Option
+
+	t := Timeouts{
+		Dial:           time.Duration(cfg.Timeouts.Dial),
+		TLSHandshake:   time.Duration(cfg.Timeouts.TLSHandshake),
+		ResponseHeader: time.Duration(cfg.Timeouts.ResponseHeader),
+		IdleConn:       time.Duration(cfg.Timeouts.IdleConn),
+		IdleRead:       time.Duration(cfg.Timeouts.IdleRead),
+		Total:          time.Duration(cfg.Timeouts.Total),
+	}
+	if t != (Timeouts{}) {
+		opts = append(opts, WithTimeouts(t))
+	}
+	if cfg.MaxRetries != nil {
+		opts = append(opts, WithMaxRetries(*cfg.MaxRetries))
+	}
+	if cfg.Proxy != "" {
+		u, err := parseProxyURL(cfg.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithProxy(u))
+	}
+
+	headers := make(map[string]string, len(cfg.Headers)+1)
+	for k, v := range cfg.Headers {
+		headers[k] = v
+	}
+	if cfg.UserAgent != "" {
+		headers["User-Agent"] = cfg.UserAgent
+	}
+	if len(headers) > 0 {
+		opts = append(opts, WithHeaders(headers))
+	}
+
+	if cfg.Limits.MaxConcurrent > 0 {
+		opts = append(opts, WithMaxConcurrentRequests(cfg.Limits.MaxConcurrent))
+	}
+	if cfg.Limits.MaxPerHost > 0 {
+		opts = append(opts, WithMaxInflightPerHost(cfg.Limits.MaxPerHost))
+	}
+	if cfg.Limits.PolitenessDelay > 0 {
+		opts = append(opts, WithPolitenessDelay(time.Duration(cfg.Limits.PolitenessDelay)))
+	}
+	if cfg.Limits.BandwidthLimit > 0 {
+		opts = append(opts, WithBandwidthLimit(cfg.Limits.BandwidthLimit))
+	}
+	if cfg.Robots != "" {
+		opts = append(opts, WithRobots(cfg.Robots))
+	}
+
+	if cfg.Auth.Token != "" {
+		opts = append(opts, WithBearerToken(cfg.Auth.Token))
+	}
+	if a := cfg.Auth; a.Username != "" {
+		opts = append(opts, WithAuthenticator("Basic", BasicAuth(a.Username, a.Password)))
+	}
+
+	if c := cfg.Cache; c.DNSMinTTL > 0 || c.DNSMaxTTL > 0 {
+		opts = append(opts, WithDNSCache(time.Duration(c.DNSMinTTL), time.Duration(c.DNSMaxTTL)))
+	}
+	if cfg.Cache.NotFoundTTL > 0 {
+		opts = append(opts, WithNegativeCache(time.Duration(cfg.Cache.NotFoundTTL)))
+	}
+
+	for _, h := range cfg.Hosts {
+		if h.Pattern == "" {
+			return nil, errors.New("host override without a pattern")
+		}
+		delay, err := parseRate(h.Rate)
+		if err != nil {
+			return nil, fmt.Errorf("host %s: %w", h.Pattern, err)
+		}
+		opts = append(opts, WithHostOverride(h.Pattern, HostOverride{
+			Token:      h.Token,
+			Headers:    h.Headers,
+			Delay:      delay,
+			Timeout:    time.Duration(h.Timeout),
+			MaxRetries: h.MaxRetries,
+		}))
+	}
+
+	for _, cred := range cfg.Credentials {
+		if cred.Pattern == "" {
+			return nil, errors.New("credential without a pattern")
+		}
+		opts = append(opts, WithCredentials(cred.Pattern, Credential{
+			Token:     cred.Token,
+			Username:  cred.Username,
+			Password:  cred.Password,
+			Header:    cred.Header,
+			AllowHTTP: cred.AllowHTTP,
+		}))
+	}
+
+	return opts, nil
+}
+
+// parseRate returns the delay of a rate like "5000/h", "100/m" or "1/2s",
+// zero for an empty one.
+func parseRate(rate string) (time.Duration, error) {
+	if rate == "" {
+		return 0, nil
+	}
+
+	i := strings.IndexByte(rate, '/')
+	if i < 0 {
+		return 0, fmt.Errorf("invalid rate %q", rate)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(rate[:i]))
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid rate %q", rate)
+	}
+
+	var per time.Duration
+	switch unit := strings.TrimSpace(rate[i+1:]); unit {
+	case "s":
+		per = time.Second
+	case "m":
+		per = time.Minute
+	case "h":
+		per = time.Hour
+	default:
+		if per, err = time.ParseDuration(unit); err != nil || per <= 0 {
+			return 0, fmt.Errorf("invalid rate %q", rate)
+		}
+	}
+
+	return RateDelay(n, per), nil
+}
@@ -0,0 +1,96 @@
+package httpclient
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestLoadConfig should test that the YAML and JSON configs are loaded alike.
+func TestLoadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"client.yml": "timeouts:\n  dial: 5s\n  total: 90\nmax_retries: 0\nlimits:\n  max_per_host: 4\n  politeness_delay: 500ms\ncache:\n  not_found_ttl: 1h\n",
+		"client.json": `{"timeouts": {"dial": "5s", "total": 90}, "max_retries": 0,
+			"limits": {"max_per_host": 4, "politeness_delay": "500ms"}, "cache": {"not_found_ttl": "1h"}}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("TestLoadConfig was incorrect, got: %v, want: no error for %s.", err, name)
+		}
+		if time.Duration(cfg.Timeouts.Dial) != 5*time.Second || time.Duration(cfg.Timeouts.Total) != 90*time.Second ||
+			cfg.MaxRetries == nil || *cfg.MaxRetries != 0 || cfg.Limits.MaxPerHost != 4 ||
+			time.Duration(cfg.Limits.PolitenessDelay) != 500*time.Millisecond || time.Duration(cfg.Cache.NotFoundTTL) != time.Hour {
+			t.Errorf("TestLoadConfig was incorrect, got: %+v, want: the values of %s.", cfg, name)
+		}
+	}
+
+	for name, content := range map[string]string{"unknown.yml": "timeout: 5s\n", "invalid.json": `{"timeouts": {"dial": "soon"}}`} {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadConfig(path); err == nil {
+			t.Errorf("TestLoadConfig was incorrect, got: no error, want: an error for %s.", name)
+		}
+	}
+}
+
+// TestNewFromConfig should test that the Client is configured by the config file.
+func TestNewFromConfig(t *testing.T) {
+	var authorization, userAgent, team, key string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization, userAgent, team = r.Header.Get("Authorization"), r.Header.Get("User-Agent"), r.Header.Get("X-Team")
+		key = r.Header.Get("X-Api-Key")
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "client.yaml")
+	content := "user_agent: crawler/1.0\nauth:\n  token: s3cr3t\nproxy: proxy.local:3128\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFromConfig(path); err == nil {
+		t.Errorf("TestNewFromConfig was incorrect, got: no error, want: an error for the invalid proxy.")
+	}
+
+	content = "user_agent: crawler/1.0\nauth:\n  token: s3cr3t\nhosts:\n  - pattern: \"*.example.org\"\n    rate: 1/day\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFromConfig(path); err == nil {
+		t.Errorf("TestNewFromConfig was incorrect, got: no error, want: an error for the invalid rate.")
+	}
+
+	content = "user_agent: crawler/1.0\nauth:\n  token: s3cr3t\nhosts:\n  - pattern: \"127.0.0.*\"\n    headers:\n      X-Team: crawler\n    rate: 10/s\ncredentials:\n  - pattern: 127.0.0.1\n    header: X-Api-Key\n    token: k3y\n    allow_http: true\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := NewFromConfig(path)
+	if err != nil {
+		t.Fatalf("TestNewFromConfig was incorrect, got: %v, want: no error.", err)
+	}
+	if _, err := c.GetURL(ts.URL, nil); err != nil || authorization != "Bearer s3cr3t" || userAgent != "crawler/1.0" || team != "crawler" || key != "k3y" {
+		t.Errorf("TestNewFromConfig was incorrect, got: %q, %q, %q, %q, %v, want: the token, the user agent, the header and the credential of the host.", authorization, userAgent, team, key, err)
+	}
+}
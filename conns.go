@@ -0,0 +1,176 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// addrNotAvailWait is the wait before dialing again after EADDRNOTAVAIL,
+// unless a connection is closed before.
+const addrNotAvailWait = 100 * time.Millisecond
+
+// ConnStats are the statistics of the connections of a Client, to watch
+// the connection churn of a crawl, see Client.ConnStats.
+type ConnStats struct {
+	// Dialed and Closed count the connections opened and closed, Open is
+	// the number of the connections open, idle ones included.
+	Dialed int64
+	Closed int64
+	Open   int64
+	// AddrNotAvail counts the dials failed for lack of ephemeral ports
+	// (EADDRNOTAVAIL), dialed again WithMaxConns.
+	AddrNotAvail int64
+	// Waits counts the dials that waited for a connection to close, WithMaxConns.
+	Waits int64
+}
+
+// connTracker counts the connections of a Client, and bounds them WithMaxConns.
+type connTracker struct {
+	dialed, closed, addrNotAvail, waits int64
+
+	max   int
+	slots chan struct{}
+	// freed is closed, and replaced, when a connection is closed.
+	mu        sync.Mutex
+	freed     chan struct{}
+	closeIdle func()
+	clock     Clock
+}
+
+// WithMaxConns limits to n the connections open at once by the Client, to
+// save the ephemeral ports of the large crawls, where the connections closed
+// linger in TIME_WAIT: up to n idle connections are kept for reuse, and the
+// dials beyond n close the idle connections and wait for one to close. A dial
+// failing with EADDRNOTAVAIL waits likewise and is made again, instead of
+// failing the request. It is ignored with WithTransport.
+func WithMaxConns(n int) Option {
+	return func(c *Client) {
+		c.conns.max = n
+	}
+}
+
+// ConnStats returns the statistics of the connections of the Client.
+func (c *Client) ConnStats() ConnStats {
+	t := &c.conns
+	s := ConnStats{
+		Dialed:       atomic.LoadInt64(&t.dialed),
+		Closed:       atomic.LoadInt64(&t.closed),
+		AddrNotAvail: atomic.LoadInt64(&t.addrNotAvail),
+		Waits:        atomic.LoadInt64(&t.waits),
+	}
+	s.Open = s.Dialed - s.Closed
+
+	return s
+}
+
+// dialContext wraps dial to count the connections and bound them.
+func (t *connTracker) dialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	if t.max > 0 {
+		t.slots = make(chan struct{}, t.max)
+		t.freed = make(chan struct{})
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if err := t.acquire(ctx); err != nil {
+			return nil, err
+		}
+
+		for {
+			conn, err := dial(ctx, network, addr)
+			if err == nil {
+				atomic.AddInt64(&t.dialed, 1)
+				return &trackedConn{Conn: conn, t: t}, nil
+			}
+			if !errors.Is(err, syscall.EADDRNOTAVAIL) {
+				t.release()
+				return nil, err
+			}
+
+			atomic.AddInt64(&t.addrNotAvail, 1)
+			if t.max <= 0 {
+				return nil, err
+			}
+			log.Warnf("No ephemeral port available, waiting to dial %s again", addr)
+			t.closeIdle()
+			if err := t.waitFreed(ctx, t.clock.After(addrNotAvailWait)); err != nil {
+				t.release()
+				return nil, err
+			}
+		}
+	}
+}
+
+// acquire takes a connection slot, closing the idle connections and waiting
+// for one to close if there's none free.
+func (t *connTracker) acquire(ctx context.Context) error {
+	if t.slots == nil {
+		return nil
+	}
+
+	select {
+	case t.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	atomic.AddInt64(&t.waits, 1)
+	t.closeIdle()
+	select {
+	case t.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a connection slot.
+func (t *connTracker) release() {
+	if t.slots == nil {
+		return
+	}
+
+	<-t.slots
+	t.mu.Lock()
+	close(t.freed)
+	t.freed = make(chan struct{})
+	t.mu.Unlock()
+}
+
+// waitFreed waits for a connection to be closed, or for timeout.
+func (t *connTracker) waitFreed(ctx context.Context, timeout <-chan time.Time) error {
+	t.mu.Lock()
+	freed := t.freed
+	t.mu.Unlock()
+
+	select {
+	case <-freed:
+	case <-timeout:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// trackedConn is a connection counted by a connTracker.
+type trackedConn struct {
+	net.Conn
+	t    *connTracker
+	once sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		atomic.AddInt64(&c.t.closed, 1)
+		c.t.release()
+	})
+
+	return c.Conn.Close()
+}
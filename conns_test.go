@@ -0,0 +1,71 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"syscall"
+	"testing"
+)
+
+// TestConnStats should test that the connections are counted and reused.
+func TestConnStats(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerOneRepoList))
+	defer ts.Close()
+
+	c := New()
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetURL(ts.URL, nil); err != nil {
+			t.Fatalf("TestConnStats was incorrect, got error: %v", err)
+		}
+	}
+	if s := c.ConnStats(); s.Dialed != 1 || s.Open != 1 {
+		t.Errorf("TestConnStats was incorrect, got: %+v, want: 1 connection dialed and open.", s)
+	}
+
+	c.httpClient.CloseIdleConnections()
+	if s := c.ConnStats(); s.Closed != 1 || s.Open != 0 {
+		t.Errorf("TestConnStats was incorrect, got: %+v, want: 1 connection closed.", s)
+	}
+}
+
+// TestWithMaxConns should test that the idle connections are closed to dial
+// beyond the limit, and that the dials failing with EADDRNOTAVAIL are made again.
+func TestWithMaxConns(t *testing.T) {
+	ts1 := httptest.NewServer(http.HandlerFunc(handlerOneRepoList))
+	defer ts1.Close()
+	ts2 := httptest.NewServer(http.HandlerFunc(handlerOneRepoList))
+	defer ts2.Close()
+
+	failures := 2
+	var d net.Dialer
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if failures > 0 {
+			failures--
+			return nil, &net.OpError{Op: "dial", Net: network, Err: os.NewSyscallError("connect", syscall.EADDRNOTAVAIL)}
+		}
+		return d.DialContext(ctx, network, addr)
+	}
+	c := New(WithClock(newTestClock()), WithDialContext(dial), WithMaxConns(1))
+
+	for _, URL := range []string{ts1.URL, ts2.URL, ts1.URL} {
+		if _, err := c.GetURL(URL, nil); err != nil {
+			t.Fatalf("TestWithMaxConns was incorrect, got error: %v", err)
+		}
+		if s := c.ConnStats(); s.Open > 1 {
+			t.Errorf("TestWithMaxConns was incorrect, got: %d connections open, want: at most 1.", s.Open)
+		}
+	}
+	if s := c.ConnStats(); s.Dialed != 3 || s.AddrNotAvail != 2 || s.Waits < 1 {
+		t.Errorf("TestWithMaxConns was incorrect, got: %+v, want: 3 dialed, 2 EADDRNOTAVAIL and a wait.", s)
+	}
+
+	// Without the limit, the error is returned.
+	failures = 1
+	c = New(WithDialContext(dial))
+	if _, err := c.GetURL(ts1.URL, nil); err == nil || c.ConnStats().AddrNotAvail != 1 {
+		t.Errorf("TestWithMaxConns was incorrect, got: %v, want: EADDRNOTAVAIL.", err)
+	}
+}
@@ -0,0 +1,93 @@
+package httpclient
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// Credential authenticates the requests to the hosts matching a pattern,
+// see WithCredentials.
+type Credential struct {
+	// Token is sent as "Authorization: Bearer token".
+	Token string
+	// Username and Password are sent with the Basic scheme, if Token is empty.
+	Username string
+	Password string
+	// Header is the header carrying Token as it is in place of Authorization,
+	// e.g. "Private-Token" for GitLab or "X-Api-Key".
+	Header string
+	// AllowHTTP sends the credential over plain HTTP, by default it's sent
+	// over HTTPS only.
+	AllowHTTP bool
+}
+
+// hostCredential is a Credential with its pattern.
+type hostCredential struct {
+	pattern []string
+	Credential
+}
+
+// WithCredentials sends cred with the requests to the hosts matching
+// pattern, as by WithHostOverride, and never with the others: unlike the
+// headers of WithHeaders or WithBearerToken, the credential is chosen by
+// the host of each request sent, redirects included, so that it doesn't
+// leak to the crawled URLs or to the redirect targets. A request gets the
+// credential of the first pattern matching its host, in the order added,
+// unless it sets the header itself.
+func WithCredentials(pattern string, cred Credential) Option {
+	return func(c *Client) {
+		c.credentials = append(c.credentials, hostCredential{
+			pattern:    strings.Split(strings.ToLower(pattern), "."),
+			Credential: cred,
+		})
+	}
+}
+
+// header returns the name and the value of the header carrying cred.
+func (cred Credential) header() (string, string) {
+	switch {
+	case cred.Header != "":
+		return cred.Header, cred.Token
+	case cred.Token != "":
+		return "Authorization", "Bearer " + cred.Token
+	default:
+		return "Authorization", "Basic " + base64.StdEncoding.EncodeToString([]byte(cred.Username+":"+cred.Password))
+	}
+}
+
+// credentialsTransport is an http.RoundTripper adding to each request the
+// credential of its host.
+type credentialsTransport struct {
+	next        http.RoundTripper
+	credentials []hostCredential
+}
+
+// RoundTrip sends req with the credential of its host, if any.
+func (t *credentialsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	cred, ok := t.match(req.URL.Hostname())
+	if !ok || (req.URL.Scheme != "https" && !cred.AllowHTTP) {
+		return t.next.RoundTrip(req)
+	}
+
+	name, value := cred.header()
+	if req.Header.Get(name) == "" {
+		// A RoundTripper must not modify the request.
+		req = req.Clone(req.Context())
+		req.Header.Set(name, value)
+	}
+
+	return t.next.RoundTrip(req)
+}
+
+// match returns the credential of the first pattern matching host.
+func (t *credentialsTransport) match(host string) (Credential, bool) {
+	labels := strings.Split(strings.ToLower(strings.TrimSuffix(host, ".")), ".")
+	for _, cred := range t.credentials {
+		if matchHost(cred.pattern, labels) {
+			return cred.Credential, true
+		}
+	}
+
+	return Credential{}, false
+}
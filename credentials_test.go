@@ -0,0 +1,39 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestWithCredentials should test that the credentials are sent to the matching hosts only.
+func TestWithCredentials(t *testing.T) {
+	got := map[string]string{}
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got["other"] = r.Header.Get("Authorization")
+	}))
+	defer other.Close()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got[r.URL.Path] = r.Header.Get("Authorization") + r.Header.Get("Private-Token")
+		if r.URL.Path == "/redirect" {
+			// The other server is reached as localhost, not matching the pattern.
+			http.Redirect(w, r, strings.Replace(other.URL, "127.0.0.1", "localhost", 1), http.StatusFound)
+		}
+	}))
+	defer ts.Close()
+
+	c := New(WithCredentials("127.0.0.1", Credential{Username: "user", Password: "pass", AllowHTTP: true}))
+	c.GetURL(ts.URL+"/basic", nil)
+	c.GetURL(ts.URL+"/own", map[string]string{"Authorization": "Bearer mine"})
+	c.GetURL(ts.URL+"/redirect", nil)
+	if got["/basic"] != "Basic dXNlcjpwYXNz" || got["/own"] != "Bearer mine" || got["/redirect"] != "Basic dXNlcjpwYXNz" || got["other"] != "" {
+		t.Errorf("TestWithCredentials was incorrect, got: %v, want: the credential sent to 127.0.0.1 only.", got)
+	}
+
+	New(WithCredentials("127.0.0.1", Credential{Header: "Private-Token", Token: "s3cr3t", AllowHTTP: true})).GetURL(ts.URL+"/header", nil)
+	New(WithCredentials("127.0.0.1", Credential{Token: "s3cr3t"})).GetURL(ts.URL+"/http", nil)
+	if got["/header"] != "s3cr3t" || got["/http"] != "" {
+		t.Errorf("TestWithCredentials was incorrect, got: %q, %q, want: the Private-Token, nothing over plain HTTP.", got["/header"], got["/http"])
+	}
+}
@@ -0,0 +1,173 @@
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// redacted replaces the values of the sensitive headers in dumps.
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders are the headers holding secrets, redacted in dumps.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "Private-Token", "X-Api-Key"}
+
+// WithDump writes to w, for each attempt of the request, the equivalent curl
+// command, the full request and the response, with the beginning of its
+// body, with the secrets redacted.
+func WithDump(w io.Writer) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.dump = w
+	}
+}
+
+// WithDebugDump writes to w, for each attempt of every request of the Client,
+// the request and response as by WithDump. The dump can be switched at
+// runtime with SetDebugDump.
+func WithDebugDump(w io.Writer) Option {
+	return func(c *Client) {
+		c.SetDebugDump(w)
+	}
+}
+
+// SetDebugDump starts writing to w the dumps of the requests of c, as by
+// WithDebugDump, or stops writing them if w is nil. WithDump takes precedence
+// for the single requests.
+func (c *Client) SetDebugDump(w io.Writer) {
+	c.debugMu.Lock()
+	defer c.debugMu.Unlock()
+
+	if w == nil {
+		c.debugDump = nil
+		return
+	}
+	c.debugDump = &lockedWriter{w: w}
+}
+
+// dumpWriter returns where to dump the attempts of a request, nil if nowhere.
+func (c *Client) dumpWriter(cfg *requestConfig) io.Writer {
+	if cfg.dump != nil {
+		return cfg.dump
+	}
+
+	c.debugMu.Lock()
+	defer c.debugMu.Unlock()
+
+	// Avoid returning a nil *lockedWriter as a non-nil io.Writer.
+	if c.debugDump == nil {
+		return nil
+	}
+
+	return c.debugDump
+}
+
+// lockedWriter serializes the writes of the concurrent requests.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.w.Write(p)
+}
+
+// CurlCommand renders req as an equivalent curl command, with the secrets redacted.
+// The body of req, if any, is read and restored.
+func CurlCommand(req *http.Request) (string, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "curl -X %s %s", req.Method, shellQuote(req.URL.String()))
+
+	header := redactHeader(req.Header)
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range header[k] {
+			fmt.Fprintf(&sb, " -H %s", shellQuote(k+": "+v))
+		}
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return "", err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		fmt.Fprintf(&sb, " --data-binary %s", shellQuote(string(body)))
+	}
+
+	return sb.String(), nil
+}
+
+// dumpRequest writes to w the curl command and the request of an attempt.
+// The body is read and restored.
+func dumpRequest(w io.Writer, req *http.Request) {
+	if curl, err := CurlCommand(req); err == nil {
+		fmt.Fprintf(w, "%s\n\n", curl)
+	}
+
+	out := req.Clone(req.Context())
+	out.Header = redactHeader(req.Header)
+	if b, err := httputil.DumpRequestOut(out, true); err == nil {
+		fmt.Fprintf(w, "%s\n\n", b)
+	}
+	req.Body = out.Body
+}
+
+// dumpBodyExcerpt is the length of the beginning of the response bodies dumped.
+const dumpBodyExcerpt = 64 << 10
+
+// dumpResponse writes to w the response of an attempt, with the first
+// dumpBodyExcerpt bytes of the body. The excerpt is read and restored, the
+// rest of the body left to be read.
+func dumpResponse(w io.Writer, resp *http.Response) {
+	header := resp.Header
+	resp.Header = redactHeader(header)
+	b, err := httputil.DumpResponse(resp, false)
+	resp.Header = header
+	if err != nil {
+		return
+	}
+
+	excerpt, err := ioutil.ReadAll(io.LimitReader(resp.Body, dumpBodyExcerpt+1))
+	resp.Body = &transformedBody{Reader: io.MultiReader(bytes.NewReader(excerpt), resp.Body), Closer: resp.Body}
+	if err != nil {
+		return
+	}
+	if len(excerpt) > dumpBodyExcerpt {
+		fmt.Fprintf(w, "%s%s\n[truncated after %d bytes]\n\n", b, excerpt[:dumpBodyExcerpt], dumpBodyExcerpt)
+		return
+	}
+	fmt.Fprintf(w, "%s%s\n\n", b, excerpt)
+}
+
+// redactHeader returns a copy of h with the values of the sensitive headers redacted.
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for _, name := range sensitiveHeaders {
+		if values, ok := out[http.CanonicalHeaderKey(name)]; ok {
+			for i := range values {
+				values[i] = redacted
+			}
+		}
+	}
+
+	return out
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,89 @@
+package httpclient
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCurlCommand should test that a request is rendered as curl command with secrets redacted.
+func TestCurlCommand(t *testing.T) {
+	req, _ := http.NewRequest("POST", "http://example.url/it's", strings.NewReader("{\"a\": 1}"))
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "token secret")
+
+	curl, err := CurlCommand(req)
+	r := `curl -X POST 'http://example.url/it'\''s' -H 'Accept: application/json' -H 'Authorization: [REDACTED]' --data-binary '{"a": 1}'`
+	if err != nil || curl != r {
+		t.Errorf("TestCurlCommand was incorrect, got: %s, want: %s.", curl, r)
+	}
+}
+
+// TestWithDump should test that request and response are dumped without secrets.
+func TestWithDump(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerHeaderInResponse))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	resp, err := GetURL(ts.URL, map[string]string{"Authorization": "token secret"}, WithDump(&buf))
+	if err != nil || string(resp.Body) == "" {
+		t.Fatalf("TestWithDump was incorrect, got error: %v", err)
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "secret") {
+		t.Errorf("TestWithDump was incorrect, the secret was dumped: %s", out)
+	}
+	for _, want := range []string{"curl -X GET", "GET / HTTP/1.1", "X-Powoftwo: 4", "example data"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("TestWithDump was incorrect, got: %s, want it to contain: %s.", out, want)
+		}
+	}
+}
+
+// TestWithDumpLargeBody should test that only the beginning of a large body is dumped,
+// the body being returned in full.
+func TestWithDumpLargeBody(t *testing.T) {
+	body := bytes.Repeat([]byte("#"), 2*dumpBodyExcerpt)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	resp, err := GetURL(ts.URL, nil, WithDump(&buf))
+	if err != nil || !bytes.Equal(resp.Body, body) {
+		t.Fatalf("TestWithDumpLargeBody was incorrect, got: %d bytes (%v), want: %d bytes.", len(resp.Body), err, len(body))
+	}
+	if n := strings.Count(buf.String(), "#"); n != dumpBodyExcerpt || !strings.Contains(buf.String(), "[truncated after") {
+		t.Errorf("TestWithDumpLargeBody was incorrect, got: %d bytes of the body dumped, want: %d and the truncation.", n, dumpBodyExcerpt)
+	}
+}
+
+// TestWithDebugDump should test that the requests of the Client are dumped until switched off.
+func TestWithDebugDump(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerHeaderInResponse))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	c := New(WithDebugDump(&buf))
+	if _, err := c.GetURL(ts.URL, map[string]string{"Private-Token": "secret"}); err != nil {
+		t.Fatalf("TestWithDebugDump was incorrect, got error: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "GET / HTTP/1.1") || !strings.Contains(out, "X-Powoftwo: 4") || strings.Contains(out, "secret") {
+		t.Errorf("TestWithDebugDump was incorrect, got: %s, want: the redacted request and response.", out)
+	}
+
+	c.SetDebugDump(nil)
+	buf.Reset()
+	if _, err := c.GetURL(ts.URL, nil); err != nil {
+		t.Fatalf("TestWithDebugDump was incorrect, got error: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("TestWithDebugDump was incorrect, got: %s after switching it off, want: nothing.", buf.String())
+	}
+}
@@ -0,0 +1,95 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// warnPersistent is the code of the Warning headers persistent across the
+// responses, used by the APIs to warn about deprecations, e.g. Kubernetes.
+const warnPersistent = "299"
+
+// Deprecation is the retirement of a resource announced by a response,
+// see WithDeprecationHandler.
+type Deprecation struct {
+	// Deprecated reports whether the Deprecation header is present, and Date
+	// is when the resource was or will be deprecated, zero if not told.
+	Deprecated bool
+	Date       time.Time
+	// Sunset is when the resource will stop responding (RFC 8594), zero if unknown.
+	Sunset time.Time
+	// Link is the documentation of the deprecation, from the Link header of
+	// relation "deprecation", or else "sunset".
+	Link string
+	// Warnings are the texts of the Warning headers with code 299.
+	Warnings []string
+}
+
+// Announced reports whether the response announced a deprecation, a sunset
+// or a warning.
+func (d Deprecation) Announced() bool {
+	return d.Deprecated || !d.Sunset.IsZero() || len(d.Warnings) > 0
+}
+
+// WithDeprecationHandler sets fn to be called with each response announcing
+// a deprecation, a sunset or a warning, to learn that an API is being retired
+// before it breaks.
+func WithDeprecationHandler(fn func(resp HTTPResponse)) Option {
+	return func(c *Client) {
+		c.onDeprecation = fn
+	}
+}
+
+// parseDeprecation returns the Deprecation announced by the headers of r.
+func parseDeprecation(r HTTPResponse) Deprecation {
+	h := r.Headers
+	var d Deprecation
+
+	if value := strings.TrimSpace(h.Get("Deprecation")); value != "" {
+		d.Deprecated = true
+		if strings.HasPrefix(value, "@") {
+			// The structured date of RFC 9745, e.g. "@1688169599".
+			if seconds, err := strconv.ParseInt(value[1:], 10, 64); err == nil {
+				d.Date = time.Unix(seconds, 0)
+			}
+		} else {
+			// The HTTP-date of the earlier drafts, or "true".
+			d.Date, _ = http.ParseTime(value)
+		}
+	}
+	d.Sunset, _ = http.ParseTime(h.Get("Sunset"))
+
+	for _, value := range h.Values("Warning") {
+		if text, ok := persistentWarning(value); ok {
+			d.Warnings = append(d.Warnings, text)
+		}
+	}
+
+	if d.Announced() && len(h.Values("Link")) > 0 {
+		if d.Link = r.Link("deprecation"); d.Link == "" {
+			d.Link = r.Link("sunset")
+		}
+	}
+
+	return d
+}
+
+// persistentWarning returns the text of a Warning header with code 299, like
+// `299 - "Deprecated API"`.
+func persistentWarning(value string) (string, bool) {
+	fields := strings.SplitN(strings.TrimSpace(value), " ", 3)
+	if len(fields) < 3 || fields[0] != warnPersistent {
+		return "", false
+	}
+
+	text := fields[2]
+	if strings.HasPrefix(text, `"`) {
+		if unquoted, _, ok := parseParamValue(text); ok {
+			return unquoted, true
+		}
+	}
+
+	return text, true
+}
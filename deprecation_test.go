@@ -0,0 +1,54 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestDeprecation should test that the Deprecation, Sunset and Warning headers are parsed.
+func TestDeprecation(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1":
+			w.Header().Set("Deprecation", "@1688169599")
+			w.Header().Set("Sunset", "Wed, 11 Nov 2026 23:59:59 GMT")
+			w.Header().Add("Link", `</docs/v2>; rel="successor-version", </docs/deprecation>; rel="deprecation"`)
+			w.Header().Add("Warning", `299 - "extensions/v1beta1 Ingress is deprecated"`)
+			w.Header().Add("Warning", `110 - "Response is Stale"`)
+		case "/beta":
+			w.Header().Set("Deprecation", "true")
+		}
+	}))
+	defer ts.Close()
+
+	var announced []string
+	c := New(WithDeprecationHandler(func(resp HTTPResponse) {
+		announced = append(announced, resp.URL)
+	}))
+
+	resp, err := c.GetURL(ts.URL+"/v1", nil)
+	if err != nil {
+		t.Fatalf("TestDeprecation was incorrect, got error: %v", err)
+	}
+	d := resp.Deprecation
+	sunset := time.Date(2026, 11, 11, 23, 59, 59, 0, time.UTC)
+	if !d.Deprecated || !d.Date.Equal(time.Unix(1688169599, 0)) || !d.Sunset.Equal(sunset) {
+		t.Errorf("TestDeprecation was incorrect, got: %+v, want: deprecated at 1688169599, sunset at %v.", d, sunset)
+	}
+	if d.Link != ts.URL+"/docs/deprecation" || len(d.Warnings) != 1 || d.Warnings[0] != "extensions/v1beta1 Ingress is deprecated" {
+		t.Errorf("TestDeprecation was incorrect, got link %s and warnings %q.", d.Link, d.Warnings)
+	}
+
+	if resp, _ := c.GetURL(ts.URL+"/beta", nil); !resp.Deprecation.Deprecated || !resp.Deprecation.Date.IsZero() {
+		t.Errorf("TestDeprecation was incorrect, got: %+v, want: deprecated without date.", resp.Deprecation)
+	}
+	if resp, _ := c.GetURL(ts.URL+"/v2", nil); resp.Deprecation.Announced() {
+		t.Errorf("TestDeprecation was incorrect, got: %+v, want: no deprecation.", resp.Deprecation)
+	}
+
+	if len(announced) != 2 || announced[0] != ts.URL+"/v1" || announced[1] != ts.URL+"/beta" {
+		t.Errorf("TestDeprecation was incorrect, got handler calls: %v, want: /v1 and /beta.", announced)
+	}
+}
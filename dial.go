@@ -0,0 +1,119 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+)
+
+// WithFallbackDelay sets how long the dialer waits for an IPv6 connection
+// before racing an IPv4 one (Happy Eyeballs, RFC 6555), 300ms by default.
+// A negative delay disables the fallback. It is ignored with WithDNSCache,
+// which tries the addresses in order, and with WithTransport.
+func WithFallbackDelay(d time.Duration) Option {
+	return func(c *Client) {
+		c.fallbackDelay = d
+	}
+}
+
+// WithIPv4Only makes the Client connect over IPv4 only, e.g. to hosts
+// publishing broken AAAA records. It is ignored with WithTransport.
+func WithIPv4Only() Option {
+	return func(c *Client) {
+		c.network = "tcp4"
+	}
+}
+
+// WithIPv6Only makes the Client connect over IPv6 only.
+// It is ignored with WithTransport.
+func WithIPv6Only() Option {
+	return func(c *Client) {
+		c.network = "tcp6"
+	}
+}
+
+// WithDialContext makes the Client open its connections with dial in place of
+// a net.Dialer, e.g. through a tunnel or to an in-memory pipe in tests.
+// WithDNSCache and WithIPv4Only still apply, calling dial with the addresses
+// resolved and the network chosen, while the dial timeout and WithFallbackDelay
+// are up to dial. It is ignored with WithTransport.
+func WithDialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) Option {
+	return func(c *Client) {
+		c.dial = dial
+	}
+}
+
+// WithLocalAddr makes the Client open its connections from the local address
+// ip, e.g. the registered egress address of a multi-homed host. Only the
+// hosts with addresses of the family of ip can be reached. It is ignored with
+// WithDialContext and WithTransport.
+func WithLocalAddr(ip net.IP) Option {
+	return func(c *Client) {
+		c.localAddr = &net.TCPAddr{IP: ip}
+	}
+}
+
+// WithInterface makes the Client open its connections from the first IPv4
+// address of the network interface name, e.g. "eth1", or its first IPv6 one
+// with WithIPv6Only or without IPv4 addresses. The connections fail if the
+// interface has no address. It is ignored with WithDialContext and WithTransport.
+func WithInterface(name string) Option {
+	return func(c *Client) {
+		c.localInterface = name
+	}
+}
+
+// interfaceAddr returns the address of the interface name to bind the
+// connections over network to, as by WithInterface.
+func interfaceAddr(name, network string) (*net.TCPAddr, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+
+	var v4, v6 net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		if ip4 := ipNet.IP.To4(); ip4 != nil {
+			if v4 == nil {
+				v4 = ip4
+			}
+		} else if v6 == nil {
+			v6 = ipNet.IP
+		}
+	}
+
+	switch {
+	case v4 != nil && network != "tcp6":
+		return &net.TCPAddr{IP: v4}, nil
+	case v6 != nil && network != "tcp4":
+		return &net.TCPAddr{IP: v6}, nil
+	}
+
+	return nil, fmt.Errorf("no address to bind to on interface %s", name)
+}
+
+// failingDial returns a dial function failing with err.
+func failingDial(err error) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(context.Context, string, string) (net.Conn, error) {
+		return nil, err
+	}
+}
+
+// dialNetwork wraps dial so that TCP connections use network.
+func dialNetwork(network string, dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, n, addr string) (net.Conn, error) {
+		if n == "tcp" {
+			n = network
+		}
+		return dial(ctx, n, addr)
+	}
+}
@@ -0,0 +1,117 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestIPFamily should test that the Client connects over the IP family set.
+func TestIPFamily(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerOneRepoList))
+	defer ts.Close()
+	localhost := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+
+	if _, err := New(WithIPv4Only()).GetURL(localhost, nil); err != nil {
+		t.Errorf("TestIPFamily was incorrect, got error: %v, want: <nil>.", err)
+	}
+	if _, err := New(WithIPv4Only(), WithDNSCache(0, 0)).GetURL(localhost, nil); err != nil {
+		t.Errorf("TestIPFamily was incorrect, got error: %v, want: <nil>.", err)
+	}
+	if _, err := New(WithIPv6Only()).GetURL(ts.URL, nil); err == nil {
+		t.Errorf("TestIPFamily was incorrect, got: <nil>, want: an error dialing IPv4 over IPv6.")
+	}
+}
+
+// TestDisableKeepAlives should test that the connections aren't reused.
+func TestDisableKeepAlives(t *testing.T) {
+	var remotes []string
+	var closes []bool
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		remotes = append(remotes, r.RemoteAddr)
+		closes = append(closes, r.Close)
+	}))
+	defer ts.Close()
+
+	c := New(WithDisableKeepAlives())
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetURL(ts.URL, nil); err != nil {
+			t.Fatalf("TestDisableKeepAlives was incorrect, got error: %v", err)
+		}
+	}
+
+	if remotes[0] == remotes[1] || !closes[0] || !closes[1] {
+		t.Errorf("TestDisableKeepAlives was incorrect, got: %v %v, want: two connections closed.", remotes, closes)
+	}
+}
+
+// TestWithDialContext should test that the connections are opened by the dial function.
+func TestWithDialContext(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerOneRepoList))
+	defer ts.Close()
+
+	var dialed []string
+	var d net.Dialer
+	dial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		dialed = append(dialed, network+" "+addr)
+		// Route every host to the test server, like a tunnel.
+		return d.DialContext(ctx, network, ts.Listener.Addr().String())
+	}
+
+	if _, err := New(WithDialContext(dial)).GetURL("http://api.internal/repos", nil); err != nil {
+		t.Fatalf("TestWithDialContext was incorrect, got error: %v", err)
+	}
+	if len(dialed) != 1 || dialed[0] != "tcp api.internal:80" {
+		t.Errorf("TestWithDialContext was incorrect, got: %v, want: [tcp api.internal:80].", dialed)
+	}
+}
+
+// TestWithLocalAddr should test that the connections are opened from the local address.
+func TestWithLocalAddr(t *testing.T) {
+	var remote string
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		remote = r.RemoteAddr
+	}))
+	defer ts.Close()
+
+	if _, err := New(WithLocalAddr(net.ParseIP("127.0.0.1"))).GetURL(ts.URL, nil); err != nil || !strings.HasPrefix(remote, "127.0.0.1:") {
+		t.Errorf("TestWithLocalAddr was incorrect, got: %s, %v, want: 127.0.0.1.", remote, err)
+	}
+	if _, err := New(WithLocalAddr(net.ParseIP("::1"))).GetURL(ts.URL, nil); err == nil {
+		t.Errorf("TestWithLocalAddr was incorrect, got: <nil>, want: an error dialing IPv4 from IPv6.")
+	}
+}
+
+// TestWithInterface should test that the connections are opened from the address of the interface.
+func TestWithInterface(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loopback := ""
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			loopback = iface.Name
+			break
+		}
+	}
+	if loopback == "" {
+		t.Skip("no loopback interface")
+	}
+
+	var remote string
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		remote = r.RemoteAddr
+	}))
+	defer ts.Close()
+
+	if _, err := New(WithInterface(loopback)).GetURL(ts.URL, nil); err != nil || !strings.HasPrefix(remote, "127.") {
+		t.Errorf("TestWithInterface was incorrect, got: %s, %v, want: a loopback address.", remote, err)
+	}
+	if _, err := New(WithInterface("inexistent0")).GetURL(ts.URL, nil); err == nil {
+		t.Errorf("TestWithInterface was incorrect, got: <nil>, want: an error for a missing interface.")
+	}
+}
@@ -0,0 +1,150 @@
+package httpclient
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// DNSLookupFunc resolves host to its addresses, also returning the TTL of the records.
+// A zero TTL means the TTL is unknown.
+type DNSLookupFunc func(ctx context.Context, host string) ([]net.IP, time.Duration, error)
+
+// dnsCacheSweepSize is the number of entries after which expired lookups are swept.
+const dnsCacheSweepSize = 1024
+
+// dnsCache caches successful DNS lookups for their TTL, clamped between minTTL and maxTTL.
+type dnsCache struct {
+	lookup DNSLookupFunc
+	minTTL time.Duration
+	maxTTL time.Duration
+	// clock is the Clock of the Client, see WithClock.
+	clock Clock
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+type dnsEntry struct {
+	ips     []net.IP
+	expires time.Time
+}
+
+// WithDNSCache caches successful DNS lookups made by the Client dialer.
+// Each lookup is kept for the TTL of its records, but never less than minTTL
+// or more than maxTTL. Lookups with an unknown TTL are kept for minTTL.
+func WithDNSCache(minTTL, maxTTL time.Duration) Option {
+	return func(c *Client) {
+		c.dnsCache = &dnsCache{
+			lookup:  lookupIP,
+			minTTL:  minTTL,
+			maxTTL:  maxTTL,
+			entries: make(map[string]dnsEntry),
+		}
+	}
+}
+
+// WithDNSLookup sets the function used by the DNS cache to resolve hosts,
+// e.g. one reporting the TTL of the records. It has no effect without WithDNSCache.
+func WithDNSLookup(lookup DNSLookupFunc) Option {
+	return func(c *Client) {
+		c.dnsLookup = lookup
+	}
+}
+
+// lookupIP resolves host with the default resolver, which doesn't report TTLs.
+func lookupIP(ctx context.Context, host string) ([]net.IP, time.Duration, error) {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+
+	return ips, 0, nil
+}
+
+// resolve returns the addresses of host, from the cache if still valid.
+func (d *dnsCache) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	now := d.clock.Now()
+
+	d.mu.Lock()
+	entry, ok := d.entries[host]
+	d.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.ips, nil
+	}
+
+	ips, ttl, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.entries) >= dnsCacheSweepSize {
+		for h, e := range d.entries {
+			if !now.Before(e.expires) {
+				delete(d.entries, h)
+			}
+		}
+	}
+	d.entries[host] = dnsEntry{ips: ips, expires: now.Add(d.clamp(ttl))}
+
+	return ips, nil
+}
+
+// clamp bounds ttl between minTTL and maxTTL.
+func (d *dnsCache) clamp(ttl time.Duration) time.Duration {
+	if ttl < d.minTTL {
+		return d.minTTL
+	}
+	if d.maxTTL > 0 && ttl > d.maxTTL {
+		return d.maxTTL
+	}
+
+	return ttl
+}
+
+// dialContext wraps dial so that host names are resolved through the cache.
+func (d *dnsCache) dialContext(dial func(ctx context.Context, network, addr string) (net.Conn, error)) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) != nil {
+			return dial(ctx, network, addr)
+		}
+
+		ips, err := d.resolve(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var firstErr error
+		for _, ip := range ips {
+			if (network == "tcp4" && ip.To4() == nil) || (network == "tcp6" && ip.To4() != nil) {
+				continue
+			}
+
+			conn, err := dial(ctx, network, net.JoinHostPort(ip.String(), port))
+			if err == nil {
+				return conn, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if firstErr == nil {
+			firstErr = &net.DNSError{Err: "no suitable address found", Name: host}
+		}
+
+		return nil, firstErr
+	}
+}
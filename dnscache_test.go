@@ -0,0 +1,83 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestDNSCache should test that a host is resolved only once while its lookup is cached.
+func TestDNSCache(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// Force a new dial for each request.
+		w.Header().Set("Connection", "close")
+		fmt.Fprint(w, "{\"data\": \"example data\"}")
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	var lookups int32
+	lookup := func(_ context.Context, host string) ([]net.IP, time.Duration, error) {
+		atomic.AddInt32(&lookups, 1)
+		return []net.IP{net.ParseIP("127.0.0.1")}, time.Hour, nil
+	}
+
+	c := New(WithDNSCache(time.Minute, 10*time.Minute), WithDNSLookup(lookup))
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetURL("http://forge.test:"+u.Port(), nil); err != nil {
+			t.Fatalf("TestDNSCache was incorrect, got error: %v", err)
+		}
+	}
+
+	if lookups != 1 {
+		t.Errorf("TestDNSCache was incorrect, got: %d lookups, want: %d.", lookups, 1)
+	}
+}
+
+// TestDNSCacheExpired should test that a host is resolved again once its lookup expires
+// on the clock of the Client.
+func TestDNSCacheExpired(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Connection", "close")
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	var lookups int32
+	lookup := func(_ context.Context, host string) ([]net.IP, time.Duration, error) {
+		atomic.AddInt32(&lookups, 1)
+		return []net.IP{net.ParseIP("127.0.0.1")}, time.Hour, nil
+	}
+
+	clock := newTestClock()
+	c := New(WithClock(clock), WithDNSCache(time.Minute, 10*time.Minute), WithDNSLookup(lookup))
+	c.GetURL("http://forge.test:"+u.Port(), nil)
+	<-clock.After(11 * time.Minute)
+	c.GetURL("http://forge.test:"+u.Port(), nil)
+
+	if lookups != 2 {
+		t.Errorf("TestDNSCacheExpired was incorrect, got: %d lookups, want: %d.", lookups, 2)
+	}
+}
+
+// TestDNSCacheClamp should test that TTLs are bounded by the floor and the ceiling.
+func TestDNSCacheClamp(t *testing.T) {
+	d := &dnsCache{minTTL: time.Minute, maxTTL: time.Hour}
+
+	for ttl, want := range map[time.Duration]time.Duration{
+		0:                time.Minute,
+		time.Second:      time.Minute,
+		10 * time.Minute: 10 * time.Minute,
+		48 * time.Hour:   time.Hour,
+	} {
+		if got := d.clamp(ttl); got != want {
+			t.Errorf("TestDNSCacheClamp was incorrect, got: %v, want: %v.", got, want)
+		}
+	}
+}
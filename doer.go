@@ -0,0 +1,22 @@
+package httpclient
+
+import "io"
+
+// Doer performs HTTP requests the way Client does.
+// Code depending on a Doer instead of a Client can be tested with a fake,
+// e.g. a DoerFunc, without standing up a server. The helpers decoding the
+// responses have a variant sending the requests through a Doer, e.g.
+// GetYAMLWith or GetRawFileWith.
+type Doer interface {
+	Request(URL string, verb string, headers map[string]string, body io.Reader, opts ...RequestOption) (HTTPResponse, error)
+}
+
+// DoerFunc is an adapter to allow the use of ordinary functions as Doer.
+type DoerFunc func(URL string, verb string, headers map[string]string, body io.Reader, opts ...RequestOption) (HTTPResponse, error)
+
+// Request calls f(URL, verb, headers, body, opts...).
+func (f DoerFunc) Request(URL string, verb string, headers map[string]string, body io.Reader, opts ...RequestOption) (HTTPResponse, error) {
+	return f(URL, verb, headers, body, opts...)
+}
+
+var _ Doer = (*Client)(nil)
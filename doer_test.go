@@ -0,0 +1,51 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestDoerFunc should test that a DoerFunc can replace a Client.
+func TestDoerFunc(t *testing.T) {
+	var d Doer = DoerFunc(func(URL string, verb string, _ map[string]string, _ io.Reader, _ ...RequestOption) (HTTPResponse, error) {
+		return HTTPResponse{
+			Body:   []byte(verb + " " + URL),
+			Status: ResponseStatus{Text: "200 OK", Code: 200},
+		}, nil
+	})
+
+	resp, err := d.Request("http://fake.url", "GET", nil, nil)
+	r := "GET http://fake.url"
+	if err != nil || string(resp.Body) != r {
+		t.Errorf("TestDoerFunc was incorrect, got: %s, want: %s.", resp.Body, r)
+	}
+}
+
+// TestDoerHelpers should test that the helpers send their requests through a Doer.
+func TestDoerHelpers(t *testing.T) {
+	var requested []string
+	d := DoerFunc(func(URL string, verb string, _ map[string]string, _ io.Reader, _ ...RequestOption) (HTTPResponse, error) {
+		requested = append(requested, verb+" "+URL)
+		if strings.Contains(URL, "/-/raw/") {
+			return HTTPResponse{Status: ResponseStatus{Text: "404 Not Found", Code: http.StatusNotFound}}, ErrNotFound
+		}
+		return HTTPResponse{
+			Body:   []byte("name: Medusa\n"),
+			Status: ResponseStatus{Text: "200 OK", Code: http.StatusOK},
+		}, nil
+	})
+
+	var out struct{ Name string }
+	if _, err := GetYAMLWith(d, "http://fake.url/publiccode.yml", nil, &out); err != nil || out.Name != "Medusa" {
+		t.Errorf("TestDoerHelpers was incorrect, got: %+v (%v), want: Medusa.", out, err)
+	}
+	_, rawURL, err := GetRawFileWith(d, "https://gitlab.example.it/group/repo", "", "publiccode.yml", nil)
+	if err != nil || rawURL != "https://gitlab.example.it/group/repo/raw/HEAD/publiccode.yml" {
+		t.Errorf("TestDoerHelpers was incorrect, got: %s (%v), want: the older GitLab route.", rawURL, err)
+	}
+	if len(requested) != 3 || requested[0] != "GET http://fake.url/publiccode.yml" {
+		t.Errorf("TestDoerHelpers was incorrect, got requests: %v, want: 3.", requested)
+	}
+}
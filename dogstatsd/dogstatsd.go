@@ -0,0 +1,104 @@
+// Package dogstatsd records the metrics of an httpclient.Client to a DogStatsD
+// agent (Datadog), with the labels of the metrics, like host, method and
+// status, as tags:
+//
+//	rec, err := dogstatsd.New("127.0.0.1:8125", dogstatsd.WithNamespace("crawler."))
+//	...
+//	defer rec.Close()
+//	client := httpclient.New(httpclient.WithMetrics(rec))
+package dogstatsd
+
+import (
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	httpclient "github.com/italia/httpclient-lib-go"
+)
+
+// Recorder is an httpclient.MetricsRecorder sending the metrics to a
+// DogStatsD agent over UDP, a datagram per metric. Send errors are ignored,
+// as metrics are best effort.
+type Recorder struct {
+	conn      net.Conn
+	namespace string
+	tags      []string
+}
+
+var _ httpclient.MetricsRecorder = (*Recorder)(nil)
+
+// Option configures a Recorder.
+type Option func(*Recorder)
+
+// WithNamespace prefixes the names of the metrics with namespace, e.g. "crawler.".
+func WithNamespace(namespace string) Option {
+	return func(r *Recorder) {
+		r.namespace = namespace
+	}
+}
+
+// WithTags adds tags, in the "key:value" form, to every metric, e.g. "env:production".
+func WithTags(tags ...string) Option {
+	return func(r *Recorder) {
+		r.tags = append(r.tags, tags...)
+	}
+}
+
+// New returns a Recorder sending the metrics to the agent at addr, e.g. "127.0.0.1:8125".
+func New(addr string, opts ...Option) (*Recorder, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{conn: conn}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r, nil
+}
+
+// Close closes the connection to the agent.
+func (r *Recorder) Close() error {
+	return r.conn.Close()
+}
+
+// IncCounter sends an increment of the counter name.
+func (r *Recorder) IncCounter(name string, labels map[string]string) {
+	r.send(name, "1", "c", labels)
+}
+
+// ObserveHistogram sends value to the histogram name.
+func (r *Recorder) ObserveHistogram(name string, value float64, labels map[string]string) {
+	r.send(name, strconv.FormatFloat(value, 'f', -1, 64), "h", labels)
+}
+
+// SetGauge sends the value of the gauge name.
+func (r *Recorder) SetGauge(name string, value float64, labels map[string]string) {
+	r.send(name, strconv.FormatFloat(value, 'f', -1, 64), "g", labels)
+}
+
+// send sends a metric in the DogStatsD format: "name:value|type|#tag:value,...".
+func (r *Recorder) send(name, value, kind string, labels map[string]string) {
+	var sb strings.Builder
+	sb.WriteString(sanitize(r.namespace + name))
+	sb.WriteString(":" + value + "|" + kind)
+
+	tags := append([]string(nil), r.tags...)
+	for k, v := range labels {
+		tags = append(tags, sanitize(k)+":"+sanitize(v))
+	}
+	if len(tags) > 0 {
+		sort.Strings(tags[len(r.tags):])
+		sb.WriteString("|#" + strings.Join(tags, ","))
+	}
+
+	_, _ = r.conn.Write([]byte(sb.String()))
+}
+
+// sanitize replaces the characters reserved by the DogStatsD format.
+func sanitize(s string) string {
+	return strings.NewReplacer("|", "_", ",", "_", "#", "_", "\n", "_").Replace(s)
+}
@@ -0,0 +1,44 @@
+package dogstatsd
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// TestRecorder should test that the metrics are sent in the DogStatsD format, labels as tags.
+func TestRecorder(t *testing.T) {
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("TestRecorder was incorrect, got error: %v", err)
+	}
+	defer agent.Close()
+
+	r, err := New(agent.LocalAddr().String(), WithNamespace("crawler."), WithTags("env:test"))
+	if err != nil {
+		t.Fatalf("TestRecorder was incorrect, got error: %v", err)
+	}
+	defer r.Close()
+
+	labels := map[string]string{"status": "200", "method": "GET", "host": "api.github.com"}
+	r.IncCounter("httpclient_requests_total", labels)
+	r.ObserveHistogram("httpclient_request_duration_seconds", 0.25, labels)
+	r.SetGauge("httpclient_ratelimit_remaining", 41, map[string]string{"host": "api.github.com"})
+
+	want := []string{
+		"crawler.httpclient_requests_total:1|c|#env:test,host:api.github.com,method:GET,status:200",
+		"crawler.httpclient_request_duration_seconds:0.25|h|#env:test,host:api.github.com,method:GET,status:200",
+		"crawler.httpclient_ratelimit_remaining:41|g|#env:test,host:api.github.com",
+	}
+	buf := make([]byte, 512)
+	for _, w := range want {
+		agent.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := agent.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("TestRecorder was incorrect, got error: %v", err)
+		}
+		if got := string(buf[:n]); got != w {
+			t.Errorf("TestRecorder was incorrect, got: %q, want: %q.", got, w)
+		}
+	}
+}
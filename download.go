@@ -0,0 +1,178 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrChecksumMismatch is returned when a downloaded body doesn't match its checksum.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// checksum is a checksum expected for a response body.
+type checksum struct {
+	algorithm string
+	expected  []byte
+}
+
+// WithChecksum verifies the body downloaded by GetToWriter and DownloadFile
+// against expected, the hex encoded digest of algorithm ("sha256" or "md5").
+// Without WithChecksum the body is verified against the Digest or Content-MD5
+// headers of the response, if any.
+func WithChecksum(algorithm, expected string) RequestOption {
+	return func(cfg *requestConfig) {
+		sum, err := hex.DecodeString(expected)
+		if err != nil {
+			// An invalid checksum never matches.
+			sum = []byte(expected)
+		}
+		cfg.checksum = &checksum{algorithm: strings.ToLower(algorithm), expected: sum}
+	}
+}
+
+// newChecksumHash returns the hash computing the digest of algorithm.
+func newChecksumHash(algorithm string) hash.Hash {
+	switch algorithm {
+	case "sha256", "sha-256":
+		return sha256.New()
+	case "md5":
+		return md5.New()
+	}
+
+	return nil
+}
+
+// responseChecksum returns the checksum told by the Digest (RFC 3230) or
+// Content-MD5 headers of resp, nil if none is usable.
+func responseChecksum(resp *http.Response) *checksum {
+	// The headers are about the encoded body, decoded by the transport.
+	if resp.Uncompressed {
+		return nil
+	}
+
+	for _, digest := range strings.Split(resp.Header.Get("Digest"), ",") {
+		i := strings.IndexByte(digest, '=')
+		if i < 0 {
+			continue
+		}
+		algorithm := strings.ToLower(strings.TrimSpace(digest[:i]))
+		if newChecksumHash(algorithm) == nil {
+			continue
+		}
+		if sum, err := base64.StdEncoding.DecodeString(strings.TrimSpace(digest[i+1:])); err == nil {
+			return &checksum{algorithm: algorithm, expected: sum}
+		}
+	}
+
+	if sum, err := base64.StdEncoding.DecodeString(resp.Header.Get("Content-MD5")); err == nil && len(sum) > 0 {
+		return &checksum{algorithm: "md5", expected: sum}
+	}
+
+	return nil
+}
+
+// streamResponse copies the body of resp to cfg.sink, verifying its checksum.
+func streamResponse(resp *http.Response, cfg *requestConfig) (HTTPResponse, error) {
+	r := newHTTPResponse(resp, nil)
+
+	sum := cfg.checksum
+	if sum == nil {
+		sum = responseChecksum(resp)
+	}
+	w := cfg.sink
+	var h hash.Hash
+	if sum != nil {
+		if h = newChecksumHash(sum.algorithm); h == nil {
+			return r, fmt.Errorf("unsupported checksum algorithm %s", sum.algorithm)
+		}
+		w = io.MultiWriter(w, h)
+	}
+
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return r, err
+	}
+	if h != nil && !bytes.Equal(h.Sum(nil), sum.expected) {
+		return r, fmt.Errorf("%w: %s is %x, want %x", ErrChecksumMismatch, sum.algorithm, h.Sum(nil), sum.expected)
+	}
+
+	return r, nil
+}
+
+// GetToWriter retrieves the body of an URL writing it to w instead of
+// HTTPResponse.Body, verifying it as by WithChecksum.
+func GetToWriter(URL string, headers map[string]string, w io.Writer, opts ...RequestOption) (HTTPResponse, error) {
+	return defaultClient.GetToWriter(URL, headers, w, opts...)
+}
+
+// GetToWriter retrieves the body of an URL writing it to w instead of
+// HTTPResponse.Body, verifying it as by WithChecksum.
+func (c *Client) GetToWriter(URL string, headers map[string]string, w io.Writer, opts ...RequestOption) (HTTPResponse, error) {
+	sink := func(cfg *requestConfig) {
+		cfg.sink = w
+	}
+
+	return c.Request(URL, "GET", headers, nil, append(opts, sink)...)
+}
+
+// DownloadFile retrieves the body of an URL to the file at path, verifying it
+// as by WithChecksum. The body is written to a temporary file in the directory
+// of path, renamed to path once downloaded, so that a failed download leaves
+// the file at path as it was.
+func DownloadFile(URL string, headers map[string]string, path string, opts ...RequestOption) (HTTPResponse, error) {
+	return defaultClient.DownloadFile(URL, headers, path, opts...)
+}
+
+// DownloadFile retrieves the body of an URL to the file at path, verifying it
+// as by WithChecksum. The body is written to a temporary file in the directory
+// of path, renamed to path once downloaded, so that a failed download leaves
+// the file at path as it was.
+func (c *Client) DownloadFile(URL string, headers map[string]string, path string, opts ...RequestOption) (HTTPResponse, error) {
+	f, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err == nil {
+		// Keep the mode of the file replaced, if any.
+		mode := os.FileMode(0644)
+		if fi, statErr := os.Stat(path); statErr == nil {
+			mode = fi.Mode().Perm()
+		}
+		err = f.Chmod(mode)
+	}
+	if err != nil {
+		if f != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+		return HTTPResponse{
+			Body:    nil,
+			Status:  ResponseStatus{Text: err.Error(), Code: -1},
+			Headers: nil,
+		}, err
+	}
+
+	resp, err := c.GetToWriter(URL, headers, f, opts...)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		if removeErr := os.Remove(f.Name()); removeErr != nil && !os.IsNotExist(removeErr) {
+			log.Warnf("Can't remove the partial download %s: %v", f.Name(), removeErr)
+		}
+	}
+
+	return resp, err
+}
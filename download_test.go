@@ -0,0 +1,76 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDownloadFile should test that the downloaded files are verified, and left as they were
+// on mismatch.
+func TestDownloadFile(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("archive"))
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "download")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "archive.tar")
+
+	sum := sha256.Sum256([]byte("archive"))
+	if _, err := DownloadFile(ts.URL, nil, path, WithChecksum("sha256", hex.EncodeToString(sum[:]))); err != nil {
+		t.Fatalf("TestDownloadFile was incorrect, got error: %v", err)
+	}
+	if b, _ := ioutil.ReadFile(path); string(b) != "archive" {
+		t.Errorf("TestDownloadFile was incorrect, got: %q, want: \"archive\".", b)
+	}
+
+	for _, p := range []string{path, filepath.Join(dir, "new.tar")} {
+		_, err = DownloadFile(ts.URL, nil, p, WithChecksum("sha256", hex.EncodeToString(make([]byte, 32))))
+		if !errors.Is(err, ErrChecksumMismatch) {
+			t.Errorf("TestDownloadFile was incorrect, got: %v, want: %v.", err, ErrChecksumMismatch)
+		}
+	}
+	if b, _ := ioutil.ReadFile(path); string(b) != "archive" {
+		t.Errorf("TestDownloadFile was incorrect, got: %q, want: the previous download kept.", b)
+	}
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Errorf("TestDownloadFile was incorrect, got: %d files, want: the previous download only.", len(files))
+	}
+}
+
+// TestGetToWriterContentMD5 should test that the body is verified against the Content-MD5 header.
+func TestGetToWriterContentMD5(t *testing.T) {
+	body := []byte("archive")
+	contentMD5 := md5.Sum(body)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-MD5", base64.StdEncoding.EncodeToString(contentMD5[:]))
+		if r.URL.Path == "/corrupted" {
+			w.Write([]byte("archivf"))
+			return
+		}
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	if _, err := GetToWriter(ts.URL, nil, &buf); err != nil || buf.String() != "archive" {
+		t.Errorf("TestGetToWriterContentMD5 was incorrect, got: %q (%v), want: \"archive\".", buf.String(), err)
+	}
+	if _, err := GetToWriter(ts.URL+"/corrupted", nil, &buf); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("TestGetToWriterContentMD5 was incorrect, got: %v, want: %v.", err, ErrChecksumMismatch)
+	}
+}
@@ -0,0 +1,130 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// The environment variables read by NewFromEnv.
+const (
+	// EnvTimeout bounds each request, as Timeouts.Total, e.g. "30s" or "30".
+	EnvTimeout = "HTTPCLIENT_TIMEOUT"
+	// EnvMaxRetries bounds the retries of each request, see WithMaxRetries.
+	EnvMaxRetries = "HTTPCLIENT_MAX_RETRIES"
+	// EnvProxy is the URL of the proxy of the requests, see WithProxy.
+	EnvProxy = "HTTPCLIENT_PROXY"
+	// EnvMaxConcurrent limits the requests in flight, see WithMaxConcurrentRequests.
+	EnvMaxConcurrent = "HTTPCLIENT_MAX_CONCURRENT"
+	// EnvMaxPerHost limits the requests in flight to a host, see WithMaxInflightPerHost.
+	EnvMaxPerHost = "HTTPCLIENT_MAX_PER_HOST"
+	// EnvPolitenessDelay spaces the requests to a host, see WithPolitenessDelay.
+	EnvPolitenessDelay = "HTTPCLIENT_POLITENESS_DELAY"
+	// EnvBandwidthLimit limits the bytes per second read, see WithBandwidthLimit.
+	EnvBandwidthLimit = "HTTPCLIENT_BANDWIDTH_LIMIT"
+	// EnvToken is the bearer token sent with the requests, see WithBearerToken.
+	EnvToken = "HTTPCLIENT_TOKEN"
+	// EnvUserAgent is the User-Agent sent with the requests.
+	EnvUserAgent = "HTTPCLIENT_USER_AGENT"
+)
+
+// NewFromEnv returns a Client configured by the HTTPCLIENT_* environment
+// variables set, see the Env constants, so that the deployments tune it
+// without code changes. The durations are either Go durations, like "1m30s",
+// or seconds. opts are applied after the environment, overriding it.
+// It fails on the values that can't be parsed.
+func NewFromEnv(opts ...Option) (*Client, error) {
+	envOpts, err := envOptions(os.Getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(append(envOpts, opts...)...), nil
+}
+
+// envOptions returns the options of the environment variables read by getenv.
+func envOptions(getenv func(string) string) ([]Option, error) {
+	var opts []Option
+
+	if v := getenv(EnvTimeout); v != "" {
+		d, err := parseEnvDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvTimeout, err)
+		}
+		opts = append(opts, WithTimeouts(Timeouts{Total: d}))
+	}
+	if v := getenv(EnvMaxRetries); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvMaxRetries, err)
+		}
+		opts = append(opts, WithMaxRetries(n))
+	}
+	if v := getenv(EnvProxy); v != "" {
+		u, err := parseProxyURL(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvProxy, err)
+		}
+		opts = append(opts, WithProxy(u))
+	}
+	if v := getenv(EnvMaxConcurrent); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvMaxConcurrent, err)
+		}
+		opts = append(opts, WithMaxConcurrentRequests(n))
+	}
+	if v := getenv(EnvMaxPerHost); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvMaxPerHost, err)
+		}
+		opts = append(opts, WithMaxInflightPerHost(n))
+	}
+	if v := getenv(EnvPolitenessDelay); v != "" {
+		d, err := parseEnvDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvPolitenessDelay, err)
+		}
+		opts = append(opts, WithPolitenessDelay(d))
+	}
+	if v := getenv(EnvBandwidthLimit); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", EnvBandwidthLimit, err)
+		}
+		opts = append(opts, WithBandwidthLimit(n))
+	}
+	if v := getenv(EnvToken); v != "" {
+		opts = append(opts, WithBearerToken(v))
+	}
+	if v := getenv(EnvUserAgent); v != "" {
+		opts = append(opts, WithHeaders(map[string]string{"User-Agent": v}))
+	}
+
+	return opts, nil
+}
+
+// parseProxyURL parses the URL of a proxy, like "http://proxy.local:3128".
+func parseProxyURL(v string) (*url.URL, error) {
+	u, err := url.Parse(v)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q", v)
+	}
+
+	return u, nil
+}
+
+// parseEnvDuration parses a Go duration, like "1m30s", or a number of seconds.
+func parseEnvDuration(v string) (time.Duration, error) {
+	if seconds, err := strconv.ParseFloat(v, 64); err == nil {
+		return time.Duration(seconds * float64(time.Second)), nil
+	}
+
+	return time.ParseDuration(v)
+}
@@ -0,0 +1,57 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestNewFromEnv should test that the Client is configured by the environment variables.
+func TestNewFromEnv(t *testing.T) {
+	hits := 0
+	var authorization, userAgent string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		authorization, userAgent = r.Header.Get("Authorization"), r.Header.Get("User-Agent")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	env := map[string]string{
+		EnvTimeout:       "2.5",
+		EnvMaxRetries:    "1",
+		EnvToken:         "s3cr3t",
+		EnvUserAgent:     "crawler/1.0",
+		EnvMaxPerHost:    "4",
+		EnvMaxConcurrent: "8",
+	}
+	opts, err := envOptions(func(name string) string { return env[name] })
+	if err != nil {
+		t.Fatalf("TestNewFromEnv was incorrect, got: %v, want: no error.", err)
+	}
+	c := New(append(opts, WithClock(newTestClock()))...)
+
+	if c.httpClient.Timeout.Seconds() != 2.5 || c.maxInflightPerHost != 4 {
+		t.Errorf("TestNewFromEnv was incorrect, got: timeout %v, %d per host, want: 2.5s, 4 per host.", c.httpClient.Timeout, c.maxInflightPerHost)
+	}
+	if _, err := c.GetURL(ts.URL, nil); err == nil || hits != 2 {
+		t.Errorf("TestNewFromEnv was incorrect, got: %d hits, %v, want: 2 hits, an error.", hits, err)
+	}
+	if authorization != "Bearer s3cr3t" || userAgent != "crawler/1.0" {
+		t.Errorf("TestNewFromEnv was incorrect, got: %q, %q, want: the token and the user agent.", authorization, userAgent)
+	}
+}
+
+// TestNewFromEnvInvalid should test that the invalid values fail.
+func TestNewFromEnvInvalid(t *testing.T) {
+	for _, env := range []map[string]string{
+		{EnvTimeout: "soon"},
+		{EnvMaxRetries: "many"},
+		{EnvProxy: "proxy.local:3128"},
+		{EnvBandwidthLimit: "1MB"},
+	} {
+		if _, err := envOptions(func(name string) string { return env[name] }); err == nil {
+			t.Errorf("TestNewFromEnvInvalid was incorrect, got: no error, want: an error for %v.", env)
+		}
+	}
+}
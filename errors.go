@@ -0,0 +1,80 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// defaultErrorBodyExcerpt is the default size of the body excerpt of an HTTPError.
+const defaultErrorBodyExcerpt = 1 << 10
+
+// Errors returned for the response statuses, to match with errors.Is.
+// Transport errors are returned as they are, e.g. a *url.Error.
+var (
+	// ErrNotFound is returned for 404 (Not Found) responses.
+	ErrNotFound = errors.New("not found")
+	// ErrForbidden is returned for 403 (Forbidden) responses not due to rate limits.
+	ErrForbidden = errors.New("forbidden resource")
+	// ErrRateLimited is returned when the retries are exhausted on rate limit responses.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrPreconditionFailed is returned for 412 (Precondition Failed) responses,
+	// e.g. to a request WithIfMatch of a resource modified in the meantime.
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrInvalidStatus is returned for the other unsuccessful statuses.
+	ErrInvalidStatus = errors.New("invalid status code")
+)
+
+// HTTPError is returned for unsuccessful responses. It wraps one of the
+// errors above and carries an excerpt of the body, where most APIs explain
+// the failure (e.g. "bad credentials").
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	// BodyExcerpt is the beginning of the body, 1 KB by default (see WithErrorBodyExcerpt).
+	BodyExcerpt []byte
+	// Attempts are the attempts made, in order, when the request was retried.
+	Attempts []Attempt
+	Err      error
+}
+
+// Attempt is a single attempt of a retried request.
+type Attempt struct {
+	Time time.Time
+	// Status is the status code of the response.
+	Status int
+	Err    error
+	// Wait is the time waited after the attempt.
+	Wait time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	msg := e.Err.Error()
+	if e.Status != "" {
+		msg += ": " + e.Status
+	}
+	if excerpt := strings.Join(strings.Fields(string(e.BodyExcerpt)), " "); excerpt != "" {
+		msg += ": " + excerpt
+	}
+	if len(e.Attempts) > 1 {
+		msg += fmt.Sprintf(" (after %d attempts)", len(e.Attempts))
+	}
+
+	return msg
+}
+
+func (e *HTTPError) Unwrap() error {
+	return e.Err
+}
+
+// WithErrorOnNon2xx makes every response with a status other than 2xx return
+// an *HTTPError, along with the populated HTTPResponse, even when the status
+// is otherwise returned successfully: the 304 (Not Modified) of Poll, or the
+// responses stopped by a StatusHandler without an error. The error wraps
+// ErrInvalidStatus, unless the status has its own, e.g. ErrNotFound.
+func WithErrorOnNon2xx() RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.errorOnNon2xx = true
+	}
+}
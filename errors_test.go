@@ -0,0 +1,128 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestStatusErrors should test that the status errors can be matched with errors.Is.
+func TestStatusErrors(t *testing.T) {
+	for code, want := range map[int]error{
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusForbidden:           ErrForbidden,
+		http.StatusTooManyRequests:     ErrRateLimited,
+		http.StatusInternalServerError: ErrInvalidStatus,
+	} {
+		code := code
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if code == http.StatusTooManyRequests {
+				w.Header().Set("Retry-After", "0")
+			}
+			w.WriteHeader(code)
+		}))
+
+		_, err := GetURL(ts.URL, nil)
+		if !errors.Is(err, want) {
+			t.Errorf("TestStatusErrors was incorrect for %d, got: %v, want: %v.", code, err, want)
+		}
+		ts.Close()
+	}
+}
+
+// TestHTTPErrorExcerpt should test that the error carries an excerpt of the body.
+func TestHTTPErrorExcerpt(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, "{\"message\": \"Bad credentials\",\n \"documentation_url\": \"https://docs.github.com/rest\"}")
+	}))
+	defer ts.Close()
+
+	_, err := New(WithErrorBodyExcerpt(30)).GetURL(ts.URL, nil)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized || string(httpErr.BodyExcerpt) != `{"message": "Bad credentials",` {
+		t.Fatalf("TestHTTPErrorExcerpt was incorrect, got error: %v", err)
+	}
+
+	r := "invalid status code: 401 Unauthorized: {\"message\": \"Bad credentials\","
+	if err.Error() != r {
+		t.Errorf("TestHTTPErrorExcerpt was incorrect, got: %s, want: %s.", err, r)
+	}
+}
+
+// TestErrorOnNon2xx should test that the statuses returned successfully are errors with WithErrorOnNon2xx.
+func TestErrorOnNon2xx(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/poll" {
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			return
+		}
+		w.WriteHeader(http.StatusTeapot)
+		fmt.Fprint(w, "teapot")
+	}))
+	defer ts.Close()
+
+	c := New(WithClock(newTestClock()), WithStatusHandler(http.StatusTeapot, func(*HTTPResponse) (StatusAction, error) {
+		return StatusStop, nil
+	}))
+	resp, err := c.GetURL(ts.URL, nil)
+	if err != nil || resp.Status.Code != http.StatusTeapot {
+		t.Errorf("TestErrorOnNon2xx was incorrect, got: %d %v, want: %d <nil>.", resp.Status.Code, err, http.StatusTeapot)
+	}
+	resp, err = c.GetURL(ts.URL, nil, WithErrorOnNon2xx())
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || !errors.Is(err, ErrInvalidStatus) || httpErr.StatusCode != http.StatusTeapot || string(resp.Body) != "teapot" {
+		t.Errorf("TestErrorOnNon2xx was incorrect, got: %q %v, want: an *HTTPError.", resp.Body, err)
+	}
+
+	type outcome struct {
+		code int
+		err  error
+	}
+	outcomes := make(chan outcome, 2)
+	p := c.Poll(context.Background(), ts.URL+"/poll", nil, time.Second, func(resp HTTPResponse, err error) {
+		select {
+		case outcomes <- outcome{resp.Status.Code, err}:
+		default:
+		}
+	}, WithErrorOnNon2xx())
+	first, second := <-outcomes, <-outcomes
+	p.Stop()
+	if first.code != http.StatusOK || first.err != nil {
+		t.Errorf("TestErrorOnNon2xx was incorrect, got: %d %v, want: 200 <nil>.", first.code, first.err)
+	}
+	if !errors.As(second.err, &httpErr) || httpErr.StatusCode != http.StatusNotModified {
+		t.Errorf("TestErrorOnNon2xx was incorrect, got: %d %v, want: an *HTTPError for the 304.", second.code, second.err)
+	}
+}
+
+// TestHTTPErrorAttempts should test that the error of exhausted retries carries the attempts.
+func TestHTTPErrorAttempts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	_, err := New(WithClock(clock)).GetURL(ts.URL, nil)
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || len(httpErr.Attempts) != 8 {
+		t.Fatalf("TestHTTPErrorAttempts was incorrect, got error: %v, want: 8 attempts.", err)
+	}
+
+	start := time.Unix(1600000000, 0)
+	for i, a := range httpErr.Attempts {
+		if a.Status != http.StatusTooManyRequests || a.Wait != 2*time.Second || !a.Time.Equal(start.Add(time.Duration(i)*2*time.Second)) {
+			t.Errorf("TestHTTPErrorAttempts was incorrect, got attempt %d: %+v", i, a)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// The types of the events of a request.
+const (
+	// EventRequestStart is logged before the first attempt of a request.
+	EventRequestStart = "request_start"
+	// EventAttempt is logged after each attempt, with its status or error.
+	EventAttempt = "attempt"
+	// EventRetry is logged when an attempt is retried, with the time waited.
+	EventRetry = "retry"
+	// EventRequestDone is logged when the request returns, once for a request
+	// to mirrors or hedged.
+	EventRequestDone = "request_done"
+)
+
+// Event is a machine-readable event of a request. The JSON field names are stable.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Type   string    `json:"event"`
+	Method string    `json:"method"`
+	URL    string    `json:"url"`
+	// Attempt is the number of the attempt, from 1.
+	Attempt int `json:"attempt,omitempty"`
+	// Status is the status code of the response, 0 if there was none.
+	Status int `json:"status,omitempty"`
+	// Wait is the time waited before retrying, Duration the time taken by the request.
+	Wait     time.Duration `json:"wait_ns,omitempty"`
+	Duration time.Duration `json:"duration_ns,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	// FromCache reports whether the response was served by a caching transport.
+	FromCache bool `json:"from_cache,omitempty"`
+	// TraceID is the W3C trace id of the request, see WithTraceContext.
+	TraceID string `json:"trace_id,omitempty"`
+}
+
+// Logger receives the events of the requests of a Client, see WithLogger.
+// It must be safe for concurrent use.
+type Logger interface {
+	LogEvent(e Event)
+}
+
+// WithLogger sends the events of the requests to l, in addition to the logrus logs.
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithJSONLog writes the events of the requests to w as JSON, one per line,
+// e.g. to be ingested by ELK without parsing the text logs.
+func WithJSONLog(w io.Writer) Option {
+	return WithLogger(NewJSONLogger(w))
+}
+
+// jsonLogger is a Logger writing the events as JSON lines.
+type jsonLogger struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONLogger returns a Logger writing the events to w as JSON, one per line.
+func NewJSONLogger(w io.Writer) Logger {
+	return &jsonLogger{enc: json.NewEncoder(w)}
+}
+
+func (l *jsonLogger) LogEvent(e Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// A failing log must not fail the request.
+	_ = l.enc.Encode(e)
+}
+
+// emit sends e, timestamped, to the Logger of the Client, if any, and records
+// its metrics and expvar counters.
+func (c *Client) emit(e Event) {
+	c.recordMetrics(e)
+	c.recordExpvar(e)
+	if c.logger == nil {
+		return
+	}
+	e.Time = c.clock.Now()
+	c.logger.LogEvent(e)
+}
+
+// errorString returns the message of err, empty if nil.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return err.Error()
+}
+
+// statusCode returns the status code of resp, 0 if there was no response.
+func statusCode(resp HTTPResponse) int {
+	if resp.Status.Code < 0 {
+		return 0
+	}
+
+	return resp.Status.Code
+}
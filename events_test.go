@@ -0,0 +1,60 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestWithJSONLog should test that the events of a retried request are written as JSON lines.
+func TestWithJSONLog(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if hits++; hits == 1 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	c := New(WithClock(newTestClock()), WithJSONLog(&buf))
+	if _, err := c.GetURL(ts.URL, nil); err != nil {
+		t.Fatalf("TestWithJSONLog was incorrect, got error: %v", err)
+	}
+
+	var got []Event
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var e Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("TestWithJSONLog was incorrect, got invalid JSON %q: %v", line, err)
+		}
+		got = append(got, e)
+	}
+
+	want := []Event{
+		{Type: EventRequestStart},
+		{Type: EventAttempt, Attempt: 1, Status: 429},
+		{Type: EventRetry, Attempt: 1, Status: 429, Wait: 2e9},
+		{Type: EventAttempt, Attempt: 2, Status: 200},
+		{Type: EventRequestDone, Attempt: 2, Status: 200, Duration: 2e9},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("TestWithJSONLog was incorrect, got: %+v, want: %+v.", got, want)
+	}
+	for i, e := range got {
+		w := want[i]
+		if e.Type != w.Type || e.Attempt != w.Attempt || e.Status != w.Status || e.Wait != w.Wait || e.Duration != w.Duration || e.Method != "GET" || e.URL == "" {
+			t.Errorf("TestWithJSONLog was incorrect, got: %+v, want: %+v.", e, w)
+		}
+	}
+
+	if !strings.Contains(buf.String(), `"event":"request_done"`) {
+		t.Errorf("TestWithJSONLog was incorrect, got: %s, want: the event field names.", buf.String())
+	}
+}
@@ -0,0 +1,106 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// existsStatus reports the statuses answered by Exists.
+func existsStatus(code int) bool {
+	switch {
+	case code >= 300 && code <= 399:
+		return true
+	case code == http.StatusNotFound, code == http.StatusGone:
+		return true
+	case code == http.StatusMethodNotAllowed, code == http.StatusNotImplemented:
+		return true
+	}
+
+	return false
+}
+
+// Exists reports whether the resource at URL exists, e.g. a raw file on a forge.
+// It performs a HEAD request, falling back to a GET of the first byte when HEAD
+// is rejected. 2xx and 3xx statuses mean true, 404 and 410 false, other
+// statuses are returned as errors.
+func Exists(URL string, headers map[string]string) (bool, error) {
+	return defaultClient.Exists(URL, headers)
+}
+
+// Exists reports whether the resource at URL exists, e.g. a raw file on a forge.
+// It performs a HEAD request, falling back to a GET of the first byte when HEAD
+// is rejected. 2xx and 3xx statuses mean true, 404 and 410 false, other
+// statuses are returned as errors.
+func (c *Client) Exists(URL string, headers map[string]string) (bool, error) {
+	returnStatus := func(cfg *requestConfig) {
+		cfg.returnStatus = existsStatus
+	}
+
+	resp, err := c.Head(URL, headers, returnStatus)
+	if err != nil {
+		return false, err
+	}
+
+	switch code := resp.Status.Code; {
+	case code >= 200 && code <= 399:
+		return true, nil
+	case code == http.StatusNotFound, code == http.StatusGone:
+		return false, nil
+	}
+
+	return false, fmt.Errorf("%w: %s", ErrInvalidStatus, resp.Status.Text)
+}
+
+// WithHeadFallback retries as a GET of the first byte, with the body
+// discarded, the HEAD requests rejected with 405 or 501, as many servers do.
+// The 206 and 416 answers are returned as the 200 of the HEAD, with the
+// Content-Length of the whole resource from the Content-Range, 0 for the
+// empty resources answering 416.
+func WithHeadFallback() RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.headFallback = true
+	}
+}
+
+// Head retrieves status and response headers from an URL, falling back to
+// a GET as by WithHeadFallback.
+func Head(URL string, headers map[string]string, opts ...RequestOption) (HTTPResponse, error) {
+	return defaultClient.Head(URL, headers, opts...)
+}
+
+// Head retrieves status and response headers from an URL, falling back to
+// a GET as by WithHeadFallback.
+func (c *Client) Head(URL string, headers map[string]string, opts ...RequestOption) (HTTPResponse, error) {
+	return c.Request(URL, "HEAD", headers, nil, append(opts, WithHeadFallback())...)
+}
+
+// asHeadResponse turns resp, the answer to the GET of the first byte sent
+// for a HEAD, into the answer to the HEAD, see WithHeadFallback.
+func asHeadResponse(resp *http.Response) {
+	var length string
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// "bytes 0-0/1234", the length being "*" if unknown.
+		r := resp.Header.Get("Content-Range")
+		i := strings.LastIndex(r, "/")
+		if i < 0 {
+			return
+		}
+		if _, err := strconv.ParseInt(r[i+1:], 10, 64); err != nil {
+			return
+		}
+		length = r[i+1:]
+	case http.StatusRequestedRangeNotSatisfiable:
+		// The first byte is past the end of an empty resource.
+		length = "0"
+	default:
+		return
+	}
+
+	resp.StatusCode = http.StatusOK
+	resp.Status = "200 OK"
+	resp.Header.Del("Content-Range")
+	resp.Header.Set("Content-Length", length)
+}
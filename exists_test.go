@@ -0,0 +1,80 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestExists should test the mapping of the statuses to the existence of the resource.
+func TestExists(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/found", handlerOneRepoList)
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	})
+	mux.HandleFunc("/no-head", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusPartialContent)
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "HEAD" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Range", "bytes */0")
+		w.WriteHeader(http.StatusRequestedRangeNotSatisfiable)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for path, want := range map[string]bool{"/found": true, "/missing": false, "/gone": false, "/no-head": true, "/empty": true} {
+		got, err := Exists(ts.URL+path, nil)
+		if err != nil || got != want {
+			t.Errorf("TestExists was incorrect for %s, got: %v (%v), want: %v.", path, got, err, want)
+		}
+	}
+}
+
+// TestExistsError should test that unexpected statuses are returned as errors.
+func TestExistsError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer ts.Close()
+
+	if _, err := Exists(ts.URL, nil); err == nil {
+		t.Errorf("TestExistsError was incorrect, got no error for a 403")
+	}
+}
+
+// TestHeadFallback should test that a rejected HEAD is retried as a GET without body.
+func TestHeadFallback(t *testing.T) {
+	var methods []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.Header.Get("Range"))
+		if r.Method == "HEAD" {
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader("body"))
+	}))
+	defer ts.Close()
+
+	resp, err := Head(ts.URL, nil)
+	if err != nil || resp.Body != nil || resp.Headers.Get("ETag") != `"v1"` {
+		t.Errorf("TestHeadFallback was incorrect, got: %q %v (%v), want: the headers only.", resp.Body, resp.Headers, err)
+	}
+	if resp.Status.Code != http.StatusOK || resp.Headers.Get("Content-Length") != "4" {
+		t.Errorf("TestHeadFallback was incorrect, got: %d with Content-Length %q, want: 200 with the length of the body.", resp.Status.Code, resp.Headers.Get("Content-Length"))
+	}
+	if len(methods) != 2 || methods[0] != "HEAD " || methods[1] != "GET bytes=0-0" {
+		t.Errorf("TestHeadFallback was incorrect, got: %q, want: [\"HEAD \" \"GET bytes=0-0\"].", methods)
+	}
+}
@@ -0,0 +1,50 @@
+package httpclient
+
+import (
+	"expvar"
+	"net/http"
+)
+
+// The counters published by WithExpvar.
+const (
+	expvarRequests        = "requests"
+	expvarRetries         = "retries"
+	expvarRateLimitSleeps = "ratelimit_sleeps"
+	expvarCacheHits       = "cache_hits"
+)
+
+// WithExpvar publishes under name, through expvar (e.g. at /debug/vars), the
+// counters of the requests, the retries, the sleeps on rate limits and the
+// responses served by the cache. Clients with the same name share the counters.
+func WithExpvar(name string) Option {
+	return func(c *Client) {
+		if m, ok := expvar.Get(name).(*expvar.Map); ok {
+			c.expvars = m
+			return
+		}
+		c.expvars = expvar.NewMap(name)
+		for _, key := range []string{expvarRequests, expvarRetries, expvarRateLimitSleeps, expvarCacheHits} {
+			c.expvars.Add(key, 0)
+		}
+	}
+}
+
+// recordExpvar counts the event e in the expvar counters of the Client, if any.
+func (c *Client) recordExpvar(e Event) {
+	if c.expvars == nil {
+		return
+	}
+
+	switch e.Type {
+	case EventRetry:
+		c.expvars.Add(expvarRetries, 1)
+		if e.Status == http.StatusTooManyRequests || e.Status == http.StatusForbidden {
+			c.expvars.Add(expvarRateLimitSleeps, 1)
+		}
+	case EventRequestDone:
+		c.expvars.Add(expvarRequests, 1)
+		if e.FromCache {
+			c.expvars.Add(expvarCacheHits, 1)
+		}
+	}
+}
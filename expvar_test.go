@@ -0,0 +1,39 @@
+package httpclient
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWithExpvar should test that the counters of the requests are published.
+func TestWithExpvar(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if hits++; hits == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-From-Cache", "1")
+	}))
+	defer ts.Close()
+
+	c := New(WithClock(newTestClock()), WithExpvar("httpclient_test"))
+	if _, err := c.GetURL(ts.URL, nil); err != nil {
+		t.Fatalf("TestWithExpvar was incorrect, got error: %v", err)
+	}
+	// A second Client shares the counters instead of panicking.
+	if _, err := New(WithExpvar("httpclient_test")).GetURL(ts.URL, nil); err != nil {
+		t.Fatalf("TestWithExpvar was incorrect, got error: %v", err)
+	}
+
+	m := expvar.Get("httpclient_test").(*expvar.Map)
+	want := map[string]string{"requests": "2", "retries": "1", "ratelimit_sleeps": "1", "cache_hits": "2"}
+	for key, v := range want {
+		if got := m.Get(key).String(); got != v {
+			t.Errorf("TestWithExpvar was incorrect, got: %s for %s, want: %s.", got, key, v)
+		}
+	}
+}
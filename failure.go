@@ -0,0 +1,31 @@
+package httpclient
+
+import (
+	"context"
+	"time"
+)
+
+// FailureReport describes a request failed after its attempts, see WithFailureReporter.
+type FailureReport struct {
+	Method string
+	URL    string
+	// Attempts is the number of requests sent, Retried the attempts ended
+	// waiting to retry, with their waits, as in HTTPError.Attempts.
+	Attempts int
+	Retried  []Attempt
+	Duration time.Duration
+	// Response is the last response, Status.Code -1 if there was none.
+	Response HTTPResponse
+	Err      error
+	// TraceID is the W3C trace id of the request, see WithTraceContext.
+	TraceID string
+}
+
+// WithFailureReporter calls report with the report of every request that
+// finally fails, after the retries, e.g. to push them to Sentry or to an
+// alerting channel. ctx is the context of the request.
+func WithFailureReporter(report func(ctx context.Context, r FailureReport)) Option {
+	return func(c *Client) {
+		c.failureReporter = report
+	}
+}
@@ -0,0 +1,115 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWithFailureReporter should test that the requests failed after the retries are reported.
+func TestWithFailureReporter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ok" {
+			return
+		}
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("slow down"))
+	}))
+	defer ts.Close()
+
+	type key struct{}
+	var reports []FailureReport
+	c := New(WithClock(newTestClock()), WithFailureReporter(func(ctx context.Context, r FailureReport) {
+		if ctx.Value(key{}) != "crawl" {
+			t.Errorf("TestWithFailureReporter was incorrect, got a context without the value of the request.")
+		}
+		reports = append(reports, r)
+	}))
+
+	ctx := context.WithValue(context.Background(), key{}, "crawl")
+	if _, err := c.RequestContext(ctx, ts.URL+"/ok", "GET", nil, nil); err != nil {
+		t.Fatalf("TestWithFailureReporter was incorrect, got error: %v", err)
+	}
+	_, err := c.RequestContext(ctx, ts.URL+"/limited", "GET", nil, nil)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("TestWithFailureReporter was incorrect, got error: %v, want: %v.", err, ErrRateLimited)
+	}
+
+	if len(reports) != 1 {
+		t.Fatalf("TestWithFailureReporter was incorrect, got: %d reports, want: 1.", len(reports))
+	}
+	r := reports[0]
+	if r.URL != ts.URL+"/limited" || r.Attempts != 8 || len(r.Retried) != 8 || r.Err != err || string(r.Response.Body) != "slow down" {
+		t.Errorf("TestWithFailureReporter was incorrect, got: %+v, want: the report of the 8 rate limited attempts.", r)
+	}
+}
+
+// failingReader is a body failing to be read.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("read failed")
+}
+
+// TestWithFailureReporterBeforeSending should test that the requests failed before
+// their first attempt are reported too.
+func TestWithFailureReporterBeforeSending(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+		}
+	}))
+	defer ts.Close()
+
+	var reports []FailureReport
+	c := New(WithClock(newTestClock()), WithRobots("crawler"), WithFailureReporter(func(_ context.Context, r FailureReport) {
+		reports = append(reports, r)
+	}))
+
+	c.GetURL("http://[::1", nil)
+	c.GetURL(ts.URL+"/private", nil)
+	c.PostURL(ts.URL+"/upload", nil, failingReader{})
+	if len(reports) != 3 {
+		t.Fatalf("TestWithFailureReporterBeforeSending was incorrect, got: %d reports, want: 3.", len(reports))
+	}
+	if !errors.Is(reports[1].Err, ErrDisallowedByRobots) || reports[1].URL != ts.URL+"/private" || reports[1].Attempts != 0 {
+		t.Errorf("TestWithFailureReporterBeforeSending was incorrect, got: %+v, want: the request disallowed by robots.txt.", reports[1])
+	}
+}
+
+// TestWithFailureReporterMirrors should test that a request succeeding on a mirror
+// isn't reported, and that one failing on all of them is reported once.
+func TestWithFailureReporterMirrors(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer mirror.Close()
+
+	var reports []FailureReport
+	c := New(WithClock(newTestClock()), WithMaxRetries(0), WithFailureReporter(func(_ context.Context, r FailureReport) {
+		reports = append(reports, r)
+	}))
+
+	if _, err := c.GetURL(primary.URL, nil, WithMirrors(mirror.URL)); err != nil {
+		t.Fatalf("TestWithFailureReporterMirrors was incorrect, got error: %v", err)
+	}
+	if len(reports) != 0 {
+		t.Fatalf("TestWithFailureReporterMirrors was incorrect, got: %d reports, want: 0.", len(reports))
+	}
+
+	if _, err := c.GetURL(primary.URL+"/missing", nil, WithMirrors(mirror.URL+"/missing")); err == nil {
+		t.Fatalf("TestWithFailureReporterMirrors was incorrect, got: no error, want: an error.")
+	}
+	if len(reports) != 1 || reports[0].URL != primary.URL+"/missing" {
+		t.Errorf("TestWithFailureReporterMirrors was incorrect, got: %+v, want: 1 report of the primary URL.", reports)
+	}
+}
@@ -0,0 +1,130 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	headerRateLimit    = "X-RateLimit-Limit"
+	headerRateUsed     = "X-RateLimit-Used"
+	headerRateResource = "X-RateLimit-Resource"
+)
+
+// GraphQLError is an error reported in the "errors" member of a GraphQL response.
+type GraphQLError struct {
+	Message   string        `json:"message"`
+	Type      string        `json:"type,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+	Locations []struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"locations,omitempty"`
+}
+
+// GraphQLErrors is returned when a GraphQL response reports errors.
+// The data, if any, is decoded anyway.
+type GraphQLErrors []GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Message)
+	}
+
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+// RateLimitInfo is the rate limit state reported by the X-RateLimit-* headers,
+// e.g. by the GitHub GraphQL API.
+type RateLimitInfo struct {
+	Limit     int
+	Remaining int
+	Used      int
+	Reset     time.Time
+	Resource  string
+}
+
+// ParseRateLimit returns the rate limit state reported by the X-RateLimit-* headers.
+// Missing headers leave the fields to their zero value.
+func ParseRateLimit(h http.Header) RateLimitInfo {
+	info := RateLimitInfo{Resource: h.Get(headerRateResource)}
+	info.Limit, _ = strconv.Atoi(h.Get(headerRateLimit))
+	info.Remaining, _ = strconv.Atoi(h.Get(headerRateRemaining))
+	info.Used, _ = strconv.Atoi(h.Get(headerRateUsed))
+	if reset, err := strconv.ParseInt(h.Get(headerRateReset), 10, 64); err == nil {
+		info.Reset = time.Unix(reset, 0)
+	}
+
+	return info
+}
+
+// PostGraphQL sends query with variables to the GraphQL endpoint at URL and
+// decodes the "data" member of the response into out.
+// GraphQL errors are returned as GraphQLErrors; the rate limit state can be
+// read from the response headers with ParseRateLimit.
+func PostGraphQL(URL string, headers map[string]string, query string, variables map[string]interface{}, out interface{}) (HTTPResponse, error) {
+	return defaultClient.PostGraphQL(URL, headers, query, variables, out)
+}
+
+// PostGraphQL sends query with variables to the GraphQL endpoint at URL and
+// decodes the "data" member of the response into out.
+// GraphQL errors are returned as GraphQLErrors; the rate limit state can be
+// read from the response headers with ParseRateLimit.
+func (c *Client) PostGraphQL(URL string, headers map[string]string, query string, variables map[string]interface{}, out interface{}) (HTTPResponse, error) {
+	return PostGraphQLWith(c, URL, headers, query, variables, out)
+}
+
+// PostGraphQLWith is PostGraphQL sending the request through d, e.g. a fake
+// of the tests.
+func PostGraphQLWith(d Doer, URL string, headers map[string]string, query string, variables map[string]interface{}, out interface{}) (HTTPResponse, error) {
+	envelope, err := json.Marshal(struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables,omitempty"`
+	}{query, variables})
+	if err != nil {
+		return HTTPResponse{}, err
+	}
+
+	h := map[string]string{
+		"Content-Type": "application/json",
+		"Accept":       "application/json",
+	}
+	for k, v := range headers {
+		h[k] = v
+	}
+
+	resp, err := d.Request(URL, "POST", h, bytes.NewReader(envelope))
+	if err != nil {
+		return resp, err
+	}
+
+	if rate := ParseRateLimit(resp.Headers); rate.Limit > 0 {
+		log.Debugf("GraphQL rate limit: %d/%d used, resets at %v - Resource: %s", rate.Used, rate.Limit, rate.Reset, URL)
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+	if err := json.Unmarshal(resp.Body, &result); err != nil {
+		return resp, err
+	}
+
+	if out != nil && len(result.Data) > 0 && string(result.Data) != "null" {
+		if err := json.Unmarshal(result.Data, out); err != nil {
+			return resp, err
+		}
+	}
+	if len(result.Errors) > 0 {
+		return resp, result.Errors
+	}
+
+	return resp, nil
+}
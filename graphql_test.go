@@ -0,0 +1,63 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// handlerGraphQL echoes the "login" variable and reports an error when it's empty.
+func handlerGraphQL(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Query     string                 `json:"query"`
+		Variables map[string]interface{} `json:"variables"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+
+	w.Header().Set("X-RateLimit-Limit", "5000")
+	w.Header().Set("X-RateLimit-Remaining", "4999")
+	w.Header().Set("X-RateLimit-Used", "1")
+	w.Header().Set("X-RateLimit-Reset", "1600000000")
+
+	if req.Variables["login"] == "" {
+		fmt.Fprint(w, `{"data": null, "errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a User"}]}`)
+		return
+	}
+	fmt.Fprintf(w, `{"data": {"user": {"login": %q}}}`, req.Variables["login"])
+}
+
+// TestPostGraphQL should test that the data of a GraphQL response is decoded.
+func TestPostGraphQL(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerGraphQL))
+	defer ts.Close()
+
+	var out struct {
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	}
+	resp, err := PostGraphQL(ts.URL, nil, "query($login: String!) { user(login: $login) { login } }", map[string]interface{}{"login": "italia"}, &out)
+	if err != nil || out.User.Login != "italia" {
+		t.Errorf("TestPostGraphQL was incorrect, got: %v (%v), want: %s.", out, err, "italia")
+	}
+
+	rate := ParseRateLimit(resp.Headers)
+	if rate.Limit != 5000 || rate.Remaining != 4999 || rate.Used != 1 || rate.Reset.Unix() != 1600000000 {
+		t.Errorf("TestPostGraphQL was incorrect, got rate limit: %+v", rate)
+	}
+}
+
+// TestPostGraphQLErrors should test that GraphQL errors are returned as GraphQLErrors.
+func TestPostGraphQLErrors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerGraphQL))
+	defer ts.Close()
+
+	_, err := PostGraphQL(ts.URL, nil, "query { user(login: \"\") { login } }", map[string]interface{}{"login": ""}, nil)
+	var gqlErrs GraphQLErrors
+	if !errors.As(err, &gqlErrs) || gqlErrs[0].Type != "NOT_FOUND" {
+		t.Errorf("TestPostGraphQLErrors was incorrect, got error: %v", err)
+	}
+}
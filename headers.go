@@ -0,0 +1,115 @@
+package httpclient
+
+import "net/http"
+
+// WithHeaders sets the headers sent with every request of the Client.
+// The headers of a single request are merged as by mergeHeaders.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		c.headers = make(http.Header, len(headers))
+		for k, v := range headers {
+			c.headers.Set(k, v)
+		}
+	}
+}
+
+// WithBearerToken sends "Authorization: Bearer token" with every request of
+// the Client, unless the request or WithHeaders set another Authorization.
+// WithCredentials sends it to the matching hosts only.
+func WithBearerToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithHeader sends the values of h with the request, headers with
+// repeated values included. Each header of h replaces the one with the same
+// name of the headers argument and of the defaults of the Client.
+func WithHeader(h http.Header) RequestOption {
+	return func(cfg *requestConfig) {
+		if cfg.header == nil {
+			cfg.header = make(http.Header, len(h))
+		}
+		for k, values := range h {
+			for _, v := range values {
+				cfg.header.Add(k, v)
+			}
+		}
+	}
+}
+
+// HeaderValues returns all the values of the header name of r, in the order
+// received, e.g. each Link or Set-Cookie header, while Headers.Get returns the
+// first one only. The keys not in canonical form, as set by the transports
+// bypassing http.Header, are matched as well.
+func (r HTTPResponse) HeaderValues(name string) []string {
+	values := append([]string(nil), r.Headers.Values(name)...)
+	canonical := http.CanonicalHeaderKey(name)
+	for k, v := range r.Headers {
+		if k != canonical && http.CanonicalHeaderKey(k) == canonical {
+			values = append(values, v...)
+		}
+	}
+
+	return values
+}
+
+// setHeader sets the header name of the request, as by WithHeader.
+func (cfg *requestConfig) setHeader(name, value string) {
+	if cfg.header == nil {
+		cfg.header = make(http.Header)
+	}
+	cfg.header.Set(name, value)
+}
+
+// mergeHeaders returns the default headers of the Client merged with the
+// headers of a request: a header of the request replaces the default one
+// with the same name, and an empty value removes it, e.g. to not send the
+// default Authorization to another host. The headers set by WithHeader
+// are merged last.
+func (c *Client) mergeHeaders(headers map[string]string, extra http.Header) http.Header {
+	h := c.headers.Clone()
+	if h == nil {
+		h = make(http.Header, len(headers))
+	}
+	if c.token != "" && h.Get("Authorization") == "" {
+		h.Set("Authorization", "Bearer "+c.token)
+	}
+	for k, v := range headers {
+		if v == "" {
+			h.Del(k)
+			continue
+		}
+		h.Set(k, v)
+	}
+	for k, values := range extra {
+		h[http.CanonicalHeaderKey(k)] = append([]string(nil), values...)
+	}
+
+	return h
+}
+
+// requestTrailer is a trailer of the request, with the value computed from the body.
+type requestTrailer struct {
+	name  string
+	value func(body []byte) string
+}
+
+// WithTrailer sends the trailer name after the body of the request, with the
+// value returned by fn for the body, e.g. its checksum. The body is sent with
+// the chunked transfer encoding, which trailers require.
+func WithTrailer(name string, fn func(body []byte) string) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.trailers = append(cfg.trailers, requestTrailer{name: name, value: fn})
+	}
+}
+
+// setTrailers sets on req the trailers of cfg for body.
+func (cfg *requestConfig) setTrailers(req *http.Request, body []byte) {
+	req.Trailer = make(http.Header, len(cfg.trailers))
+	for _, t := range cfg.trailers {
+		req.Trailer.Set(t.name, t.value(body))
+	}
+	// An unknown length makes the transport use the chunked encoding.
+	req.ContentLength = -1
+}
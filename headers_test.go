@@ -0,0 +1,121 @@
+package httpclient
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestMergeHeaders should test that the headers of the request replace or remove the default ones.
+func TestMergeHeaders(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r.Header
+	}))
+	defer ts.Close()
+
+	c := New(WithHeaders(map[string]string{"Authorization": "token secret", "Accept": "application/json"}))
+	if _, err := c.GetURL(ts.URL, map[string]string{"authorization": "", "accept": "text/plain", "X-Custom": "1"}); err != nil {
+		t.Fatalf("TestMergeHeaders was incorrect, got error: %v", err)
+	}
+
+	if _, ok := got["Authorization"]; ok {
+		t.Errorf("TestMergeHeaders was incorrect, got Authorization: %s, want: none.", got.Get("Authorization"))
+	}
+	if got.Get("Accept") != "text/plain" || got.Get("X-Custom") != "1" {
+		t.Errorf("TestMergeHeaders was incorrect, got: %v, want: Accept text/plain and X-Custom 1.", got)
+	}
+
+	if _, err := c.GetURL(ts.URL, nil); err != nil || got.Get("Authorization") != "token secret" {
+		t.Errorf("TestMergeHeaders was incorrect, got Authorization: %s (%v), want: token secret.", got.Get("Authorization"), err)
+	}
+}
+
+// TestWithHeader should test that the repeated values of WithHeader are sent.
+func TestWithHeader(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r.Header
+	}))
+	defer ts.Close()
+
+	h := http.Header{}
+	h.Add("Accept", "application/vnd.github.v3+json")
+	h.Add("Accept", "application/json")
+	if _, err := GetURL(ts.URL, map[string]string{"Accept": "text/plain"}, WithHeader(h)); err != nil {
+		t.Fatalf("TestWithHeader was incorrect, got error: %v", err)
+	}
+
+	if accept := got.Values("Accept"); len(accept) != 2 || accept[0] != "application/vnd.github.v3+json" || accept[1] != "application/json" {
+		t.Errorf("TestWithHeader was incorrect, got: %v, want: both the values of WithHeader.", accept)
+	}
+}
+
+// TestWithTrailer should test that the trailers are sent after the body.
+func TestWithTrailer(t *testing.T) {
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		got = r.Trailer
+	}))
+	defer ts.Close()
+
+	checksum := func(body []byte) string {
+		return fmt.Sprintf("sha256=%x", sha256.Sum256(body))
+	}
+	if _, err := PostURL(ts.URL, nil, strings.NewReader("payload"), WithTrailer("Digest", checksum)); err != nil {
+		t.Fatalf("TestWithTrailer was incorrect, got error: %v", err)
+	}
+
+	if r := checksum([]byte("payload")); got.Get("Digest") != r {
+		t.Errorf("TestWithTrailer was incorrect, got: %s, want: %s.", got.Get("Digest"), r)
+	}
+}
+
+// TestHeaderValues should test that all the values of the repeated headers are returned.
+func TestHeaderValues(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Add("Set-Cookie", "a=1")
+		w.Header().Add("Set-Cookie", "b=2")
+		w.Header().Add("Link", `</?page=2>; rel="next"`)
+		w.Header().Add("Link", `</?page=3>; rel="last"`)
+	}))
+	defer ts.Close()
+
+	resp, err := GetURL(ts.URL, nil)
+	if err != nil {
+		t.Fatalf("TestHeaderValues was incorrect, got error: %v", err)
+	}
+	if got := resp.HeaderValues("set-cookie"); len(got) != 2 || got[0] != "a=1" || got[1] != "b=2" {
+		t.Errorf("TestHeaderValues was incorrect, got: %v, want: [a=1 b=2].", got)
+	}
+	if got := resp.Link("last"); got != ts.URL+"/?page=3" {
+		t.Errorf("TestHeaderValues was incorrect, got last link: %s, want: %s.", got, ts.URL+"/?page=3")
+	}
+
+	// Keys not in canonical form, set bypassing http.Header.
+	resp.Headers["x-vendor-id"] = []string{"2"}
+	resp.Headers.Add("X-Vendor-Id", "1")
+	if got := resp.HeaderValues("X-Vendor-ID"); len(got) != 2 || got[0] != "1" || got[1] != "2" {
+		t.Errorf("TestHeaderValues was incorrect, got: %v, want: [1 2].", got)
+	}
+}
+
+// TestWithBearerToken should test that the token is sent unless another Authorization is.
+func TestWithBearerToken(t *testing.T) {
+	c := New(WithBearerToken("s3cr3t"))
+
+	if got := c.mergeHeaders(nil, nil).Get("Authorization"); got != "Bearer s3cr3t" {
+		t.Errorf("TestWithBearerToken was incorrect, got: %q, want: %q.", got, "Bearer s3cr3t")
+	}
+	if got := c.mergeHeaders(map[string]string{"Authorization": "Basic Zm9v"}, nil).Get("Authorization"); got != "Basic Zm9v" {
+		t.Errorf("TestWithBearerToken was incorrect, got: %q, want: %q.", got, "Basic Zm9v")
+	}
+	if got := c.mergeHeaders(map[string]string{"Authorization": ""}, nil); got.Get("Authorization") != "" {
+		t.Errorf("TestWithBearerToken was incorrect, got: %q, want: no Authorization.", got.Get("Authorization"))
+	}
+}
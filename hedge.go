@@ -0,0 +1,73 @@
+package httpclient
+
+import (
+	"context"
+	"time"
+)
+
+// WithHedging sends a second identical GET request if the first one hasn't
+// answered after delay, using whichever answers first successfully and
+// canceling the other, to cut the tail latency of flaky hosts. If the first
+// one fails before delay, the second one is sent right away only if the
+// failure can be retried, e.g. not for a 404.
+// Only GET requests are hedged, and not the ones streaming their body, e.g.
+// by GetToWriter or DownloadFile.
+func WithHedging(delay time.Duration) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.hedgeDelay = delay
+	}
+}
+
+// hedgedResult is the outcome of one of the hedged requests.
+type hedgedResult struct {
+	resp HTTPResponse
+	err  error
+}
+
+// requestHedged performs the GET request of URL hedged as by WithHedging.
+func (c *Client) requestHedged(ctx context.Context, URL string, headers map[string]string, delay time.Duration, opts []RequestOption) (HTTPResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	// Canceling the request still in flight.
+	defer cancel()
+
+	single := func(cfg *requestConfig) {
+		cfg.hedgeDelay = 0
+		cfg.inner = true
+	}
+	opts = append(append([]RequestOption(nil), opts...), single)
+
+	results := make(chan hedgedResult, 2)
+	send := func() {
+		resp, err := c.RequestContext(ctx, URL, "GET", headers, nil, opts...)
+		results <- hedgedResult{resp, err}
+	}
+
+	go send()
+	pending := 1
+	hedge := c.clock.After(delay)
+
+	var last hedgedResult
+	for pending > 0 {
+		select {
+		case <-hedge:
+			hedge = nil
+			pending++
+			go send()
+		case last = <-results:
+			pending--
+			if last.err == nil {
+				return last.resp, nil
+			}
+			// The first one failed before the delay: send the hedged one right
+			// away, unless the failure is final, e.g. a 404. Otherwise wait
+			// for the hedged one already sent.
+			if hedge != nil && ctx.Err() == nil && mirrorRetryable(last.resp.Status.Code) {
+				hedge = nil
+				pending++
+				go send()
+			}
+		}
+	}
+
+	return last.resp, last.err
+}
@@ -0,0 +1,86 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWithHedging should test that a slow request is hedged by a second one.
+func TestWithHedging(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&hits, 1) == 1 {
+			select {
+			case <-time.After(5 * time.Second):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		fmt.Fprint(w, "fast")
+	}))
+	defer ts.Close()
+
+	// The request canceled isn't reported, the hedged one succeeding.
+	var reports int32
+	c := New(WithFailureReporter(func(context.Context, FailureReport) {
+		atomic.AddInt32(&reports, 1)
+	}))
+
+	start := time.Now()
+	resp, err := c.GetURL(ts.URL, nil, WithHedging(20*time.Millisecond))
+	if err != nil || string(resp.Body) != "fast" {
+		t.Errorf("TestWithHedging was incorrect, got: %q (%v), want: \"fast\".", resp.Body, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second || atomic.LoadInt32(&hits) != 2 {
+		t.Errorf("TestWithHedging was incorrect, got: %v and %d requests, want: the hedged request answering.", elapsed, hits)
+	}
+	c.Shutdown(context.Background())
+	if n := atomic.LoadInt32(&reports); n != 0 {
+		t.Errorf("TestWithHedging was incorrect, got: %d reports, want: 0.", n)
+	}
+}
+
+// TestWithHedgingStreamed should test that the streamed bodies aren't hedged.
+func TestWithHedgingStreamed(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		fmt.Fprint(w, "PARTIAL-")
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		fmt.Fprint(w, "body")
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	if _, err := GetToWriter(ts.URL, nil, &buf, WithHedging(10*time.Millisecond)); err != nil || buf.String() != "PARTIAL-body" {
+		t.Errorf("TestWithHedgingStreamed was incorrect, got: %q (%v), want: \"PARTIAL-body\".", buf.String(), err)
+	}
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Errorf("TestWithHedgingStreamed was incorrect, got: %d requests, want: 1.", n)
+	}
+}
+
+// TestWithHedgingFinalFailure should test that a request failed with a final status isn't hedged.
+func TestWithHedgingFinalFailure(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	if _, err := GetURL(ts.URL, nil, WithHedging(time.Second)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TestWithHedgingFinalFailure was incorrect, got error: %v, want: %v.", err, ErrNotFound)
+	}
+	if n := atomic.LoadInt32(&hits); n != 1 {
+		t.Errorf("TestWithHedgingFinalFailure was incorrect, got: %d requests, want: 1.", n)
+	}
+}
@@ -1,9 +1,15 @@
 package httpclient
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"io/ioutil"
 	"math"
 	"net/http"
+	"net/url"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -15,113 +21,604 @@ type HTTPResponse struct {
 	Body    []byte
 	Status  ResponseStatus
 	Headers http.Header
+	// Method and URL are those of the last request, the URL after the redirects.
+	Method string
+	URL    string
+	// Attempts is the number of requests sent and Duration the time taken by all of them.
+	Attempts int
+	Duration time.Duration
+	// FromCache reports whether the response was served by a caching transport,
+	// as told by the X-From-Cache header.
+	FromCache bool
+	Timings   Timings
+	// TraceID is the W3C trace id of the request, see WithTraceContext.
+	TraceID string
+	// ETag, LastModified, Expires and CacheControl are parsed from the headers
+	// of the same name, zero values if missing or invalid.
+	ETag         string
+	LastModified time.Time
+	Expires      time.Time
+	CacheControl CacheControl
+	// Deprecation is the deprecation announced by the Deprecation, Sunset and
+	// Warning headers, if any.
+	Deprecation Deprecation
 }
 
 // GetURL retrieves data, status and response headers from an URL.
 // It uses some technique to slow down the requests if it get a 429 (Too Many Requests) response.
-func GetURL(URL string, headers map[string]string) (HTTPResponse, error) {
-	return Request(URL, "GET", headers, nil)
+func GetURL(URL string, headers map[string]string, opts ...RequestOption) (HTTPResponse, error) {
+	return defaultClient.GetURL(URL, headers, opts...)
 }
 
 // PostURL retrieves data, status and response headers from an URL.
 // It uses some technique to slow down the requests if it get a 429 (Too Many Requests) response.
-func PostURL(URL string, headers map[string]string, body io.Reader) (HTTPResponse, error) {
-	return Request(URL, "POST", headers, body)
+func PostURL(URL string, headers map[string]string, body io.Reader, opts ...RequestOption) (HTTPResponse, error) {
+	return defaultClient.PostURL(URL, headers, body, opts...)
 }
 
 // Request retrieves data, status and response headers from an URL.
 // It uses some technique to slow down the requests if it get a 429 (Too Many Requests) response.
-func Request(URL string, verb string, headers map[string]string, body io.Reader) (HTTPResponse, error) {
+func Request(URL string, verb string, headers map[string]string, body io.Reader, opts ...RequestOption) (HTTPResponse, error) {
+	return defaultClient.Request(URL, verb, headers, body, opts...)
+}
+
+// Request retrieves data, status and response headers from an URL.
+// It uses some technique to slow down the requests if it get a 429 (Too Many Requests) response.
+func (c *Client) Request(URL string, verb string, headers map[string]string, body io.Reader, opts ...RequestOption) (HTTPResponse, error) {
+	return c.RequestContext(context.Background(), URL, verb, headers, body, opts...)
+}
+
+// RequestContext is like Request, but the request is canceled when ctx is
+// done, the waits before the retries included, returning ctx.Err().
+func (c *Client) RequestContext(ctx context.Context, URL string, verb string, headers map[string]string, body io.Reader, opts ...RequestOption) (HTTPResponse, error) {
+	ctx, end, err := c.begin(ctx)
+	if err != nil {
+		return HTTPResponse{
+			Body:    nil,
+			Status:  ResponseStatus{Text: err.Error(), Code: -1},
+			Headers: nil,
+		}, err
+	}
+	defer end()
+
+	cfg := newRequestConfig(opts)
+	if cfg.stallTimeout == 0 && c.timeouts != nil {
+		cfg.stallTimeout = c.timeouts.IdleRead
+	}
+	if cfg.priority != PriorityNormal {
+		ctx = context.WithValue(ctx, priorityKey{}, cfg.priority)
+	}
+	if len(cfg.mirrors) > 0 {
+		return c.requestOuter(ctx, cfg, verb, URL, func() (HTTPResponse, error) {
+			return c.requestMirrors(ctx, append([]string{URL}, cfg.mirrors...), verb, headers, body, opts)
+		})
+	}
+	// The streamed bodies can't be written by two requests at once.
+	if cfg.hedgeDelay > 0 && verb == "GET" && cfg.sink == nil && cfg.onChunk == nil {
+		return c.requestOuter(ctx, cfg, verb, URL, func() (HTTPResponse, error) {
+			return c.requestHedged(ctx, URL, headers, cfg.hedgeDelay, opts)
+		})
+	}
+
+	var last HTTPResponse
+	// lastErr is the error of the last attempt, returned if the retries are exhausted.
+	var lastErr error
+	start := c.clock.Now()
+	tries := 0
+	// retries counts the attempts retried after a failure, see WithMaxRetries.
+	retries := 0
+	headAsGet := false
+	// headRange is set when the GET replacing a HEAD asks the first byte only.
+	headRange := false
+	// authenticated is set once a challenge is answered, see WithAuthenticator.
+	authenticated := false
+	var timings Timings
+	var attempts []Attempt
+	// tr is the trace context of the request, see WithTraceContext.
+	var tr trace
+	emit := func(e Event) {
+		e.TraceID = tr.id
+		c.emit(e)
+	}
+	// done adds to the response the metadata of the attempts.
+	done := func(resp HTTPResponse, err error) (HTTPResponse, error) {
+		if headAsGet {
+			resp.Body = nil
+		}
+		resp.Attempts = tries
+		resp.Timings = timings
+		resp.TraceID = tr.id
+		resp.Duration = c.clock.Now().Sub(start)
+		if c.onDeprecation != nil && resp.Deprecation.Announced() {
+			c.onDeprecation(resp)
+		}
+		if !cfg.inner {
+			c.requestDone(ctx, verb, URL, resp, err, attempts)
+		}
+		return resp, err
+	}
+	// Apply the override of the host, see WithHostOverride.
+	maxRetries := c.maxRetries
+	var politenessDelay time.Duration
+	if c.politeness != nil {
+		politenessDelay = c.politeness.delay
+	}
+	if o, ok := c.overrideFor(URL); ok {
+		if cfg.timeout == 0 {
+			cfg.timeout = o.Timeout
+		}
+		if o.MaxRetries != nil {
+			maxRetries = *o.MaxRetries
+		}
+		if o.Delay > 0 {
+			politenessDelay = o.Delay
+		}
+		headers = o.overrideHeaders(headers)
+	}
+
+	httpClient := c.httpClient
+	if cfg.timeout > 0 {
+		// The timeout is applied to each attempt through its context.
+		withoutTimeout := *c.httpClient
+		withoutTimeout.Timeout = 0
+		httpClient = &withoutTimeout
+	}
+
 	expBackoffAttempts := 0
-	const timeout = 60 * time.Second
 	const maxBackOffAttempts = 8 // 2 minutes.
-	var err error
 
-	client := http.Client{
-		// Request Timeout.
-		Timeout: timeout,
+	normalized, err := c.normalizeURL(URL)
+	if err == nil {
+		URL = normalized
+	}
+
+	header := c.mergeHeaders(headers, cfg.header)
+	if c.traceContext {
+		tr = requestTrace(ctx, header)
+	}
+
+	if !cfg.inner {
+		emit(Event{Type: EventRequestStart, Method: verb, URL: URL})
+	}
+	// The failures before the first attempt are reported as well, see done.
+	if err != nil {
+		return done(HTTPResponse{
+			Body:    nil,
+			Status:  ResponseStatus{Text: err.Error(), Code: -1},
+			Headers: nil,
+		}, err)
+	}
+
+	// Read the body once, to send it again on each attempt.
+	var payload []byte
+	if body != nil && cfg.onChunk == nil {
+		if payload, err = ioutil.ReadAll(body); err != nil {
+			return done(HTTPResponse{
+				Body:    nil,
+				Status:  ResponseStatus{Text: err.Error(), Code: -1},
+				Headers: nil,
+			}, err)
+		}
+	}
+
+	if u, _ := url.Parse(URL); c.robots != nil && !c.customScheme(u) && !c.notFoundCached(verb, u, header) {
+		if err := c.checkRobots(ctx, u); err != nil {
+			return done(HTTPResponse{
+				Body:    nil,
+				Status:  ResponseStatus{Text: err.Error(), Code: -1},
+				Headers: nil,
+			}, err)
+		}
 	}
 
-	for expBackoffAttempts < maxBackOffAttempts {
+	// The body and the context of the current attempt, released before the
+	// next attempt or on return.
+	var attemptBody io.ReadCloser
+	cancelAttempt := context.CancelFunc(func() {})
+	closeAttempt := func() {
+		if attemptBody != nil {
+			drainAndClose(attemptBody)
+			attemptBody = nil
+		}
+		cancelAttempt()
+	}
+	defer closeAttempt()
 
-		req, err := http.NewRequest(verb, URL, body)
+	// WithMaxRetries replaces the bound of the backoff.
+	for maxRetries >= 0 || expBackoffAttempts < maxBackOffAttempts {
+		attempt := Attempt{Time: c.clock.Now()}
+		tries++
+
+		var reqBody io.Reader
+		switch {
+		case cfg.onChunk != nil:
+			reqBody = &chunkReader{r: body, onChunk: cfg.onChunk}
+		case body != nil:
+			reqBody = bytes.NewReader(payload)
+		}
+
+		attemptCtx := ctx
+		if cfg.timeout > 0 || cfg.stallTimeout > 0 {
+			var cancel context.CancelFunc
+			if cfg.timeout > 0 {
+				attemptCtx, cancel = context.WithTimeout(ctx, cfg.timeout)
+			} else {
+				attemptCtx, cancel = context.WithCancel(ctx)
+			}
+			cancelAttempt = cancel
+		}
+
+		req, err := http.NewRequestWithContext(attemptCtx, verb, URL, reqBody)
 		if err != nil {
-			return HTTPResponse{
+			return done(HTTPResponse{
 				Body:    nil,
-				Status:  ResponseStatus{Text: err.Error() + URL, Code: -1},
+				Status:  ResponseStatus{Text: err.Error(), Code: -1},
 				Headers: nil,
-			}, err
+			}, err)
 		}
 
 		// Set headers.
-		for k, v := range headers {
-			req.Header.Add(k, v)
+		req.Header = header.Clone()
+		if tr.id != "" {
+			tr.setHeader(req.Header)
+		}
+		req.Close = c.disableKeepAlives
+		if cfg.host != "" {
+			req.Host = cfg.host
+		}
+		if body != nil && cfg.onChunk != nil {
+			c.setExpectContinue(req, -1)
+			req.ContentLength = -1
+		} else if body != nil {
+			sentPayload, err := c.transformRequest(req, payload)
+			if err != nil {
+				return done(HTTPResponse{
+					Body:    nil,
+					Status:  ResponseStatus{Text: err.Error(), Code: -1},
+					Headers: nil,
+				}, err)
+			}
+			c.setExpectContinue(req, int64(len(sentPayload)))
+			if len(cfg.trailers) > 0 {
+				cfg.setTrailers(req, sentPayload)
+			}
+		}
+
+		dump := c.dumpWriter(cfg)
+		if dump != nil {
+			dumpRequest(dump, req)
+		}
+		if req.Body != nil {
+			req.Body = c.throttle(attemptCtx, cfg, req.Body, nil)
+			if cfg.progress != nil {
+				req.Body = &progressBody{ReadCloser: req.Body, total: req.ContentLength, fn: cfg.progress}
+			}
+		}
+
+		if politenessDelay > 0 && !c.notFoundCached(verb, req.URL, req.Header) {
+			if err := c.waitPoliteness(attemptCtx, req.URL.Host, politenessDelay); err != nil {
+				return done(HTTPResponse{
+					Body:    nil,
+					Status:  ResponseStatus{Text: err.Error(), Code: -1},
+					Headers: nil,
+				}, err)
+			}
+		}
+		if err := c.waitResumed(attemptCtx); err != nil {
+			return done(HTTPResponse{
+				Body:    nil,
+				Status:  ResponseStatus{Text: err.Error(), Code: -1},
+				Headers: nil,
+			}, err)
+		}
+
+		release, wait, err := c.acquireSlot(attemptCtx, cfg.priority)
+		timings.QueueWait += wait
+		if err != nil {
+			return done(HTTPResponse{
+				Body:    nil,
+				Status:  ResponseStatus{Text: err.Error(), Code: -1},
+				Headers: nil,
+			}, err)
 		}
 
 		// Perform the request.
-		resp, err := client.Do(req)
+		sent := c.clock.Now()
+		resp, err := httpClient.Do(req)
 		if err != nil {
-			return HTTPResponse{
+			emit(Event{Type: EventAttempt, Method: verb, URL: URL, Attempt: tries, Error: err.Error()})
+			if ctx.Err() == nil {
+				c.recordOutcome(req.URL.Host, true)
+			}
+			release()
+			return done(HTTPResponse{
 				Body:    nil,
-				Status:  ResponseStatus{Text: err.Error() + URL, Code: -1},
+				Status:  ResponseStatus{Text: err.Error(), Code: -1},
 				Headers: nil,
-			}, err
+			}, err)
+		}
+		emit(Event{Type: EventAttempt, Method: verb, URL: URL, Attempt: tries, Status: resp.StatusCode})
+		c.recordLatency(req.URL.Host, c.clock.Now().Sub(sent))
+		c.recordOutcome(req.URL.Host, resp.StatusCode >= 500)
+		resp.Body = &releaseOnClose{ReadCloser: checkLength(resp, req.Method), release: release}
+		var stall *stallBody
+		if cfg.stallTimeout > 0 {
+			stall = newStallBody(resp.Body, cfg.stallTimeout, cancelAttempt)
+			resp.Body = stall
 		}
+		attemptBody = resp.Body
+		c.recordRateLimit(req.URL.Host, resp)
 
-		if resp != nil && resp.Body != nil {
-			defer resp.Body.Close()
+		if dump != nil {
+			dumpResponse(dump, resp)
+		}
+		resp.Body = c.throttle(attemptCtx, cfg, resp.Body, stall)
+		if cfg.progress != nil {
+			resp.Body = &progressBody{ReadCloser: resp.Body, total: resp.ContentLength, fn: cfg.progress}
+		}
+
+		if err := c.transform(resp); err != nil {
+			return done(newHTTPResponse(resp, nil), err)
+		}
+		if cfg.maxBodySize > 0 {
+			resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: cfg.maxBodySize}
+		}
+
+		// Retry as GET the HEAD requests rejected by the server.
+		if cfg.headFallback && verb == "HEAD" && (resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotImplemented) {
+			log.Debugf("Status: %s - Resource: %s, retrying as GET", resp.Status, URL)
+			closeAttempt()
+			verb, headAsGet = "GET", true
+			if header.Get("Range") == "" {
+				header = header.Clone()
+				header.Set("Range", "bytes=0-0")
+				headRange = true
+			}
+			continue
+		}
+		if headRange {
+			asHeadResponse(resp)
 		}
 
 		// Check if the request results in http OK.
 		if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
-			return statusOK(resp)
+			if err := cfg.checkAccept(resp); err != nil {
+				return done(newHTTPResponse(resp, nil), err)
+			}
+			if cfg.sniffer != nil {
+				head, ok, err := cfg.sniffer.sniff(resp)
+				if err != nil {
+					return done(newHTTPResponse(resp, nil), err)
+				}
+				if !ok {
+					// Close without draining, the rest of the body isn't wanted.
+					attemptBody.Close()
+					attemptBody = nil
+					return done(newHTTPResponse(resp, head), ErrSniffRejected)
+				}
+			}
+			if cfg.sink != nil {
+				return done(streamResponse(resp, cfg))
+			}
+			r, err := statusOK(resp)
+			if err == nil && cfg.soft404 != nil && cfg.soft404(r) {
+				log.Debugf("Status: %s - Resource: %s, detected as a soft 404", resp.Status, URL)
+				return done(r, c.responseError(r, ErrSoft404, attempts))
+			}
+			if err == nil || !cfg.retryBody(ctx, verb, err) || retries == maxRetries {
+				return done(cfg.complete(r, err))
+			}
+
+			// Retry the failed reads of the body after the backoff.
+			log.Warnf("Reading the body failed, retrying: %v - Resource: %s", err, URL)
+			closeAttempt()
+			waitStart := c.clock.Now()
+			if err := c.backoffSleep(ctx, time.Duration(expBackoffCalc(expBackoffAttempts)*float64(time.Second))); err != nil {
+				return done(r, err)
+			}
+			attempt.Status = resp.StatusCode
+			attempt.Err = err
+			attempt.Wait = c.clock.Now().Sub(waitStart)
+			attempts = append(attempts, attempt)
+			emit(Event{Type: EventRetry, Method: verb, URL: URL, Attempt: tries, Status: attempt.Status, Wait: attempt.Wait, Error: err.Error()})
+			last, lastErr = r, err
+			expBackoffAttempts++
+			retries++
+			continue
+		}
+
+		// Return the statuses handled by the caller.
+		if cfg.returnStatus != nil && cfg.returnStatus(resp.StatusCode) && !cfg.errorOnNon2xx {
+			return done(cfg.complete(statusOK(resp)))
+		}
+
+		// Answer the authentication challenge, once.
+		if resp.StatusCode == http.StatusUnauthorized && !authenticated && cfg.onChunk == nil {
+			if ch, a := c.authenticator(resp); a != nil {
+				log.Debugf("Status: %s - Resource: %s, answering the %s challenge", resp.Status, URL, ch.Scheme)
+				authenticated = true
+				r := readResponse(resp)
+				closeAttempt()
+				authorization, err := a.Authenticate(ctx, ch, verb, URL)
+				if err != nil {
+					return done(r, fmt.Errorf("authenticating with %s: %w", ch.Scheme, err))
+				}
+				header = header.Clone()
+				header.Set("Authorization", authorization)
+				attempt.Status = resp.StatusCode
+				attempts = append(attempts, attempt)
+				emit(Event{Type: EventRetry, Method: verb, URL: URL, Attempt: tries, Status: attempt.Status})
+				continue
+			}
+		}
+
+		// Let the handler registered for the status decide.
+		retryStatus := false
+		var retryErr error
+		if h := c.statusHandlers[resp.StatusCode]; h != nil {
+			r := readResponse(resp)
+			action, err := h(&r)
+			switch action {
+			case StatusStop:
+				log.Debugf("Status: %s - Resource: %s, stopped by the status handler", resp.Status, URL)
+				if err == nil && cfg.errorOnNon2xx {
+					err = ErrInvalidStatus
+				}
+				if err != nil {
+					return done(r, c.responseError(r, err, attempts))
+				}
+				return done(cfg.complete(r, nil))
+			case StatusRetry:
+				retryStatus, retryErr = true, err
+				if retryErr == nil {
+					retryErr = ErrRateLimited
+				}
+			}
+			// Give the body back to the built-in handling.
+			resp.Body = ioutil.NopCloser(bytes.NewReader(r.Body))
+		}
+
+		// Retry the object stores asking to slow down as a 429, e.g. with
+		// the presigned URLs of S3.
+		if !retryStatus && isSlowDown(resp) {
+			log.Debugf("Status: %s - Resource: %s, asked to slow down", resp.Status, URL)
+			retryStatus, retryErr = true, ErrRateLimited
 		}
 
 		// Check if the request results in http notFound.
-		if resp.StatusCode == http.StatusNotFound {
+		if !retryStatus && resp.StatusCode == http.StatusNotFound {
 			log.Debugf("Status: %s - Resource: %s", resp.Status, URL)
-			return statusNotFound(resp)
+			return done(c.statusNotFound(resp))
 		}
 
-		// Check if the request results in http RateLimit error.
-		if resp.StatusCode == http.StatusTooManyRequests {
+		if !retryStatus && resp.StatusCode == http.StatusPreconditionFailed {
 			log.Debugf("Status: %s - Resource: %s", resp.Status, URL)
-			expBackoffAttempts, err = statusTooManyRequests(resp, expBackoffAttempts)
-			if err != nil {
-				return HTTPResponse{
-					Body:    nil,
-					Status:  ResponseStatus{Text: err.Error() + URL, Code: -1},
-					Headers: nil,
-				}, err
-			}
+			return done(c.statusError(resp, ErrPreconditionFailed))
+		}
 
+		// Statuses other than RateLimit and Forbidden are not retried.
+		if !retryStatus && resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusForbidden {
+			log.Debugf("Status: %s - Resource: %s", resp.Status, URL)
+			return done(c.statusError(resp, ErrInvalidStatus))
 		}
-		// Check if the request result in http Forbidden status.
-		if resp.StatusCode == http.StatusForbidden {
+
+		// A streamed body can't be sent again, and the mirrors are tried
+		// before waiting. Neither are the exhausted retries waited for.
+		if cfg.onChunk != nil || cfg.failFast || retries == maxRetries {
 			log.Debugf("Status: %s - Resource: %s", resp.Status, URL)
-			expBackoffAttempts, err = statusForbidden(resp, expBackoffAttempts)
-			if err != nil {
-				return HTTPResponse{
-					Body:    nil,
-					Status:  ResponseStatus{Text: err.Error() + URL, Code: -1},
-					Headers: nil,
-				}, err
+			if retryStatus {
+				return done(c.statusError(resp, retryErr))
+			}
+			if resp.StatusCode == http.StatusForbidden {
+				return done(c.statusError(resp, ErrForbidden))
 			}
+			return done(c.statusError(resp, ErrRateLimited))
+		}
+
+		// Keep the response, returned if the retries are exhausted.
+		last, lastErr = readResponse(resp), ErrRateLimited
+		if retryStatus {
+			lastErr = retryErr
+		}
+		// Release the connection before waiting for the next attempt.
+		closeAttempt()
+
+		waitStart := c.clock.Now()
+		switch wait := c.quotaWait(resp); {
+		// Wait for the reset of a quota exhausted.
+		case wait > 0:
+			log.Infof("Quota exhausted, waiting %v - Resource: %s", wait, URL)
+			err = c.backoffSleep(ctx, wait)
+		// Check if the request results in http RateLimit error, or in a status
+		// retried by its handler.
+		case resp.StatusCode == http.StatusTooManyRequests || retryStatus:
+			log.Debugf("Status: %s - Resource: %s", resp.Status, URL)
+			expBackoffAttempts, err = c.statusTooManyRequests(ctx, resp, expBackoffAttempts)
+		// Check if the request result in a GitHub secondary rate limit or else
+		// in http Forbidden status.
+		case isSecondaryRateLimit(last):
+			log.Debugf("Status: %s - Resource: %s", resp.Status, URL)
+			expBackoffAttempts, err = c.statusSecondaryRateLimit(ctx, req.URL, resp, expBackoffAttempts)
+		case resp.StatusCode == http.StatusForbidden:
+			log.Debugf("Status: %s - Resource: %s", resp.Status, URL)
+			expBackoffAttempts, err = c.statusForbidden(ctx, resp, expBackoffAttempts)
+		}
+
+		attempt.Status = resp.StatusCode
+		attempt.Err = err
+		attempt.Wait = c.clock.Now().Sub(waitStart)
+		attempts = append(attempts, attempt)
+		if err != nil && err == ctx.Err() {
+			// The wait was interrupted.
+			return done(last, err)
+		}
+		if err != nil {
+			return done(last, c.responseError(last, err, attempts))
 		}
+		emit(Event{Type: EventRetry, Method: verb, URL: URL, Attempt: tries, Status: attempt.Status, Wait: attempt.Wait})
 
 		expBackoffAttempts += 1
+		retries++
 	}
 
-	// Generic invalid status code.
-	return HTTPResponse{
-		Body:    nil,
-		Status:  ResponseStatus{Text: "Invalid Status Code: " + URL, Code: -1},
-		Headers: nil,
-	}, err
+	// Retries exhausted, return the last response.
+	return done(last, c.responseError(last, lastErr, attempts))
+}
+
+// requestOuter performs with request the inner requests of a mirrored or
+// hedged one, emitting its events and reporting its failure once, with the
+// final result. The requests already inner are left to the outer one.
+func (c *Client) requestOuter(ctx context.Context, cfg *requestConfig, verb, URL string, request func() (HTTPResponse, error)) (HTTPResponse, error) {
+	if cfg.inner {
+		return request()
+	}
+
+	start := c.clock.Now()
+	c.emit(Event{Type: EventRequestStart, Method: verb, URL: URL})
+	resp, err := request()
+	resp.Duration = c.clock.Now().Sub(start)
+
+	var attempts []Attempt
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		attempts = httpErr.Attempts
+	}
+	c.requestDone(ctx, verb, URL, resp, err, attempts)
+
+	return resp, err
+}
+
+// requestDone emits the EventRequestDone of a request and reports its
+// failure, see WithFailureReporter.
+func (c *Client) requestDone(ctx context.Context, verb, URL string, resp HTTPResponse, err error, attempts []Attempt) {
+	c.emit(Event{
+		Type:      EventRequestDone,
+		Method:    verb,
+		URL:       URL,
+		Attempt:   resp.Attempts,
+		Status:    statusCode(resp),
+		Duration:  resp.Duration,
+		Error:     errorString(err),
+		FromCache: resp.FromCache,
+		TraceID:   resp.TraceID,
+	})
+	if err != nil && c.failureReporter != nil {
+		c.failureReporter(ctx, FailureReport{
+			Method:   verb,
+			URL:      URL,
+			Attempts: resp.Attempts,
+			Retried:  attempts,
+			Duration: resp.Duration,
+			Response: resp,
+			Err:      err,
+			TraceID:  resp.TraceID,
+		})
+	}
 }
 
 // HeaderLink parse the Github Header Link to "next"/"last"/"first"/"prev" link of repositories.
 // Example: HeaderLink(link,"next") or HeaderLink(link, "prev") or HeaderLink(link,"last").
+// The link is returned as sent, possibly relative: HTTPResponse.Link resolves it.
 func HeaderLink(linkHeader, command string) string {
 	parsedLinks := linkheader.Parse(linkHeader)
 
@@ -138,3 +635,12 @@ func HeaderLink(linkHeader, command string) string {
 func expBackoffCalc(attempts int) float64 {
 	return (math.Pow(2, float64(attempts)) - 1) / 2
 }
+
+// cappedBackoff returns the exponential backoff after attempts, at most max.
+func cappedBackoff(attempts int, max time.Duration) time.Duration {
+	if seconds := expBackoffCalc(attempts); seconds < max.Seconds() {
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	return max
+}
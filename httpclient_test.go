@@ -1,6 +1,8 @@
 package httpclient
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -160,3 +162,136 @@ func TestPostUrlWithHeadersResponse(t *testing.T) {
 		t.Errorf("TestCallWithDelay was incorrect, got: %s, want: %s.", resp.Headers.Get("X-PowOfTwo"), "4")
 	}
 }
+
+// TestGetUrlInvalidStatus should test that an unhandled status is returned with its data.
+func TestGetUrlInvalidStatus(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-Request-Id", "42")
+		w.WriteHeader(http.StatusBadGateway)
+		fmt.Fprint(w, "upstream down")
+	}))
+	defer ts.Close()
+
+	resp, err := GetURL(ts.URL, nil)
+	if err == nil || resp.Status.Code != http.StatusBadGateway || string(resp.Body) != "upstream down" || resp.Headers.Get("X-Request-Id") != "42" {
+		t.Errorf("TestGetUrlInvalidStatus was incorrect, got: %d %s (%v)", resp.Status.Code, resp.Body, err)
+	}
+}
+
+// TestGetUrlRetriesExhausted should test that the last response is returned when the retries are exhausted.
+func TestGetUrlRetriesExhausted(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, "slow down")
+	}))
+	defer ts.Close()
+
+	resp, err := GetURL(ts.URL, nil)
+	if err == nil || resp.Status.Code != http.StatusTooManyRequests || string(resp.Body) != "slow down" {
+		t.Errorf("TestGetUrlRetriesExhausted was incorrect, got: %d %s (%v)", resp.Status.Code, resp.Body, err)
+	}
+}
+
+// TestGetUrlMetadata should test the metadata of the response.
+func TestGetUrlMetadata(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusMovedPermanently)
+			return
+		}
+		if hits++; hits == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-From-Cache", "1")
+	}))
+	defer ts.Close()
+
+	resp, err := New(WithClock(newTestClock())).GetURL(ts.URL+"/old", nil)
+	if err != nil {
+		t.Fatalf("TestGetUrlMetadata was incorrect, got error: %v", err)
+	}
+	if resp.Method != "GET" || resp.URL != ts.URL+"/new" || resp.Attempts != 2 || resp.Duration != 3*time.Second || !resp.FromCache {
+		t.Errorf("TestGetUrlMetadata was incorrect, got: %s %s %d %v %t, want: GET %s 2 3s true.",
+			resp.Method, resp.URL, resp.Attempts, resp.Duration, resp.FromCache, ts.URL+"/new")
+	}
+}
+
+// TestBackoffBounds should test that the waits are bounded by WithBackoffBounds.
+func TestBackoffBounds(t *testing.T) {
+	retryAfter := []string{"7200", "0"}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if len(retryAfter) > 0 {
+			w.Header().Set("Retry-After", retryAfter[0])
+			retryAfter = retryAfter[1:]
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	c := New(WithClock(clock), WithBackoffBounds(time.Second, time.Minute))
+	if _, err := c.GetURL(ts.URL, nil); err != nil {
+		t.Fatalf("TestBackoffBounds was incorrect, got error: %v", err)
+	}
+
+	sleeps := clock.Sleeps()
+	if len(sleeps) != 2 || sleeps[0] != time.Minute || sleeps[1] != time.Second {
+		t.Errorf("TestBackoffBounds was incorrect, got: %v, want: [1m0s 1s].", sleeps)
+	}
+}
+
+// TestWithMaxRetries should test that the retries stop at the limit.
+func TestWithMaxRetries(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	for _, n := range []int{0, 2} {
+		hits = 0
+		clock := newTestClock()
+		resp, err := New(WithClock(clock), WithMaxRetries(n)).GetURL(ts.URL, nil)
+		if err == nil || resp.Status.Code != http.StatusTooManyRequests || hits != n+1 || len(clock.Sleeps()) != n {
+			t.Errorf("TestWithMaxRetries was incorrect, got: %d hits, %d sleeps, %v, want: %d hits, %d sleeps, an error.", hits, len(clock.Sleeps()), err, n+1, n)
+		}
+	}
+}
+
+// TestRequestContextCanceledDuringBackoff should test that canceling ctx interrupts the wait before a retry.
+func TestRequestContextCanceledDuringBackoff(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := New().RequestContext(ctx, ts.URL, "GET", nil, nil)
+	if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 5*time.Second {
+		t.Errorf("TestRequestContextCanceledDuringBackoff was incorrect, got: %v after %v, want: %v.", err, time.Since(start), context.DeadlineExceeded)
+	}
+}
+
+// TestCappedBackoff should test that the backoff is bounded, however many the attempts.
+func TestCappedBackoff(t *testing.T) {
+	tests := map[int]time.Duration{
+		1:    500 * time.Millisecond,
+		3:    3500 * time.Millisecond,
+		20:   time.Minute,
+		2000: time.Minute,
+	}
+	for attempts, want := range tests {
+		if got := cappedBackoff(attempts, time.Minute); got != want {
+			t.Errorf("TestCappedBackoff was incorrect for %d attempts, got: %v, want: %v.", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package httpclienttest
+
+import (
+	"testing"
+	"time"
+
+	httpclient "github.com/italia/httpclient-lib-go"
+)
+
+// TestClockBackoff should test that the backoff waits on the fake clock instead of sleeping.
+func TestClockBackoff(t *testing.T) {
+	srv := NewServer(Status(429), Status(429), Status(429), OK("done"))
+	defer srv.Close()
+
+	clock := NewClock(time.Unix(0, 0))
+	start := time.Now()
+	resp, err := httpclient.New(httpclient.WithClock(clock)).GetURL(srv.URL, nil)
+	if err != nil || string(resp.Body) != "done" {
+		t.Errorf("TestClockBackoff was incorrect, got: %s (%v), want: %s.", resp.Body, err, "done")
+	}
+	if len(clock.Sleeps()) != 3 {
+		t.Errorf("TestClockBackoff was incorrect, got: %v sleeps, want: %d.", clock.Sleeps(), 3)
+	}
+	if time.Since(start) > time.Second {
+		t.Errorf("TestClockBackoff was incorrect, the client slept for real")
+	}
+}
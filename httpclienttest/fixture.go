@@ -0,0 +1,115 @@
+package httpclienttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strconv"
+)
+
+// FixtureExt is the extension of the fixture files loaded by LoadFixtures.
+const FixtureExt = ".fixture.json"
+
+// Fixture describes a canned response, e.g.
+//
+//	{
+//	  "method": "GET",
+//	  "url": "https://api.github.com/repos/italia/publiccode.yml",
+//	  "status": 200,
+//	  "headers": {"Content-Type": "application/json"},
+//	  "headerValues": {"Link": ["<https://api.github.com/repositories?page=2>; rel=\"next\""]},
+//	  "bodyFile": "repo.json"
+//	}
+//
+// HeaderValues are added to Headers, for the headers sent several times, like
+// Link or Set-Cookie. BodyFile is relative to the directory of the fixture.
+type Fixture struct {
+	Method       string              `json:"method"`
+	URL          string              `json:"url"`
+	Status       int                 `json:"status"`
+	Headers      map[string]string   `json:"headers"`
+	HeaderValues map[string][]string `json:"headerValues"`
+	BodyFile     string              `json:"bodyFile"`
+
+	body []byte
+}
+
+// Transport is an http.RoundTripper answering with fixtures, to plug into
+// a client with httpclient.WithTransport.
+type Transport struct {
+	fixtures []Fixture
+}
+
+// LoadFixtures loads the fixtures (files ending in FixtureExt) in dir
+// and returns a Transport serving them.
+func LoadFixtures(dir string) (*Transport, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+FixtureExt))
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Transport{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var f Fixture
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+		}
+		if f.Method == "" {
+			f.Method = http.MethodGet
+		}
+		if f.Status == 0 {
+			f.Status = http.StatusOK
+		}
+		if f.BodyFile != "" {
+			if f.body, err = ioutil.ReadFile(filepath.Join(dir, f.BodyFile)); err != nil {
+				return nil, fmt.Errorf("invalid fixture %s: %w", path, err)
+			}
+		}
+
+		t.fixtures = append(t.fixtures, f)
+	}
+
+	return t, nil
+}
+
+// RoundTrip implements http.RoundTripper, returning an error when no fixture
+// matches method and URL of req.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for _, f := range t.fixtures {
+		if f.Method != req.Method || f.URL != req.URL.String() {
+			continue
+		}
+
+		header := make(http.Header, len(f.Headers))
+		for k, v := range f.Headers {
+			header.Set(k, v)
+		}
+		for k, values := range f.HeaderValues {
+			for _, v := range values {
+				header.Add(k, v)
+			}
+		}
+
+		return &http.Response{
+			Status:        strconv.Itoa(f.Status) + " " + http.StatusText(f.Status),
+			StatusCode:    f.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(f.body)),
+			ContentLength: int64(len(f.body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("httpclienttest: no fixture for %s %s", req.Method, req.URL)
+}
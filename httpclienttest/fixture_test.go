@@ -0,0 +1,35 @@
+package httpclienttest
+
+import (
+	"testing"
+
+	httpclient "github.com/italia/httpclient-lib-go"
+)
+
+// TestLoadFixtures should test that the responses are served from the fixtures.
+func TestLoadFixtures(t *testing.T) {
+	transport, err := LoadFixtures("testdata/fixtures")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := httpclient.New(httpclient.WithTransport(transport))
+
+	resp, err := c.GetURL("https://api.github.com/repos/italia/publiccode.yml", nil)
+	r := "{\"full_name\": \"italia/publiccode.yml\"}\n"
+	if err != nil || string(resp.Body) != r || resp.Headers.Get("Content-Type") != "application/json" {
+		t.Errorf("TestLoadFixtures was incorrect, got: %s (%v), want: %s.", resp.Body, err, r)
+	}
+
+	if links := resp.HeaderValues("Link"); len(links) != 2 || resp.Link("last") != "https://api.github.com/repos/italia/publiccode.yml?page=5" {
+		t.Errorf("TestLoadFixtures was incorrect, got Link headers: %v, want: next and last.", links)
+	}
+
+	resp, _ = c.GetURL("https://raw.githubusercontent.com/italia/missing/master/publiccode.yml", nil)
+	if resp.Status.Code != 404 {
+		t.Errorf("TestLoadFixtures was incorrect, got: %d, want: %d.", resp.Status.Code, 404)
+	}
+
+	if _, err := c.GetURL("https://example.com/unknown", nil); err == nil {
+		t.Errorf("TestLoadFixtures was incorrect, got no error for a request without fixture")
+	}
+}
@@ -0,0 +1,164 @@
+// Package httpclienttest provides stub servers scripting sequences of responses,
+// to verify the retry and backoff behavior of code using httpclient.
+//
+//	srv := httpclienttest.NewServer(
+//		httpclienttest.TooManyRequests(0),
+//		httpclienttest.TooManyRequests(0),
+//		httpclienttest.OK("{}"),
+//	)
+//	defer srv.Close()
+package httpclienttest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Response is a scripted response.
+type Response struct {
+	Status  int
+	Headers map[string]string
+	// HeaderValues are added to Headers, for the headers sent several times.
+	HeaderValues http.Header
+	Body         string
+	// Delay is waited before writing the headers.
+	Delay time.Duration
+	// ChunkSize and ChunkDelay simulate a slow body, written ChunkSize bytes
+	// at a time waiting ChunkDelay between chunks.
+	ChunkSize  int
+	ChunkDelay time.Duration
+}
+
+// RecordedRequest is a request received by the Server.
+type RecordedRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// Server is an httptest.Server answering with the scripted responses in order.
+// Once the script is over, the last response is repeated.
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	responses []Response
+	requests  []RecordedRequest
+}
+
+// OK returns a 200 response with body.
+func OK(body string) Response {
+	return Response{Status: http.StatusOK, Body: body}
+}
+
+// TooManyRequests returns a 429 response with a Retry-After of retryAfter seconds.
+func TooManyRequests(retryAfter int) Response {
+	return Response{
+		Status:  http.StatusTooManyRequests,
+		Headers: map[string]string{"Retry-After": strconv.Itoa(retryAfter)},
+	}
+}
+
+// Status returns an empty response with status code.
+func Status(code int) Response {
+	return Response{Status: code}
+}
+
+// NewServer starts a Server answering with responses.
+func NewServer(responses ...Response) *Server {
+	s := &Server{responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+
+	return s
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// Hits returns the number of requests received so far.
+func (s *Server) Hits() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.requests)
+}
+
+// AssertHeader fails t if the n-th received request (starting from 0)
+// doesn't have the header name set to want.
+func (s *Server) AssertHeader(t testing.TB, n int, name, want string) {
+	t.Helper()
+
+	requests := s.Requests()
+	if n >= len(requests) {
+		t.Errorf("request %d not received, got %d requests", n, len(requests))
+		return
+	}
+	if got := requests[n].Header.Get(name); got != want {
+		t.Errorf("request %d header %s was incorrect, got: %q, want: %q.", n, name, got, want)
+	}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, RecordedRequest{
+		Method: r.Method,
+		URL:    r.URL.String(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	resp := Response{Status: http.StatusInternalServerError, Body: "httpclienttest: no scripted response"}
+	if len(s.responses) > 0 {
+		i := len(s.requests) - 1
+		if i >= len(s.responses) {
+			i = len(s.responses) - 1
+		}
+		resp = s.responses[i]
+	}
+	s.mu.Unlock()
+
+	time.Sleep(resp.Delay)
+
+	for k, v := range resp.Headers {
+		w.Header().Set(k, v)
+	}
+	for k, values := range resp.HeaderValues {
+		for _, v := range values {
+			w.Header().Add(k, v)
+		}
+	}
+	if resp.Status == 0 {
+		resp.Status = http.StatusOK
+	}
+	w.WriteHeader(resp.Status)
+
+	if resp.ChunkSize <= 0 {
+		fmt.Fprint(w, resp.Body)
+		return
+	}
+	for data := resp.Body; len(data) > 0; {
+		n := resp.ChunkSize
+		if n > len(data) {
+			n = len(data)
+		}
+		fmt.Fprint(w, data[:n])
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		data = data[n:]
+		time.Sleep(resp.ChunkDelay)
+	}
+}
@@ -0,0 +1,39 @@
+package httpclienttest
+
+import (
+	"testing"
+	"time"
+
+	httpclient "github.com/italia/httpclient-lib-go"
+)
+
+// TestServerSequence should test that the scripted responses are served in order.
+func TestServerSequence(t *testing.T) {
+	srv := NewServer(TooManyRequests(0), TooManyRequests(0), OK("{\"data\": \"example data\"}"))
+	defer srv.Close()
+
+	resp, err := httpclient.GetURL(srv.URL, map[string]string{"Accept": "application/json"})
+	r := "{\"data\": \"example data\"}"
+	if err != nil || string(resp.Body) != r {
+		t.Errorf("TestServerSequence was incorrect, got: %s (%v), want: %s.", resp.Body, err, r)
+	}
+	if srv.Hits() != 3 {
+		t.Errorf("TestServerSequence was incorrect, got: %d hits, want: %d.", srv.Hits(), 3)
+	}
+	srv.AssertHeader(t, 2, "Accept", "application/json")
+}
+
+// TestServerSlowBody should test that a chunked body is delivered whole.
+func TestServerSlowBody(t *testing.T) {
+	srv := NewServer(Response{Body: "slow body", ChunkSize: 2, ChunkDelay: 10 * time.Millisecond})
+	defer srv.Close()
+
+	start := time.Now()
+	resp, err := httpclient.GetURL(srv.URL, nil)
+	if err != nil || string(resp.Body) != "slow body" {
+		t.Errorf("TestServerSlowBody was incorrect, got: %s (%v), want: %s.", resp.Body, err, "slow body")
+	}
+	if time.Since(start) < 40*time.Millisecond {
+		t.Errorf("TestServerSlowBody was incorrect, the body was not slow")
+	}
+}
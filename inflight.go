@@ -0,0 +1,98 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"sync"
+)
+
+// inflightTransport is an http.RoundTripper that caps the number of
+// outstanding requests per host.
+type inflightTransport struct {
+	next http.RoundTripper
+	max  int
+
+	mu    sync.Mutex
+	hosts map[string]*hostSlots
+}
+
+// hostSlots is the semaphore of a single host. refs counts the requests
+// holding or waiting for a slot, so that idle hosts can be forgotten.
+type hostSlots struct {
+	sem  *prioritySemaphore
+	refs int
+}
+
+func newInflightTransport(next http.RoundTripper, max int) *inflightTransport {
+	return &inflightTransport{
+		next:  next,
+		max:   max,
+		hosts: make(map[string]*hostSlots),
+	}
+}
+
+// RoundTrip waits for a free slot of the request host, as by its priority,
+// and holds it until the response body is closed.
+func (t *inflightTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	slots := t.ref(host)
+
+	if err := slots.sem.acquire(req.Context(), requestPriority(req.Context())); err != nil {
+		t.unref(host)
+		return nil, err
+	}
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() {
+			slots.sem.release()
+			t.unref(host)
+		})
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+func (t *inflightTransport) ref(host string) *hostSlots {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	slots, ok := t.hosts[host]
+	if !ok {
+		slots = &hostSlots{sem: newPrioritySemaphore(t.max)}
+		t.hosts[host] = slots
+	}
+	slots.refs++
+
+	return slots
+}
+
+func (t *inflightTransport) unref(host string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	slots := t.hosts[host]
+	slots.refs--
+	if slots.refs == 0 {
+		delete(t.hosts, host)
+	}
+}
+
+// releaseOnClose calls release when the body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,47 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMaxInflightPerHost should test that no more than n requests are outstanding to the same host.
+func TestMaxInflightPerHost(t *testing.T) {
+	var current, peak int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := atomic.AddInt32(&current, 1)
+		defer atomic.AddInt32(&current, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, "{\"data\": \"example data\"}")
+	}))
+	defer ts.Close()
+
+	c := New(WithMaxInflightPerHost(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.GetURL(ts.URL, nil); err != nil {
+				t.Errorf("TestMaxInflightPerHost was incorrect, got error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Errorf("TestMaxInflightPerHost was incorrect, got: %d in flight, want: at most %d.", peak, 2)
+	}
+}
@@ -0,0 +1,110 @@
+package httpclient
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/tomnomnom/linkheader"
+)
+
+// Link is a link of a Link header, with its relation type Rel and its
+// other parameters.
+type Link = linkheader.Link
+
+// ParseLinkHeader parses a Link header like
+// `<https://api.github.com/repositories?page=2>; rel="next"`.
+func ParseLinkHeader(linkHeader string) []Link {
+	return linkheader.Parse(linkHeader)
+}
+
+// BuildLinkHeader returns the value of a Link header for links, the inverse
+// of ParseLinkHeader. The parameters are sorted so the value is stable.
+func BuildLinkHeader(links []Link) string {
+	parts := make([]string, 0, len(links))
+	for _, link := range links {
+		keys := make([]string, 0, len(link.Params))
+		for k := range link.Params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		var b strings.Builder
+		b.WriteString("<" + link.URL + ">")
+		if link.Rel != "" {
+			b.WriteString(`; rel="` + quoteLinkParam(link.Rel) + `"`)
+		}
+		for _, k := range keys {
+			b.WriteString("; " + k + `="` + quoteLinkParam(link.Params[k]) + `"`)
+		}
+		parts = append(parts, b.String())
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// AddLink returns links with a link to URL of relation rel appended.
+func AddLink(links []Link, rel, URL string) []Link {
+	return append(links, Link{URL: URL, Rel: rel})
+}
+
+// SetLink returns links with the links of relation rel replaced by a single
+// link to URL, added if there was none, e.g. to override the "next" page.
+func SetLink(links []Link, rel, URL string) []Link {
+	result := make([]Link, 0, len(links)+1)
+	replaced := false
+	for _, link := range links {
+		if link.Rel != rel {
+			result = append(result, link)
+			continue
+		}
+		if !replaced {
+			link.URL = URL
+			result = append(result, link)
+			replaced = true
+		}
+	}
+	if !replaced {
+		result = append(result, Link{URL: URL, Rel: rel})
+	}
+
+	return result
+}
+
+// quoteLinkParam escapes the quotes and the backslashes of a parameter value.
+func quoteLinkParam(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value)
+}
+
+// ResolveLinks returns links with their URLs resolved against base, the URL
+// of the request the links were received for (RFC 8288, section 3.2).
+// The URLs that fail to parse are left as they are.
+func ResolveLinks(links []Link, base string) []Link {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return links
+	}
+
+	resolved := make([]Link, len(links))
+	for i, link := range links {
+		if ref, err := url.Parse(link.URL); err == nil {
+			link.URL = baseURL.ResolveReference(ref).String()
+		}
+		resolved[i] = link
+	}
+
+	return resolved
+}
+
+// Link returns the URL of the link of relation rel of the Link headers of r,
+// resolved against the URL of r, empty if missing.
+func (r HTTPResponse) Link(rel string) string {
+	links := ResolveLinks(linkheader.ParseMultiple(r.Headers.Values("Link")), r.URL)
+	for _, link := range links {
+		if link.Rel == rel {
+			return link.URL
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,71 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestBuildLinkHeader should test that the built header parses back to the links.
+func TestBuildLinkHeader(t *testing.T) {
+	links := []Link{
+		{URL: "https://example.com/items?page=2", Rel: "next", Params: map[string]string{"title": "page 2", "type": "application/json"}},
+		{URL: "https://example.com/items?page=9", Rel: "last"},
+	}
+
+	got := BuildLinkHeader(links)
+	want := `<https://example.com/items?page=2>; rel="next"; title="page 2"; type="application/json", <https://example.com/items?page=9>; rel="last"`
+	if got != want {
+		t.Errorf("TestBuildLinkHeader was incorrect, got: %s, want: %s.", got, want)
+	}
+
+	if next := HeaderLink(got, "next"); next != links[0].URL {
+		t.Errorf("TestBuildLinkHeader was incorrect, got next: %s, want: %s.", next, links[0].URL)
+	}
+	if parsed := ParseLinkHeader(got); len(parsed) != 2 || parsed[0].Param("title") != "page 2" {
+		t.Errorf("TestBuildLinkHeader was incorrect, got parsed: %+v.", parsed)
+	}
+}
+
+// TestSetLink should test that SetLink overrides the links of a rel, or adds one.
+func TestSetLink(t *testing.T) {
+	links := AddLink(nil, "next", "/a?page=2")
+	links = AddLink(links, "next", "/b?page=2")
+	links = AddLink(links, "last", "/a?page=5")
+
+	links = SetLink(links, "next", "/c?page=2")
+	links = SetLink(links, "prev", "/a?page=1")
+
+	got := BuildLinkHeader(links)
+	want := `</c?page=2>; rel="next", </a?page=5>; rel="last", </a?page=1>; rel="prev"`
+	if got != want {
+		t.Errorf("TestSetLink was incorrect, got: %s, want: %s.", got, want)
+	}
+}
+
+// TestResponseLink should test that the relative links are resolved against the request URL.
+func TestResponseLink(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", `<?page=2>; rel="next", </items?page=9>; rel="last"`)
+		w.Header().Add("Link", `<https://example.com/items?page=1>; rel="first"`)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	resp, err := GetURL(ts.URL+"/v1/items?page=1", nil)
+	if err != nil {
+		t.Fatalf("TestResponseLink was incorrect, got error: %v", err)
+	}
+
+	tests := map[string]string{
+		"next":  ts.URL + "/v1/items?page=2",
+		"last":  ts.URL + "/items?page=9",
+		"first": "https://example.com/items?page=1",
+		"prev":  "",
+	}
+	for rel, want := range tests {
+		if got := resp.Link(rel); got != want {
+			t.Errorf("TestResponseLink was incorrect for %s, got: %s, want: %s.", rel, got, want)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+// Package logadapter adapts the loggers of log/slog and go.uber.org/zap to
+// the httpclient.Logger interface, so the events of the requests go to the
+// logging stack already in use:
+//
+//	client := httpclient.New(httpclient.WithLogger(logadapter.Slog(slog.Default())))
+//
+// Failed requests are logged at the warning level, retries at the info level
+// and the other events at the debug level. The package is a module of its
+// own, so that the users of httpclient alone don't depend on zap.
+package logadapter
+
+import httpclient "github.com/italia/httpclient-lib-go"
+
+// level is the level of the events.
+type level int
+
+const (
+	levelDebug level = iota
+	levelInfo
+	levelWarn
+)
+
+// eventLevel returns the level e is logged at.
+func eventLevel(e httpclient.Event) level {
+	switch {
+	case e.Type == httpclient.EventRequestDone && e.Error != "":
+		return levelWarn
+	case e.Type == httpclient.EventRetry:
+		return levelInfo
+	default:
+		return levelDebug
+	}
+}
@@ -0,0 +1,10 @@
+module github.com/italia/httpclient-lib-go/logadapter
+
+go 1.14
+
+require (
+	github.com/italia/httpclient-lib-go v0.0.0-00010101000000-000000000000
+	go.uber.org/zap v1.21.0
+)
+
+replace github.com/italia/httpclient-lib-go => ../
@@ -0,0 +1,55 @@
+//go:build go1.21
+// +build go1.21
+
+package logadapter
+
+import (
+	"context"
+	"log/slog"
+
+	httpclient "github.com/italia/httpclient-lib-go"
+)
+
+// slogLogger is an httpclient.Logger logging to a *slog.Logger.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// Slog returns an httpclient.Logger logging the events to logger, with the
+// event type as message and the other fields of the event as attributes.
+func Slog(logger *slog.Logger) httpclient.Logger {
+	return slogLogger{logger: logger}
+}
+
+func (l slogLogger) LogEvent(e httpclient.Event) {
+	lvl := slog.LevelDebug
+	switch eventLevel(e) {
+	case levelInfo:
+		lvl = slog.LevelInfo
+	case levelWarn:
+		lvl = slog.LevelWarn
+	}
+
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, lvl) {
+		return
+	}
+
+	attrs := []slog.Attr{slog.String("method", e.Method), slog.String("url", e.URL)}
+	if e.Attempt > 0 {
+		attrs = append(attrs, slog.Int("attempt", e.Attempt))
+	}
+	if e.Status > 0 {
+		attrs = append(attrs, slog.Int("status", e.Status))
+	}
+	if e.Wait > 0 {
+		attrs = append(attrs, slog.Duration("wait", e.Wait))
+	}
+	if e.Duration > 0 {
+		attrs = append(attrs, slog.Duration("duration", e.Duration))
+	}
+	if e.Error != "" {
+		attrs = append(attrs, slog.String("error", e.Error))
+	}
+	l.logger.LogAttrs(ctx, lvl, e.Type, attrs...)
+}
@@ -0,0 +1,32 @@
+//go:build go1.21
+// +build go1.21
+
+package logadapter
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	httpclient "github.com/italia/httpclient-lib-go"
+)
+
+// TestSlog should test that the events are logged with their attributes at their level.
+func TestSlog(t *testing.T) {
+	var buf bytes.Buffer
+	l := Slog(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo})))
+
+	l.LogEvent(httpclient.Event{Type: httpclient.EventRequestStart, Method: "GET", URL: "https://example.org"})
+	l.LogEvent(httpclient.Event{Type: httpclient.EventRequestDone, Method: "GET", URL: "https://example.org", Attempt: 1, Status: 404, Error: "not found"})
+
+	got := buf.String()
+	if strings.Contains(got, httpclient.EventRequestStart) {
+		t.Errorf("TestSlog was incorrect, got: %q, want: the start at the debug level.", got)
+	}
+	for _, want := range []string{"level=WARN", "msg=request_done", "url=https://example.org", "status=404", `error="not found"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("TestSlog was incorrect, got: %q, want: %s.", got, want)
+		}
+	}
+}
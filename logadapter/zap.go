@@ -0,0 +1,52 @@
+package logadapter
+
+import (
+	httpclient "github.com/italia/httpclient-lib-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// zapLogger is an httpclient.Logger logging to a *zap.Logger.
+type zapLogger struct {
+	logger *zap.Logger
+}
+
+// Zap returns an httpclient.Logger logging the events to logger, with the
+// event type as message and the other fields of the event as zap fields.
+func Zap(logger *zap.Logger) httpclient.Logger {
+	return zapLogger{logger: logger}
+}
+
+func (l zapLogger) LogEvent(e httpclient.Event) {
+	lvl := zapcore.DebugLevel
+	switch eventLevel(e) {
+	case levelInfo:
+		lvl = zapcore.InfoLevel
+	case levelWarn:
+		lvl = zapcore.WarnLevel
+	}
+
+	ce := l.logger.Check(lvl, e.Type)
+	if ce == nil {
+		return
+	}
+
+	fields := []zap.Field{zap.String("method", e.Method), zap.String("url", e.URL)}
+	if e.Attempt > 0 {
+		fields = append(fields, zap.Int("attempt", e.Attempt))
+	}
+	if e.Status > 0 {
+		fields = append(fields, zap.Int("status", e.Status))
+	}
+	if e.Wait > 0 {
+		fields = append(fields, zap.Duration("wait", e.Wait))
+	}
+	if e.Duration > 0 {
+		fields = append(fields, zap.Duration("duration", e.Duration))
+	}
+	if e.Error != "" {
+		fields = append(fields, zap.String("error", e.Error))
+	}
+	ce.Time = e.Time
+	ce.Write(fields...)
+}
@@ -0,0 +1,32 @@
+package logadapter
+
+import (
+	"testing"
+	"time"
+
+	httpclient "github.com/italia/httpclient-lib-go"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// TestZap should test that the events are logged with their fields at their level.
+func TestZap(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	l := Zap(zap.New(core))
+
+	l.LogEvent(httpclient.Event{Type: httpclient.EventAttempt, Method: "GET", URL: "https://example.org", Attempt: 1, Status: 429})
+	l.LogEvent(httpclient.Event{Type: httpclient.EventRetry, Method: "GET", URL: "https://example.org", Attempt: 1, Status: 429, Wait: time.Second})
+	l.LogEvent(httpclient.Event{Type: httpclient.EventRequestDone, Method: "GET", URL: "https://example.org", Attempt: 2, Error: "rate limited"})
+
+	entries := logs.All()
+	if len(entries) != 2 {
+		t.Fatalf("TestZap was incorrect, got: %d entries, want: 2, the attempt being at the debug level.", len(entries))
+	}
+	if entries[0].Message != httpclient.EventRetry || entries[0].Level != zapcore.InfoLevel || entries[0].ContextMap()["wait"] != time.Second {
+		t.Errorf("TestZap was incorrect, got: %+v, want: the retry at the info level.", entries[0])
+	}
+	if entries[1].Level != zapcore.WarnLevel || entries[1].ContextMap()["error"] != "rate limited" {
+		t.Errorf("TestZap was incorrect, got: %+v, want: the failure at the warning level.", entries[1])
+	}
+}
@@ -0,0 +1,63 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultLongPollTimeout is the default timeout of each long polling request.
+const defaultLongPollTimeout = 5 * time.Minute
+
+// maxLongPollBackoff bounds the wait after the failures of LongPoll.
+const maxLongPollBackoff = 5 * time.Minute
+
+// LongPoll repeatedly GETs URL, calling onResponse with each response, until
+// ctx is done or onResponse returns an error, which is returned.
+// Each request waits up to 5 minutes, or the time set by WithTimeout: a request
+// timing out is expected and is issued again right away, while the backoff
+// is applied only on failures.
+func LongPoll(ctx context.Context, URL string, headers map[string]string, onResponse func(HTTPResponse) error, opts ...RequestOption) error {
+	return defaultClient.LongPoll(ctx, URL, headers, onResponse, opts...)
+}
+
+// LongPoll repeatedly GETs URL, calling onResponse with each response, until
+// ctx is done or onResponse returns an error, which is returned.
+// Each request waits up to 5 minutes, or the time set by WithTimeout: a request
+// timing out is expected and is issued again right away, while the backoff
+// is applied only on failures.
+func (c *Client) LongPoll(ctx context.Context, URL string, headers map[string]string, onResponse func(HTTPResponse) error, opts ...RequestOption) error {
+	opts = append([]RequestOption{WithTimeout(defaultLongPollTimeout)}, opts...)
+	failures := 0
+
+	for {
+		resp, err := c.RequestContext(ctx, URL, "GET", headers, nil, opts...)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if err == nil {
+			failures = 0
+			if err := onResponse(resp); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			log.Debugf("Long polling timed out, polling again - Resource: %s", URL)
+			continue
+		}
+
+		failures++
+		sleep := cappedBackoff(failures, maxLongPollBackoff)
+		log.Infof("Long polling failed (%v), sleep %v", err, sleep)
+		if err := c.sleepContext(ctx, sleep); err != nil {
+			return err
+		}
+	}
+}
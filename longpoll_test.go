@@ -0,0 +1,45 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestLongPoll should test that timeouts are polled again right away and failures backed off.
+func TestLongPoll(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		switch atomic.AddInt32(&hits, 1) {
+		case 1:
+			time.Sleep(200 * time.Millisecond)
+		case 2:
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			fmt.Fprint(w, "event")
+		}
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	c := New(WithClock(clock))
+	errStop := errors.New("stop")
+
+	var events []string
+	err := c.LongPoll(context.Background(), ts.URL, nil, func(resp HTTPResponse) error {
+		events = append(events, string(resp.Body))
+		return errStop
+	}, WithTimeout(50*time.Millisecond))
+
+	if err != errStop || len(events) != 1 || events[0] != "event" {
+		t.Errorf("TestLongPoll was incorrect, got: %v (%v), want: %v.", events, err, []string{"event"})
+	}
+	if hits != 3 || len(clock.Sleeps()) != 1 {
+		t.Errorf("TestLongPoll was incorrect, got: %d hits and sleeps %v, want: %d hits and one sleep.", hits, clock.Sleeps(), 3)
+	}
+}
@@ -0,0 +1,67 @@
+package httpclient
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// The metrics recorded by the Client, with their labels.
+const (
+	// MetricRequests counts the requests returned, labeled by host, method and
+	// status. A request to mirrors or hedged counts once, with its final status.
+	MetricRequests = "httpclient_requests_total"
+	// MetricRetries counts the attempts retried, labeled by host, method and status.
+	MetricRetries = "httpclient_retries_total"
+	// MetricRequestDuration observes the seconds taken by the requests, retries,
+	// mirrors and hedged requests included, labeled by host, method and status.
+	MetricRequestDuration = "httpclient_request_duration_seconds"
+	// MetricRateLimitRemaining is the last rate limit remaining of each host, labeled by host.
+	MetricRateLimitRemaining = "httpclient_ratelimit_remaining"
+)
+
+// MetricsRecorder receives the metrics of a Client, see WithMetrics. The names
+// of the metrics are the Metric constants, each with the same label names on
+// every call. It must be safe for concurrent use.
+type MetricsRecorder interface {
+	IncCounter(name string, labels map[string]string)
+	ObserveHistogram(name string, value float64, labels map[string]string)
+	SetGauge(name string, value float64, labels map[string]string)
+}
+
+// WithMetrics records the metrics of the requests to m, e.g. to Prometheus
+// with the prommetrics package.
+func WithMetrics(m MetricsRecorder) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// recordMetrics records the metrics of the event e.
+func (c *Client) recordMetrics(e Event) {
+	if c.metrics == nil {
+		return
+	}
+
+	labels := map[string]string{
+		"host":   eventHost(e.URL),
+		"method": e.Method,
+		"status": strconv.Itoa(e.Status),
+	}
+	switch e.Type {
+	case EventRetry:
+		c.metrics.IncCounter(MetricRetries, labels)
+	case EventRequestDone:
+		c.metrics.IncCounter(MetricRequests, labels)
+		c.metrics.ObserveHistogram(MetricRequestDuration, e.Duration.Seconds(), labels)
+	}
+}
+
+// eventHost returns the host of URL, empty if invalid.
+func eventHost(URL string) string {
+	u, err := url.Parse(URL)
+	if err != nil {
+		return ""
+	}
+
+	return u.Host
+}
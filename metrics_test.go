@@ -0,0 +1,89 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// testMetrics is a MetricsRecorder keeping the recorded values.
+type testMetrics struct {
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+func (m *testMetrics) IncCounter(name string, labels map[string]string) {
+	m.record(name, labels, 1, true)
+}
+
+func (m *testMetrics) ObserveHistogram(name string, value float64, labels map[string]string) {
+	m.record(name, labels, value, true)
+}
+
+func (m *testMetrics) SetGauge(name string, value float64, labels map[string]string) {
+	m.record(name, labels, value, false)
+}
+
+func (m *testMetrics) record(name string, labels map[string]string, value float64, add bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := name + "{" + labels["method"] + " " + labels["status"] + "}"
+	if add {
+		value += m.values[key]
+	}
+	m.values[key] = value
+}
+
+// TestWithMetrics should test that requests, retries and durations are recorded.
+func TestWithMetrics(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if hits++; hits == 1 {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-RateLimit-Remaining", "7")
+	}))
+	defer ts.Close()
+
+	m := &testMetrics{values: make(map[string]float64)}
+	c := New(WithClock(newTestClock()), WithMetrics(m))
+	if _, err := c.GetURL(ts.URL, nil); err != nil {
+		t.Fatalf("TestWithMetrics was incorrect, got error: %v", err)
+	}
+
+	want := map[string]float64{
+		MetricRetries + "{GET 429}":         1,
+		MetricRequests + "{GET 200}":        1,
+		MetricRequestDuration + "{GET 200}": 3,
+		MetricRateLimitRemaining + "{ }":    7,
+	}
+	for key, v := range want {
+		if m.values[key] != v {
+			t.Errorf("TestWithMetrics was incorrect, got: %v for %s, want: %v.", m.values[key], key, v)
+		}
+	}
+}
+
+// TestWithMetricsMirrors should test that a request to mirrors is recorded once.
+func TestWithMetricsMirrors(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer mirror.Close()
+
+	m := &testMetrics{values: make(map[string]float64)}
+	c := New(WithClock(newTestClock()), WithMetrics(m))
+	if _, err := c.GetURL(primary.URL, nil, WithMirrors(mirror.URL)); err != nil {
+		t.Fatalf("TestWithMetricsMirrors was incorrect, got error: %v", err)
+	}
+
+	if m.values[MetricRequests+"{GET 200}"] != 1 || m.values[MetricRequests+"{GET 503}"] != 0 {
+		t.Errorf("TestWithMetricsMirrors was incorrect, got: %v, want: 1 request with status 200.", m.values)
+	}
+}
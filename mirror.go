@@ -0,0 +1,70 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// WithMirrors sets alternative URLs of the resource, e.g. of an artifact
+// published on several hosts. When the URL fails with a retryable status
+// (429, 403 or 5xx) or can't be reached, the mirrors are tried in order
+// without waiting; only when they all fail the request is retried on the URL
+// with the usual backoff.
+func WithMirrors(URLs ...string) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.mirrors = URLs
+	}
+}
+
+// requestMirrors performs the request on each of URLs until one doesn't
+// fail with a retryable status, then retries the first one with the backoff.
+func (c *Client) requestMirrors(ctx context.Context, URLs []string, verb string, headers map[string]string, body io.Reader, opts []RequestOption) (HTTPResponse, error) {
+	// Read the body once, to send it to each mirror.
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = ioutil.ReadAll(body); err != nil {
+			return HTTPResponse{
+				Body:    nil,
+				Status:  ResponseStatus{Text: err.Error(), Code: -1},
+				Headers: nil,
+			}, err
+		}
+	}
+	bodyReader := func() io.Reader {
+		if body == nil {
+			return nil
+		}
+		return bytes.NewReader(payload)
+	}
+
+	single := func(cfg *requestConfig) {
+		cfg.mirrors = nil
+		cfg.inner = true
+	}
+	failFast := func(cfg *requestConfig) {
+		cfg.failFast = true
+	}
+	mirrorOpts := append(append([]RequestOption(nil), opts...), single, failFast)
+
+	for _, URL := range URLs {
+		resp, err := c.RequestContext(ctx, URL, verb, headers, bodyReader(), mirrorOpts...)
+		if err == nil || !mirrorRetryable(resp.Status.Code) {
+			return resp, err
+		}
+		log.Debugf("Mirror failed (%v), trying the next one - Resource: %s", err, URL)
+	}
+
+	return c.RequestContext(ctx, URLs[0], verb, headers, bodyReader(), append(append([]RequestOption(nil), opts...), single)...)
+}
+
+// mirrorRetryable reports whether a request failed with code should be tried
+// on the next mirror.
+func mirrorRetryable(code int) bool {
+	return code == -1 || code == http.StatusTooManyRequests || code == http.StatusForbidden || code >= 500
+}
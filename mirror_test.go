@@ -0,0 +1,34 @@
+package httpclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestWithMirrors should test that the mirrors are tried without waiting.
+func TestWithMirrors(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer primary.Close()
+	limited := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer limited.Close()
+	mirror := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "artifact")
+	}))
+	defer mirror.Close()
+
+	clock := newTestClock()
+	resp, err := New(WithClock(clock)).GetURL(primary.URL, nil, WithMirrors(limited.URL, mirror.URL))
+	if err != nil || string(resp.Body) != "artifact" {
+		t.Errorf("TestWithMirrors was incorrect, got: %q (%v), want: \"artifact\".", resp.Body, err)
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 0 {
+		t.Errorf("TestWithMirrors was incorrect, got sleeps: %v, want: none.", sleeps)
+	}
+}
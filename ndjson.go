@@ -0,0 +1,107 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// maxNDJSONRecord bounds the size of a record of GetNDJSON.
+const maxNDJSONRecord = 16 << 20
+
+// ErrRecordTooLarge is returned by GetNDJSON for a record larger than 16 MB.
+var ErrRecordTooLarge = errors.New("NDJSON record too large")
+
+// GetNDJSON GETs URL and streams its body of newline delimited JSON (NDJSON,
+// JSON Lines), calling fn with each record, without holding the body in
+// memory. The record is only valid until fn returns. Blank lines are skipped.
+// An error of fn stops the download and is returned, like the invalid records.
+func GetNDJSON(ctx context.Context, URL string, headers map[string]string, fn func(json.RawMessage) error, opts ...RequestOption) (HTTPResponse, error) {
+	return defaultClient.GetNDJSON(ctx, URL, headers, fn, opts...)
+}
+
+// GetNDJSON GETs URL and streams its body of newline delimited JSON (NDJSON,
+// JSON Lines), calling fn with each record, without holding the body in
+// memory. The record is only valid until fn returns. Blank lines are skipped.
+// An error of fn stops the download and is returned, like the invalid records.
+func (c *Client) GetNDJSON(ctx context.Context, URL string, headers map[string]string, fn func(json.RawMessage) error, opts ...RequestOption) (HTTPResponse, error) {
+	if _, ok := headers["Accept"]; !ok {
+		h := make(map[string]string, len(headers)+1)
+		for k, v := range headers {
+			h[k] = v
+		}
+		h["Accept"] = "application/x-ndjson, application/jsonl, application/json;q=0.5"
+		headers = h
+	}
+
+	w := &ndjsonWriter{fn: fn}
+	sink := func(cfg *requestConfig) {
+		cfg.sink = w
+	}
+	resp, err := c.RequestContext(ctx, URL, "GET", headers, nil, append(opts, sink)...)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, w.flush()
+}
+
+// ndjsonWriter splits the bytes written into lines, calling fn with each record.
+type ndjsonWriter struct {
+	fn   func(json.RawMessage) error
+	buf  []byte
+	line int
+}
+
+func (w *ndjsonWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			if len(w.buf)+len(p) > maxNDJSONRecord {
+				return 0, fmt.Errorf("%w: line %d", ErrRecordTooLarge, w.line+1)
+			}
+			w.buf = append(w.buf, p...)
+			break
+		}
+
+		record := p[:i]
+		if len(w.buf) > 0 {
+			w.buf = append(w.buf, record...)
+			record = w.buf
+		}
+		if err := w.record(record); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[:0]
+		p = p[i+1:]
+	}
+
+	return n, nil
+}
+
+// flush calls fn with the last record, not terminated by a newline.
+func (w *ndjsonWriter) flush() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	defer func() { w.buf = w.buf[:0] }()
+
+	return w.record(w.buf)
+}
+
+// record calls fn with the record of a line, skipping the blank ones.
+func (w *ndjsonWriter) record(line []byte) error {
+	w.line++
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil
+	}
+	if !json.Valid(line) {
+		return fmt.Errorf("invalid NDJSON record on line %d", w.line)
+	}
+
+	return w.fn(json.RawMessage(line))
+}
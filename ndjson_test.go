@@ -0,0 +1,70 @@
+package httpclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGetNDJSON should test that the records are streamed one by one.
+func TestGetNDJSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		switch r.URL.Path {
+		case "/invalid":
+			fmt.Fprint(w, "{\"id\":1}\n{\"id\":\n")
+		default:
+			// Split the records across the writes.
+			fmt.Fprint(w, "{\"id\":1}\r\n\n{\"id\"")
+			w.(http.Flusher).Flush()
+			fmt.Fprint(w, ":2}\n{\"id\":3}")
+		}
+	}))
+	defer ts.Close()
+
+	var ids []int
+	_, err := GetNDJSON(context.Background(), ts.URL, nil, func(record json.RawMessage) error {
+		var v struct{ ID int }
+		if err := json.Unmarshal(record, &v); err != nil {
+			return err
+		}
+		ids = append(ids, v.ID)
+		return nil
+	})
+	if err != nil || fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("TestGetNDJSON was incorrect, got: %v, %v, want: [1 2 3].", ids, err)
+	}
+
+	_, err = GetNDJSON(context.Background(), ts.URL+"/invalid", nil, func(json.RawMessage) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("TestGetNDJSON was incorrect, got: %v, want: an invalid record on line 2.", err)
+	}
+
+	errStop := errors.New("stop")
+	calls := 0
+	_, err = GetNDJSON(context.Background(), ts.URL, nil, func(json.RawMessage) error {
+		calls++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || calls != 1 {
+		t.Errorf("TestGetNDJSON was incorrect, got: %v after %d calls, want: %v after 1.", err, calls, errStop)
+	}
+}
+
+// TestNDJSONRecordTooLarge should test that the records are bounded.
+func TestNDJSONRecordTooLarge(t *testing.T) {
+	w := &ndjsonWriter{fn: func(json.RawMessage) error { return nil }}
+	chunk := make([]byte, 1<<20)
+	var err error
+	for i := 0; i <= maxNDJSONRecord>>20 && err == nil; i++ {
+		_, err = w.Write(chunk)
+	}
+	if !errors.Is(err, ErrRecordTooLarge) {
+		t.Errorf("TestNDJSONRecordTooLarge was incorrect, got: %v, want: %v.", err, ErrRecordTooLarge)
+	}
+}
@@ -0,0 +1,197 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// maxNegativeBody bounds the body of the responses kept by the negative cache.
+const maxNegativeBody = 64 << 10
+
+// negativeCacheSweepSize is the number of entries after which the expired ones are swept.
+const negativeCacheSweepSize = 1024
+
+// negativeCache keeps the 404 (Not Found) and 410 (Gone) responses, see
+// WithNegativeCache.
+type negativeCache struct {
+	ttl   time.Duration
+	clock Clock
+
+	mu sync.Mutex
+	// entries are the responses of each URL by the credentials of the
+	// requests, see credentialsKey.
+	entries map[string]map[string]negativeEntry
+}
+
+type negativeEntry struct {
+	status  int
+	text    string
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// WithNegativeCache keeps for ttl the 404 (Not Found) and 410 (Gone)
+// responses to the GET and HEAD requests, answering the requests to the same
+// URL from the cache without any request to the server, nor waiting for the
+// politeness and robots.txt delays, e.g. so that the passes of a crawl don't
+// check again the raw files known to be missing. The responses are kept by
+// the Authorization of the requests as well, as a resource missing for some
+// credentials may exist for others. The responses served from the cache have
+// FromCache set. See InvalidateNotFound.
+func WithNegativeCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.notFound = &negativeCache{ttl: ttl, entries: make(map[string]map[string]negativeEntry)}
+	}
+}
+
+// InvalidateNotFound removes URL from the negative cache, whatever the
+// credentials, e.g. once the file has been created, so that the next request
+// reaches the server.
+func (c *Client) InvalidateNotFound(URL string) {
+	if c.notFound == nil {
+		return
+	}
+	if normalized, err := c.normalizeURL(URL); err == nil {
+		URL = normalized
+	}
+
+	c.notFound.mu.Lock()
+	delete(c.notFound.entries, URL)
+	c.notFound.mu.Unlock()
+}
+
+// notFoundCached reports whether a request with method to u and header is
+// answered by the negative cache.
+func (c *Client) notFoundCached(method string, u *url.URL, header http.Header) bool {
+	if c.notFound == nil || (method != "GET" && method != "HEAD") {
+		return false
+	}
+	_, ok := c.notFound.get(u.String(), credentialsKey(header))
+
+	return ok
+}
+
+// credentialsKey returns the key of the credentials of header in the
+// negative cache, a hash of its Authorization, empty without one.
+func credentialsKey(header http.Header) string {
+	authorization := header.Get("Authorization")
+	if authorization == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(authorization))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns the entry of URL for the credentials key, if not expired.
+func (n *negativeCache) get(URL, key string) (negativeEntry, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	e, ok := n.entries[URL][key]
+	if !ok {
+		return negativeEntry{}, false
+	}
+	if !n.clock.Now().Before(e.expires) {
+		n.delete(URL, key)
+		return negativeEntry{}, false
+	}
+
+	return e, true
+}
+
+// delete removes the entry of URL for the credentials key. n.mu must be held.
+func (n *negativeCache) delete(URL, key string) {
+	delete(n.entries[URL], key)
+	if len(n.entries[URL]) == 0 {
+		delete(n.entries, URL)
+	}
+}
+
+// negativeCacheTransport answers from a negativeCache, filling it with the
+// responses of next.
+type negativeCacheTransport struct {
+	next  http.RoundTripper
+	cache *negativeCache
+}
+
+func (t *negativeCacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != "GET" && req.Method != "HEAD" {
+		return t.next.RoundTrip(req)
+	}
+
+	URL, key := req.URL.String(), credentialsKey(req.Header)
+	if e, ok := t.cache.get(URL, key); ok {
+		header := e.header.Clone()
+		header.Set(headerFromCache, "1")
+		body := e.body
+		if req.Method == "HEAD" {
+			body = nil
+		}
+		return &http.Response{
+			Status:        e.text,
+			StatusCode:    e.status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil || (resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusGone) {
+		return resp, err
+	}
+
+	// Keep the body, unless too large, and give it back to the response.
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxNegativeBody+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = &transformedBody{Reader: io.MultiReader(bytes.NewReader(body), resp.Body), Closer: resp.Body}
+	if len(body) > maxNegativeBody {
+		return resp, nil
+	}
+
+	header := resp.Header.Clone()
+	// The length is set again for the body served, empty for HEAD.
+	header.Del("Content-Length")
+	t.cache.put(URL, key, negativeEntry{status: resp.StatusCode, text: resp.Status, header: header, body: body})
+
+	return resp, nil
+}
+
+// put adds the entry of URL for the credentials key, expiring after the TTL.
+func (n *negativeCache) put(URL, key string, e negativeEntry) {
+	now := n.clock.Now()
+	e.expires = now.Add(n.ttl)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if len(n.entries) >= negativeCacheSweepSize {
+		for u, entries := range n.entries {
+			for k, old := range entries {
+				if !now.Before(old.expires) {
+					n.delete(u, k)
+				}
+			}
+		}
+	}
+	if n.entries[URL] == nil {
+		n.entries[URL] = make(map[string]negativeEntry)
+	}
+	n.entries[URL][key] = e
+}
@@ -0,0 +1,82 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestWithNegativeCache should test that the missing resources are served
+// from the cache until invalidated or expired.
+func TestWithNegativeCache(t *testing.T) {
+	hits := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		switch r.URL.Path {
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, "no such file")
+		case "/gone":
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	c := New(WithClock(clock), WithNegativeCache(time.Hour))
+
+	for i := 0; i < 2; i++ {
+		resp, err := c.GetURL(ts.URL+"/missing", nil)
+		if !errors.Is(err, ErrNotFound) || string(resp.Body) != "no such file" || resp.FromCache != (i == 1) {
+			t.Errorf("TestWithNegativeCache was incorrect, got: %q, %v, from cache %v, want: the 404 body and ErrNotFound.", resp.Body, err, resp.FromCache)
+		}
+	}
+	if exists, err := c.Exists(ts.URL+"/missing", nil); exists || err != nil {
+		t.Errorf("TestWithNegativeCache was incorrect, got: %v, %v, want: false.", exists, err)
+	}
+	c.GetURL(ts.URL+"/gone", nil)
+	c.GetURL(ts.URL+"/gone", nil)
+	c.GetURL(ts.URL+"/found", nil)
+	c.GetURL(ts.URL+"/found", nil)
+	if hits["/missing"] != 1 || hits["/gone"] != 1 || hits["/found"] != 2 {
+		t.Errorf("TestWithNegativeCache was incorrect, got hits: %v, want: 1 missing, 1 gone, 2 found.", hits)
+	}
+
+	c.InvalidateNotFound(ts.URL + "//missing")
+	c.GetURL(ts.URL+"/missing", nil)
+	<-clock.After(2 * time.Hour)
+	c.GetURL(ts.URL+"/missing", nil)
+	if hits["/missing"] != 3 {
+		t.Errorf("TestWithNegativeCache was incorrect, got: %d hits, want: 3 after the invalidation and the expiration.", hits["/missing"])
+	}
+}
+
+// TestWithNegativeCacheCredentials should test that the responses are cached by the
+// credentials of the requests.
+func TestWithNegativeCacheCredentials(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c := New(WithClock(newTestClock()), WithNegativeCache(time.Hour))
+	if _, err := c.GetURL(ts.URL+"/private", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TestWithNegativeCacheCredentials was incorrect, got: %v, want: ErrNotFound.", err)
+	}
+	for i := 0; i < 2; i++ {
+		resp, err := c.GetURL(ts.URL+"/private", map[string]string{"Authorization": "Bearer s3cr3t"})
+		if err != nil || resp.FromCache {
+			t.Errorf("TestWithNegativeCacheCredentials was incorrect, got: %v, from cache %v, want: the resource.", err, resp.FromCache)
+		}
+	}
+	if resp, err := c.GetURL(ts.URL+"/private", nil); !errors.Is(err, ErrNotFound) || !resp.FromCache || hits != 3 {
+		t.Errorf("TestWithNegativeCacheCredentials was incorrect, got: %v, from cache %v, %d hits, want: the 404 from the cache.", err, resp.FromCache, hits)
+	}
+}
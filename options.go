@@ -0,0 +1,97 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Capabilities are the capabilities of a resource advertised in the response
+// to an OPTIONS request, see Options.
+type Capabilities struct {
+	// Allow are the methods of the Allow header, uppercased.
+	Allow []string
+	// AcceptPatch are the media types of the Accept-Patch header (RFC 5789).
+	AcceptPatch []string
+	CORS        CORS
+	Response    HTTPResponse
+}
+
+// CORS are the Access-Control-* headers of a response, answering to the
+// preflights with the Origin and Access-Control-Request-* headers.
+type CORS struct {
+	AllowOrigin      string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
+
+// Allows reports whether method is among the allowed ones.
+func (c Capabilities) Allows(method string) bool {
+	for _, m := range c.Allow {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Options sends an OPTIONS request to URL and returns the capabilities of
+// the resource, e.g. to check which methods it supports before a write.
+func Options(URL string, headers map[string]string, opts ...RequestOption) (Capabilities, error) {
+	return defaultClient.Options(URL, headers, opts...)
+}
+
+// Options sends an OPTIONS request to URL and returns the capabilities of
+// the resource, e.g. to check which methods it supports before a write.
+func (c *Client) Options(URL string, headers map[string]string, opts ...RequestOption) (Capabilities, error) {
+	resp, err := c.Request(URL, "OPTIONS", headers, nil, opts...)
+	if err != nil {
+		return Capabilities{Response: resp}, err
+	}
+
+	return parseCapabilities(resp), nil
+}
+
+// parseCapabilities returns the Capabilities of the headers of resp.
+func parseCapabilities(resp HTTPResponse) Capabilities {
+	h := resp.Headers
+	c := Capabilities{
+		Allow:       headerList(h, "Allow"),
+		AcceptPatch: headerList(h, "Accept-Patch"),
+		CORS: CORS{
+			AllowOrigin:      h.Get("Access-Control-Allow-Origin"),
+			AllowMethods:     headerList(h, "Access-Control-Allow-Methods"),
+			AllowHeaders:     headerList(h, "Access-Control-Allow-Headers"),
+			ExposeHeaders:    headerList(h, "Access-Control-Expose-Headers"),
+			AllowCredentials: strings.EqualFold(h.Get("Access-Control-Allow-Credentials"), "true"),
+		},
+		Response: resp,
+	}
+	for i, m := range c.Allow {
+		c.Allow[i] = strings.ToUpper(m)
+	}
+	if seconds, err := strconv.Atoi(h.Get("Access-Control-Max-Age")); err == nil && seconds > 0 {
+		c.CORS.MaxAge = time.Duration(seconds) * time.Second
+	}
+
+	return c
+}
+
+// headerList returns the comma separated elements of the values of the header name.
+func headerList(h http.Header, name string) []string {
+	var list []string
+	for _, value := range h.Values(name) {
+		for _, element := range strings.Split(value, ",") {
+			if element = strings.TrimSpace(element); element != "" {
+				list = append(list, element)
+			}
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestOptions should test that the Allow list and the CORS headers are parsed.
+func TestOptions(t *testing.T) {
+	var method, origin string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, origin = r.Method, r.Header.Get("Origin")
+		w.Header().Add("Allow", "get, HEAD")
+		w.Header().Add("Allow", "PATCH,,OPTIONS")
+		w.Header().Set("Accept-Patch", "application/merge-patch+json")
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Allow-Methods", "GET, PATCH")
+		w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type")
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+		w.Header().Set("Access-Control-Max-Age", "600")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	c, err := Options(ts.URL, map[string]string{"Origin": "https://developers.italia.it"})
+	if err != nil {
+		t.Fatalf("TestOptions was incorrect, got error: %v", err)
+	}
+
+	if method != "OPTIONS" || len(c.Allow) != 4 || c.Allow[0] != "GET" || !c.Allows("patch") || c.Allows("DELETE") {
+		t.Errorf("TestOptions was incorrect, got: %s with Allow %v, want: OPTIONS with GET, HEAD, PATCH, OPTIONS.", method, c.Allow)
+	}
+	if len(c.AcceptPatch) != 1 || c.AcceptPatch[0] != "application/merge-patch+json" {
+		t.Errorf("TestOptions was incorrect, got Accept-Patch: %v.", c.AcceptPatch)
+	}
+	cors := c.CORS
+	if cors.AllowOrigin != "https://developers.italia.it" || len(cors.AllowMethods) != 2 || len(cors.AllowHeaders) != 2 || !cors.AllowCredentials || cors.MaxAge != 10*time.Minute {
+		t.Errorf("TestOptions was incorrect, got CORS: %+v.", cors)
+	}
+}
@@ -0,0 +1,112 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HostOverride are the settings of the requests to the hosts matching a
+// pattern, see WithHostOverride. The zero fields keep the settings of the
+// Client, and the ones of the request take precedence.
+type HostOverride struct {
+	// Token is sent as "Authorization: Bearer token", see WithBearerToken.
+	Token string
+	// Headers replace the default headers of the Client with the same name.
+	Headers map[string]string
+	// Delay spaces the successive requests to each host, as
+	// WithPolitenessDelay does, e.g. RateDelay(5000, time.Hour).
+	Delay time.Duration
+	// Timeout bounds each attempt, as WithTimeout does.
+	Timeout time.Duration
+	// MaxRetries bounds the retries, as WithMaxRetries does.
+	MaxRetries *int
+}
+
+// hostOverride is a HostOverride with its pattern.
+type hostOverride struct {
+	pattern string
+	HostOverride
+}
+
+// WithHostOverride applies o to the requests to the hosts matching pattern,
+// in place of the settings of the Client. A "*" label of pattern matches
+// one or more labels of the host, e.g. "*.github.com" matches
+// "api.github.com" but not "github.com", "*.comune.*.it" matches
+// "www.comune.milano.it" and "www.comune.dolo.ve.it". A request gets the
+// override of the first pattern matching its host, in the order added.
+func WithHostOverride(pattern string, o HostOverride) Option {
+	return func(c *Client) {
+		c.overrides = append(c.overrides, hostOverride{pattern: strings.ToLower(pattern), HostOverride: o})
+	}
+}
+
+// RateDelay returns the delay spacing n requests in each period per, e.g.
+// RateDelay(5000, time.Hour) for 5000 requests an hour.
+func RateDelay(n int, per time.Duration) time.Duration {
+	if n <= 0 {
+		return 0
+	}
+
+	return per / time.Duration(n)
+}
+
+// overrideFor returns the override of the host of URL, false if none matches.
+func (c *Client) overrideFor(URL string) (HostOverride, bool) {
+	if len(c.overrides) == 0 {
+		return HostOverride{}, false
+	}
+	u, err := url.Parse(URL)
+	if err != nil {
+		return HostOverride{}, false
+	}
+
+	host := strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	for _, o := range c.overrides {
+		if matchHost(strings.Split(o.pattern, "."), strings.Split(host, ".")) {
+			return o.HostOverride, true
+		}
+	}
+
+	return HostOverride{}, false
+}
+
+// overrideHeaders returns the headers of a request with the ones of o below
+// them, canonicalized so that the ones of the request take precedence.
+func (o HostOverride) overrideHeaders(headers map[string]string) map[string]string {
+	if o.Token == "" && len(o.Headers) == 0 {
+		return headers
+	}
+
+	merged := make(map[string]string, len(o.Headers)+len(headers)+1)
+	for k, v := range o.Headers {
+		merged[http.CanonicalHeaderKey(k)] = v
+	}
+	if o.Token != "" {
+		merged["Authorization"] = "Bearer " + o.Token
+	}
+	for k, v := range headers {
+		merged[http.CanonicalHeaderKey(k)] = v
+	}
+
+	return merged
+}
+
+// matchHost reports whether the labels of host match the ones of pattern,
+// a "*" matching one or more labels.
+func matchHost(pattern, host []string) bool {
+	if len(pattern) == 0 {
+		return len(host) == 0
+	}
+	if pattern[0] != "*" {
+		return len(host) > 0 && pattern[0] == host[0] && matchHost(pattern[1:], host[1:])
+	}
+	for i := 1; i <= len(host); i++ {
+		if matchHost(pattern[1:], host[i:]) {
+			return true
+		}
+	}
+
+	return false
+}
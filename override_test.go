@@ -0,0 +1,93 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestMatchHost should test the host patterns of WithHostOverride.
+func TestMatchHost(t *testing.T) {
+	tests := []struct {
+		pattern, host string
+		want          bool
+	}{
+		{"*.github.com", "api.github.com", true},
+		{"*.github.com", "uploads.api.github.com", true},
+		{"*.github.com", "github.com", false},
+		{"*.github.com", "github.com.evil.org", false},
+		{"*.comune.*.it", "www.comune.milano.it", true},
+		{"*.comune.*.it", "www.comune.dolo.ve.it", true},
+		{"*.comune.*.it", "www.regione.veneto.it", false},
+		{"example.org", "example.org", true},
+		{"example.org", "www.example.org", false},
+	}
+	for _, test := range tests {
+		if got := matchHost(strings.Split(test.pattern, "."), strings.Split(test.host, ".")); got != test.want {
+			t.Errorf("TestMatchHost was incorrect for %s and %s, got: %v, want: %v.", test.pattern, test.host, got, test.want)
+		}
+	}
+}
+
+// TestWithHostOverride should test that the override of the host of each request applies.
+func TestWithHostOverride(t *testing.T) {
+	hits := 0
+	var authorization string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		authorization = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer ts.Close()
+
+	noRetries := 0
+	clock := newTestClock()
+	c := New(WithClock(clock), WithMaxRetries(2), WithHostOverride("127.0.0.*", HostOverride{
+		Token:      "s3cr3t",
+		Delay:      RateDelay(1, 2*time.Second),
+		MaxRetries: &noRetries,
+	}), WithHostOverride("*", HostOverride{Token: "other"}))
+
+	for i := 0; i < 2; i++ {
+		c.GetURL(ts.URL, nil)
+	}
+	if hits != 2 || authorization != "Bearer s3cr3t" {
+		t.Errorf("TestWithHostOverride was incorrect, got: %d hits, %q, want: 2 hits, the token of the first pattern.", hits, authorization)
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 2 || sleeps[1] != 2*time.Second {
+		t.Errorf("TestWithHostOverride was incorrect, got sleeps: %v, want: [0s 2s].", sleeps)
+	}
+
+	c.GetURL(ts.URL, map[string]string{"authorization": "Basic Zm9v"})
+	if authorization != "Basic Zm9v" {
+		t.Errorf("TestWithHostOverride was incorrect, got: %q, want: the Authorization of the request.", authorization)
+	}
+
+	hits = 0
+	New(WithClock(newTestClock()), WithMaxRetries(2), WithHostOverride("*.example.org", HostOverride{MaxRetries: &noRetries})).GetURL(ts.URL, nil)
+	if hits != 3 {
+		t.Errorf("TestWithHostOverride was incorrect, got: %d hits, want: 3 without a matching override.", hits)
+	}
+}
+
+// TestParseRate should test the rates of the host overrides of a Config.
+func TestParseRate(t *testing.T) {
+	tests := map[string]time.Duration{
+		"5000/h": 720 * time.Millisecond,
+		"1/2s":   2 * time.Second,
+		"60/m":   time.Second,
+		"":       0,
+	}
+	for rate, want := range tests {
+		if got, err := parseRate(rate); err != nil || got != want {
+			t.Errorf("TestParseRate was incorrect for %q, got: %v, %v, want: %v.", rate, got, err, want)
+		}
+	}
+	for _, rate := range []string{"5000", "0/h", "1/day"} {
+		if _, err := parseRate(rate); err == nil {
+			t.Errorf("TestParseRate was incorrect for %q, got: no error, want: an error.", rate)
+		}
+	}
+}
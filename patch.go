@@ -0,0 +1,84 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+const (
+	// contentTypeJSONPatch is the media type of the JSON Patch documents (RFC 6902).
+	contentTypeJSONPatch = "application/json-patch+json"
+	// contentTypeMergePatch is the media type of the JSON Merge Patch documents (RFC 7396).
+	contentTypeMergePatch = "application/merge-patch+json"
+)
+
+// JSONPatch is a JSON Patch document (RFC 6902), a sequence of operations.
+type JSONPatch []PatchOperation
+
+// PatchOperation is an operation of a JSON Patch. Value is sent for the
+// "add", "replace" and "test" operations, null included.
+type PatchOperation struct {
+	Op    string
+	Path  string
+	From  string
+	Value interface{}
+}
+
+// MarshalJSON encodes the members of the operation o.Op has.
+func (o PatchOperation) MarshalJSON() ([]byte, error) {
+	op := struct {
+		Op    string           `json:"op"`
+		Path  string           `json:"path"`
+		From  string           `json:"from,omitempty"`
+		Value *json.RawMessage `json:"value,omitempty"`
+	}{Op: o.Op, Path: o.Path, From: o.From}
+
+	switch o.Op {
+	case "add", "replace", "test":
+		value, err := json.Marshal(o.Value)
+		if err != nil {
+			return nil, err
+		}
+		raw := json.RawMessage(value)
+		op.Value = &raw
+	}
+
+	return json.Marshal(op)
+}
+
+// PatchJSON sends a PATCH request to URL with patch: a JSONPatch is sent as
+// application/json-patch+json, any other value as a JSON Merge Patch
+// (application/merge-patch+json), e.g. a map or a struct with omitempty fields.
+// The merge patches, idempotent, are retried like PUT requests, while the
+// JSON Patches, which may not be (e.g. appending to an array), are retried
+// only when the server didn't process them, as for rate limits.
+func PatchJSON(URL string, headers map[string]string, patch interface{}, opts ...RequestOption) (HTTPResponse, error) {
+	return defaultClient.PatchJSON(URL, headers, patch, opts...)
+}
+
+// PatchJSON sends a PATCH request to URL with patch: a JSONPatch is sent as
+// application/json-patch+json, any other value as a JSON Merge Patch
+// (application/merge-patch+json), e.g. a map or a struct with omitempty fields.
+// The merge patches, idempotent, are retried like PUT requests, while the
+// JSON Patches, which may not be (e.g. appending to an array), are retried
+// only when the server didn't process them, as for rate limits.
+func (c *Client) PatchJSON(URL string, headers map[string]string, patch interface{}, opts ...RequestOption) (HTTPResponse, error) {
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return HTTPResponse{}, err
+	}
+
+	h := map[string]string{"Content-Type": contentTypeMergePatch}
+	if _, ok := patch.(JSONPatch); ok {
+		h["Content-Type"] = contentTypeJSONPatch
+	} else {
+		opts = append(opts, func(cfg *requestConfig) {
+			cfg.idempotent = true
+		})
+	}
+	for k, v := range headers {
+		h[k] = v
+	}
+
+	return c.Request(URL, "PATCH", h, bytes.NewReader(body), opts...)
+}
@@ -0,0 +1,61 @@
+package httpclient
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestPatchJSON should test that JSON Patches and merge patches are sent with their content types.
+func TestPatchJSON(t *testing.T) {
+	var contentType, body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		contentType, body = r.Header.Get("Content-Type"), string(b)
+	}))
+	defer ts.Close()
+
+	patch := JSONPatch{
+		{Op: "replace", Path: "/description", Value: nil},
+		{Op: "remove", Path: "/tags/0"},
+		{Op: "move", From: "/old", Path: "/new"},
+	}
+	if _, err := PatchJSON(ts.URL, nil, patch); err != nil {
+		t.Fatalf("TestPatchJSON was incorrect, got error: %v", err)
+	}
+	want := `[{"op":"replace","path":"/description","value":null},{"op":"remove","path":"/tags/0"},{"op":"move","path":"/new","from":"/old"}]`
+	if contentType != "application/json-patch+json" || body != want {
+		t.Errorf("TestPatchJSON was incorrect, got: %s %s, want: application/json-patch+json %s.", contentType, body, want)
+	}
+
+	if _, err := PatchJSON(ts.URL, nil, map[string]interface{}{"name": "software", "logo": nil}); err != nil {
+		t.Fatalf("TestPatchJSON was incorrect, got error: %v", err)
+	}
+	want = `{"logo":null,"name":"software"}`
+	if contentType != "application/merge-patch+json" || body != want {
+		t.Errorf("TestPatchJSON was incorrect, got: %s %s, want: application/merge-patch+json %s.", contentType, body, want)
+	}
+}
+
+// TestPatchJSONRetry should test that only the merge patches are retried when the body read fails.
+func TestPatchJSONRetry(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		// The response is shorter than its Content-Length.
+		w.Header().Set("Content-Length", "10")
+		w.Write([]byte("short"))
+	}))
+	defer ts.Close()
+
+	c := New(WithClock(newTestClock()))
+	if _, err := c.PatchJSON(ts.URL, nil, JSONPatch{{Op: "add", Path: "/tags/-", Value: "new"}}); err == nil || hits != 1 {
+		t.Errorf("TestPatchJSONRetry was incorrect, got: %d attempts, error: %v, want: a single failed attempt.", hits, err)
+	}
+
+	hits = 0
+	if _, err := c.PatchJSON(ts.URL, nil, map[string]string{"name": "software"}); err == nil || hits < 2 {
+		t.Errorf("TestPatchJSONRetry was incorrect, got: %d attempts, error: %v, want: the merge patch retried.", hits, err)
+	}
+}
@@ -0,0 +1,69 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPaused is returned by the requests of a paused Client set with WithPauseFailFast.
+var ErrPaused = errors.New("client paused")
+
+// WithPauseFailFast makes the requests of a paused Client fail with ErrPaused
+// instead of waiting for Resume.
+func WithPauseFailFast() Option {
+	return func(c *Client) {
+		c.pauseFailFast = true
+	}
+}
+
+// Pause halts the outbound traffic of the Client, e.g. during a cooldown
+// asked by a forge: the attempts of the requests wait for Resume, or fail
+// with ErrPaused with WithPauseFailFast. The requests in flight complete.
+func (c *Client) Pause() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+
+	if c.resumed == nil {
+		c.resumed = make(chan struct{})
+	}
+}
+
+// Resume resumes the outbound traffic of a paused Client.
+func (c *Client) Resume() {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+
+	if c.resumed != nil {
+		close(c.resumed)
+		c.resumed = nil
+	}
+}
+
+// Paused reports whether the Client is paused.
+func (c *Client) Paused() bool {
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+
+	return c.resumed != nil
+}
+
+// waitResumed waits for the Client to be resumed, if paused.
+func (c *Client) waitResumed(ctx context.Context) error {
+	c.pauseMu.Lock()
+	resumed := c.resumed
+	c.pauseMu.Unlock()
+
+	if resumed == nil {
+		return nil
+	}
+	if c.pauseFailFast {
+		return ErrPaused
+	}
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,53 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestPause should test that the requests of a paused Client wait for Resume.
+func TestPause(t *testing.T) {
+	var hits int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer ts.Close()
+
+	c := New()
+	c.Pause()
+	result := make(chan error, 1)
+	go func() {
+		_, err := c.GetURL(ts.URL, nil)
+		result <- err
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if n := atomic.LoadInt32(&hits); n != 0 || !c.Paused() {
+		t.Fatalf("TestPause was incorrect, got: %d requests, want: 0 while paused.", n)
+	}
+
+	c.Resume()
+	if err := <-result; err != nil || atomic.LoadInt32(&hits) != 1 {
+		t.Errorf("TestPause was incorrect, got: %v with %d requests, want: the request sent.", err, hits)
+	}
+}
+
+// TestPauseFailFast should test that the requests of a paused Client fail with WithPauseFailFast.
+func TestPauseFailFast(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerOneRepoList))
+	defer ts.Close()
+
+	c := New(WithPauseFailFast())
+	c.Pause()
+	if _, err := c.GetURL(ts.URL, nil); !errors.Is(err, ErrPaused) {
+		t.Errorf("TestPauseFailFast was incorrect, got: %v, want: %v.", err, ErrPaused)
+	}
+	c.Resume()
+	if _, err := c.GetURL(ts.URL, nil); err != nil {
+		t.Errorf("TestPauseFailFast was incorrect, got: %v, want: <nil>.", err)
+	}
+}
@@ -0,0 +1,53 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// politenessSweepSize is the number of hosts after which the past slots are swept.
+const politenessSweepSize = 1024
+
+// politeness spaces the requests to the same host by a minimum delay.
+type politeness struct {
+	delay time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+// WithPolitenessDelay spaces by at least d the successive requests to the
+// same host, retries included, whatever its rate limits, as polite crawlers
+// of small websites do.
+func WithPolitenessDelay(d time.Duration) Option {
+	return func(c *Client) {
+		c.politeness = &politeness{delay: d, next: make(map[string]time.Time)}
+	}
+}
+
+// waitPoliteness waits for the turn of a request to host, spaced by delay
+// from the next one.
+func (c *Client) waitPoliteness(ctx context.Context, host string, delay time.Duration) error {
+	p := c.politeness
+
+	// Reserve the next slot, then wait for it.
+	p.mu.Lock()
+	now := c.clock.Now()
+	slot, ok := p.next[host]
+	if !ok || slot.Before(now) {
+		slot = now
+	}
+	p.next[host] = slot.Add(delay)
+	// Forget the hosts whose slots are past.
+	if len(p.next) > politenessSweepSize {
+		for h, next := range p.next {
+			if next.Before(now) {
+				delete(p.next, h)
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	return c.sleepContext(ctx, slot.Sub(now))
+}
@@ -0,0 +1,33 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestPolitenessDelay should test that the requests to the same host are spaced.
+func TestPolitenessDelay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerOneRepoList))
+	defer ts.Close()
+	other := strings.Replace(ts.URL, "127.0.0.1", "localhost", 1)
+
+	clock := newTestClock()
+	c := New(WithClock(clock), WithPolitenessDelay(2*time.Second))
+	for _, URL := range []string{ts.URL, other, ts.URL, ts.URL} {
+		if _, err := c.GetURL(URL, nil); err != nil {
+			t.Fatalf("TestPolitenessDelay was incorrect, got error: %v", err)
+		}
+	}
+
+	// The first requests to each host don't wait.
+	var slept time.Duration
+	for _, d := range clock.Sleeps() {
+		slept += d
+	}
+	if slept != 4*time.Second {
+		t.Errorf("TestPolitenessDelay was incorrect, got: %v, want: 4s.", slept)
+	}
+}
@@ -0,0 +1,104 @@
+package httpclient
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// pollJitter is the fraction of the interval by which each wait of Poll is
+// randomly shortened or lengthened, so that the pollers don't synchronize.
+const pollJitter = 0.1
+
+// maxPollBackoffIntervals bounds the wait after the failures of Poll to these
+// many intervals, unless WithBackoffBounds sets a maximum.
+const maxPollBackoffIntervals = 10
+
+// Poller is a polling started by Poll.
+type Poller struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Stop stops the polling, waiting for the fetch or the handler in progress.
+func (p *Poller) Stop() {
+	p.cancel()
+	<-p.done
+}
+
+// Done returns a channel closed when the polling stops.
+func (p *Poller) Done() <-chan struct{} {
+	return p.done
+}
+
+// Poll GETs URL right away, then every interval, until ctx is done or the
+// returned Poller is stopped, calling handler with each outcome. The requests
+// are conditional on the ETag and Last-Modified of the last response, so
+// handler gets a 304 (Not Modified) response when the resource didn't change.
+// The waits are randomized by 10% of interval, and grow exponentially beyond
+// interval while the fetches keep failing, up to 10 intervals or the maximum
+// of WithBackoffBounds.
+func Poll(ctx context.Context, URL string, headers map[string]string, interval time.Duration, handler func(HTTPResponse, error), opts ...RequestOption) *Poller {
+	return defaultClient.Poll(ctx, URL, headers, interval, handler, opts...)
+}
+
+// Poll GETs URL right away, then every interval, until ctx is done or the
+// returned Poller is stopped, calling handler with each outcome. The requests
+// are conditional on the ETag and Last-Modified of the last response, so
+// handler gets a 304 (Not Modified) response when the resource didn't change.
+// The waits are randomized by 10% of interval, and grow exponentially beyond
+// interval while the fetches keep failing, up to 10 intervals or the maximum
+// of WithBackoffBounds.
+func (c *Client) Poll(ctx context.Context, URL string, headers map[string]string, interval time.Duration, handler func(HTTPResponse, error), opts ...RequestOption) *Poller {
+	ctx, cancel := context.WithCancel(ctx)
+	p := &Poller{cancel: cancel, done: make(chan struct{})}
+	opts = append(opts, returnNotModified)
+
+	maxBackoff := maxPollBackoffIntervals * interval
+	if c.maxBackoff > 0 {
+		maxBackoff = c.maxBackoff
+	}
+
+	go func() {
+		defer close(p.done)
+		defer cancel()
+
+		var v Validators
+		failures := 0
+		for {
+			resp, err := c.RequestContext(ctx, URL, "GET", v.headers(headers), nil, opts...)
+			if ctx.Err() != nil {
+				return
+			}
+			switch {
+			case err != nil:
+				failures++
+			case resp.Status.Code != http.StatusNotModified:
+				failures = 0
+				v = responseValidators(resp)
+			default:
+				failures = 0
+			}
+			handler(resp, err)
+
+			wait := jitter(interval)
+			if backoff := cappedBackoff(failures, maxBackoff); backoff > wait {
+				log.Infof("Polling failed %d times (%v), sleep %v - Resource: %s", failures, err, backoff, URL)
+				wait = backoff
+			}
+			if err := c.sleepContext(ctx, wait); err != nil {
+				return
+			}
+		}
+	}()
+
+	return p
+}
+
+// jitter returns d randomly shortened or lengthened by up to pollJitter.
+func jitter(d time.Duration) time.Duration {
+	return time.Duration(float64(d) * (1 + pollJitter*(2*rand.Float64()-1)))
+}
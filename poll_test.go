@@ -0,0 +1,124 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestPoll should test that the resource is fetched on schedule, conditionally, backing off on failures.
+func TestPoll(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		switch {
+		case hits == 3 || hits == 4:
+			w.WriteHeader(http.StatusInternalServerError)
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			w.Write([]byte("data"))
+		}
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	c := New(WithClock(clock))
+	ctx, cancel := context.WithCancel(context.Background())
+	var statuses []int
+	p := c.Poll(ctx, ts.URL, nil, time.Minute, func(resp HTTPResponse, err error) {
+		if statuses = append(statuses, resp.Status.Code); len(statuses) == 6 {
+			cancel()
+		}
+	})
+
+	select {
+	case <-p.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("TestPoll was incorrect, the polling didn't stop.")
+	}
+
+	want := []int{200, 304, 500, 500, 304, 304}
+	for i := range want {
+		if i >= len(statuses) || statuses[i] != want[i] {
+			t.Fatalf("TestPoll was incorrect, got statuses: %v, want: %v.", statuses, want)
+		}
+	}
+	for i, d := range clock.Sleeps()[:4] {
+		if d < 54*time.Second || d > 66*time.Second {
+			t.Errorf("TestPoll was incorrect, got wait %d: %v, want: a minute with jitter.", i, d)
+		}
+	}
+}
+
+// TestPollBackoff should test that the waits grow beyond the interval on repeated failures.
+func TestPollBackoff(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	ctx, cancel := context.WithCancel(context.Background())
+	failures := 0
+	p := New(WithClock(clock)).Poll(ctx, ts.URL, nil, time.Second, func(_ HTTPResponse, err error) {
+		if err == nil {
+			t.Errorf("TestPollBackoff was incorrect, got no error for a 502.")
+		}
+		if failures++; failures == 4 {
+			cancel()
+		}
+	})
+	<-p.Done()
+
+	sleeps := clock.Sleeps()
+	if len(sleeps) < 3 || sleeps[1] != 1500*time.Millisecond || sleeps[2] != 3500*time.Millisecond {
+		t.Errorf("TestPollBackoff was incorrect, got waits: %v, want: about 1s, then 1.5s and 3.5s.", sleeps)
+	}
+}
+
+// TestPollBackoffBound should test that the waits after many failures are bounded by 10 intervals.
+func TestPollBackoffBound(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	ctx, cancel := context.WithCancel(context.Background())
+	failures := 0
+	p := New(WithClock(clock)).Poll(ctx, ts.URL, nil, time.Second, func(HTTPResponse, error) {
+		if failures++; failures == 40 {
+			cancel()
+		}
+	})
+	<-p.Done()
+
+	for _, d := range clock.Sleeps() {
+		if d > 10*time.Second {
+			t.Fatalf("TestPollBackoffBound was incorrect, got wait: %v, want: at most 10s.", d)
+		}
+	}
+}
+
+// TestPollerStop should test that Stop stops the polling.
+func TestPollerStop(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerOneRepoList))
+	defer ts.Close()
+
+	fetched := make(chan struct{}, 1)
+	p := Poll(context.Background(), ts.URL, nil, time.Hour, func(HTTPResponse, error) {
+		fetched <- struct{}{}
+	})
+	<-fetched
+	p.Stop()
+
+	select {
+	case <-p.Done():
+	default:
+		t.Errorf("TestPollerStop was incorrect, the polling is still running after Stop.")
+	}
+}
@@ -0,0 +1,37 @@
+package httpclient
+
+import (
+	"net/http"
+	"time"
+)
+
+// WithIfMatch makes the request conditional on the resource still having
+// etag, e.g. the ETag of a previous response, so that a read-modify-write
+// fails with ErrPreconditionFailed instead of overwriting a concurrent change.
+func WithIfMatch(etag string) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.setHeader("If-Match", etag)
+	}
+}
+
+// WithIfUnmodifiedSince makes the request conditional on the resource not
+// being modified after t, failing with ErrPreconditionFailed otherwise.
+func WithIfUnmodifiedSince(t time.Time) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.setHeader("If-Unmodified-Since", t.UTC().Format(http.TimeFormat))
+	}
+}
+
+// WithPreconditions makes the request conditional on the resource being the
+// one of resp, a previous response: on its ETag if any, otherwise on its
+// Last-Modified, as by WithIfMatch and WithIfUnmodifiedSince.
+func WithPreconditions(resp HTTPResponse) RequestOption {
+	return func(cfg *requestConfig) {
+		switch {
+		case resp.ETag != "":
+			WithIfMatch(resp.ETag)(cfg)
+		case !resp.LastModified.IsZero():
+			WithIfUnmodifiedSince(resp.LastModified)(cfg)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithPreconditions should test that a write of a resource changed in the meantime fails.
+func TestWithPreconditions(t *testing.T) {
+	etag := `"v1"`
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" {
+			w.Header().Set("ETag", etag)
+			return
+		}
+		if r.Header.Get("If-Match") != etag {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return
+		}
+		etag = `"v2"`
+	}))
+	defer ts.Close()
+
+	read, err := GetURL(ts.URL, nil)
+	if err != nil {
+		t.Fatalf("TestWithPreconditions was incorrect, got error: %v", err)
+	}
+	if _, err := Request(ts.URL, "PUT", nil, strings.NewReader("first"), WithPreconditions(read)); err != nil {
+		t.Errorf("TestWithPreconditions was incorrect, got error: %v for the first write.", err)
+	}
+
+	resp, err := Request(ts.URL, "PUT", nil, strings.NewReader("second"), WithPreconditions(read))
+	var httpErr *HTTPError
+	if !errors.Is(err, ErrPreconditionFailed) || !errors.As(err, &httpErr) || resp.Status.Code != http.StatusPreconditionFailed {
+		t.Errorf("TestWithPreconditions was incorrect, got: %d, error: %v, want: %v.", resp.Status.Code, err, ErrPreconditionFailed)
+	}
+}
+
+// TestWithIfUnmodifiedSince should test that the date is sent in the HTTP format.
+func TestWithIfUnmodifiedSince(t *testing.T) {
+	var got string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("If-Unmodified-Since")
+	}))
+	defer ts.Close()
+
+	modified := time.Date(2021, 3, 4, 5, 6, 7, 0, time.FixedZone("CET", 3600))
+	if _, err := Request(ts.URL, "DELETE", nil, nil, WithPreconditions(HTTPResponse{LastModified: modified})); err != nil {
+		t.Fatalf("TestWithIfUnmodifiedSince was incorrect, got error: %v", err)
+	}
+	if want := "Thu, 04 Mar 2021 04:06:07 GMT"; got != want {
+		t.Errorf("TestWithIfUnmodifiedSince was incorrect, got: %q, want: %q.", got, want)
+	}
+}
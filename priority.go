@@ -0,0 +1,117 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+)
+
+// Priority is the priority of a request waiting for a concurrency slot.
+type Priority int
+
+// Priorities of the requests, PriorityNormal by default.
+const (
+	PriorityLow    Priority = -1
+	PriorityNormal Priority = 0
+	PriorityHigh   Priority = 1
+)
+
+// fairnessEvery is how often a slot goes to the request waiting the longest
+// whatever its priority, so that low priority requests are not starved.
+const fairnessEvery = 4
+
+// priorityKey is the context key of the priority of a request.
+type priorityKey struct{}
+
+// WithPriority sets the priority of the request: when the slots of
+// WithMaxInflightPerHost or WithMaxConcurrentRequests are all taken, the
+// waiting requests get them in order of priority, e.g. interactive lookups
+// before a bulk crawl.
+func WithPriority(p Priority) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.priority = p
+	}
+}
+
+// requestPriority returns the priority of the request of ctx.
+func requestPriority(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
+
+// prioritySemaphore is a counting semaphore granting the free slots to the
+// waiters with the highest priority, and every fairnessEvery grants to the
+// oldest waiter.
+type prioritySemaphore struct {
+	size int
+
+	mu      sync.Mutex
+	used    int
+	grants  int
+	waiters []*semaphoreWaiter
+}
+
+// semaphoreWaiter is a waiter of a prioritySemaphore, ready is closed when
+// it's granted a slot.
+type semaphoreWaiter struct {
+	priority Priority
+	ready    chan struct{}
+}
+
+func newPrioritySemaphore(size int) *prioritySemaphore {
+	return &prioritySemaphore{size: size}
+}
+
+// acquire waits for a slot, returning ctx.Err() if ctx is done first.
+func (s *prioritySemaphore) acquire(ctx context.Context, p Priority) error {
+	s.mu.Lock()
+	if s.used < s.size && len(s.waiters) == 0 {
+		s.used++
+		s.mu.Unlock()
+		return nil
+	}
+	w := &semaphoreWaiter{priority: p, ready: make(chan struct{})}
+	s.waiters = append(s.waiters, w)
+	s.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for i, waiter := range s.waiters {
+			if waiter == w {
+				s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+				s.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		s.mu.Unlock()
+		// Granted in the meantime.
+		s.release()
+		return ctx.Err()
+	}
+}
+
+// release frees a slot, handing it to the next waiter if any.
+func (s *prioritySemaphore) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.waiters) == 0 {
+		s.used--
+		return
+	}
+
+	next := 0
+	s.grants++
+	if s.grants%fairnessEvery != 0 {
+		for i, w := range s.waiters {
+			if w.priority > s.waiters[next].priority {
+				next = i
+			}
+		}
+	}
+	w := s.waiters[next]
+	s.waiters = append(s.waiters[:next], s.waiters[next+1:]...)
+	close(w.ready)
+}
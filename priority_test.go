@@ -0,0 +1,72 @@
+package httpclient
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestPrioritySemaphore should test that the slots go to the highest priority
+// waiters, and every fairnessEvery grants to the oldest one.
+func TestPrioritySemaphore(t *testing.T) {
+	s := newPrioritySemaphore(1)
+	if err := s.acquire(context.Background(), PriorityNormal); err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan Priority, 8)
+	waiting := 0
+	wait := func(p Priority) {
+		go func() {
+			s.acquire(context.Background(), p)
+			order <- p
+		}()
+		// Let the waiter queue up.
+		waiting++
+		for {
+			s.mu.Lock()
+			n := len(s.waiters)
+			s.mu.Unlock()
+			if n == waiting {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	wait(PriorityLow)
+	for i := 0; i < 4; i++ {
+		wait(PriorityHigh)
+	}
+
+	var got []Priority
+	for i := 0; i < 5; i++ {
+		s.release()
+		got = append(got, <-order)
+	}
+
+	// The fourth grant goes to the low priority waiter, the oldest.
+	want := []Priority{PriorityHigh, PriorityHigh, PriorityHigh, PriorityLow, PriorityHigh}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("TestPrioritySemaphore was incorrect, got: %v, want: %v.", got, want)
+			break
+		}
+	}
+}
+
+// TestPrioritySemaphoreCancel should test that a canceled waiter gives up its place.
+func TestPrioritySemaphoreCancel(t *testing.T) {
+	s := newPrioritySemaphore(1)
+	s.acquire(context.Background(), PriorityNormal)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.acquire(ctx, PriorityHigh); err != context.DeadlineExceeded {
+		t.Errorf("TestPrioritySemaphoreCancel was incorrect, got: %v, want: %v.", err, context.DeadlineExceeded)
+	}
+
+	s.release()
+	if err := s.acquire(context.Background(), PriorityLow); err != nil || s.used != 1 {
+		t.Errorf("TestPrioritySemaphoreCancel was incorrect, got: %v with %d used, want: the slot.", err, s.used)
+	}
+}
@@ -0,0 +1,96 @@
+package httpclient
+
+import (
+	"encoding/json"
+	"mime"
+)
+
+// problemMediaType is the media type of RFC 7807 problem details.
+const problemMediaType = "application/problem+json"
+
+// maxProblemSize is the maximum size of the problem details read from a body.
+const maxProblemSize = 1 << 20
+
+// ProblemDetails are the RFC 7807 problem details of an error response.
+type ProblemDetails struct {
+	Type     string
+	Title    string
+	Status   int
+	Detail   string
+	Instance string
+	// Extensions holds the members not defined by RFC 7807.
+	Extensions map[string]interface{}
+}
+
+// ProblemError is returned for error responses with problem details.
+// Use errors.As to get the details of an error returned by the Client.
+type ProblemError struct {
+	Problem ProblemDetails
+	Err     error
+}
+
+func (e *ProblemError) Error() string {
+	msg := e.Err.Error()
+	if e.Problem.Title != "" {
+		msg += ": " + e.Problem.Title
+	}
+	if e.Problem.Detail != "" {
+		msg += ": " + e.Problem.Detail
+	}
+
+	return msg
+}
+
+func (e *ProblemError) Unwrap() error {
+	return e.Err
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+
+	fields := map[string]interface{}{
+		"type":     &p.Type,
+		"title":    &p.Title,
+		"status":   &p.Status,
+		"detail":   &p.Detail,
+		"instance": &p.Instance,
+	}
+	for name, raw := range members {
+		if field, ok := fields[name]; ok {
+			// Members of the wrong type are ignored, as for unknown ones.
+			json.Unmarshal(raw, field)
+			continue
+		}
+
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return err
+		}
+		if p.Extensions == nil {
+			p.Extensions = make(map[string]interface{})
+		}
+		p.Extensions[name] = v
+	}
+
+	return nil
+}
+
+// problemError wraps err in a *ProblemError if resp carries problem details,
+// otherwise it returns err.
+func problemError(resp HTTPResponse, err error) error {
+	mediaType, _, _ := mime.ParseMediaType(resp.Headers.Get("Content-Type"))
+	if mediaType != problemMediaType || len(resp.Body) > maxProblemSize {
+		return err
+	}
+
+	var problem ProblemDetails
+	if json.Unmarshal(resp.Body, &problem) != nil {
+		return err
+	}
+
+	return &ProblemError{Problem: problem, Err: err}
+}
@@ -0,0 +1,33 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// handlerProblem returns a 404 with RFC 7807 problem details.
+func handlerProblem(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/problem+json; charset=utf-8")
+	w.WriteHeader(http.StatusNotFound)
+	fmt.Fprint(w, `{"type": "https://example.com/probs/no-repo", "title": "Repository not found", "status": 404, "repo": "italia/missing"}`)
+}
+
+// TestProblemDetails should test that problem details are attached to the error.
+func TestProblemDetails(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(handlerProblem))
+	defer ts.Close()
+
+	_, err := GetURL(ts.URL, nil)
+	var problemErr *ProblemError
+	if !errors.As(err, &problemErr) {
+		t.Fatalf("TestProblemDetails was incorrect, got error: %v", err)
+	}
+
+	p := problemErr.Problem
+	if p.Type != "https://example.com/probs/no-repo" || p.Title != "Repository not found" || p.Status != 404 || p.Extensions["repo"] != "italia/missing" {
+		t.Errorf("TestProblemDetails was incorrect, got: %+v", p)
+	}
+}
@@ -0,0 +1,54 @@
+package httpclient
+
+import "time"
+
+// ProfileCrawler configures the Client for crawling the websites of third
+// parties, like the ones of the public administrations: the requests to the
+// same host are spaced by 1 second, the internal addresses are blocked by
+// WithSSRFGuard, the DNS lookups are cached, and the failures are retried
+// up to 10 times, each wait bounded to 5 minutes.
+// The options following it override its settings.
+func ProfileCrawler() Option {
+	return withOptions(
+		WithPolitenessDelay(time.Second),
+		WithSSRFGuard(),
+		WithDNSCache(time.Minute, 10*time.Minute),
+		WithTimeouts(Timeouts{Dial: 10 * time.Second, ResponseHeader: 30 * time.Second, Total: 2 * time.Minute}),
+		WithMaxRetries(10),
+		WithBackoffBounds(0, 5*time.Minute),
+	)
+}
+
+// ProfileAPI configures the Client for the calls to an API, failing fast:
+// tight timeouts, at most 2 retries, each wait bounded to 10 seconds.
+// The options following it override its settings.
+func ProfileAPI() Option {
+	return withOptions(
+		WithTimeouts(Timeouts{Dial: 3 * time.Second, TLSHandshake: 3 * time.Second, ResponseHeader: 10 * time.Second, Total: 15 * time.Second}),
+		WithMaxRetries(2),
+		WithBackoffBounds(0, 10*time.Second),
+	)
+}
+
+// ProfileBulkDownload configures the Client for the large downloads streamed
+// by DownloadFile and GetToWriter: the bodies are read without a total timeout,
+// however long they take, on up to 4 connections per host, the servers slow
+// to respond are waited for up to 1 minute and the failures retried up to 5
+// times. The requests WithStallTimeout detect the dead connections.
+// The options following it override its settings.
+func ProfileBulkDownload() Option {
+	return withOptions(
+		WithTimeouts(Timeouts{Dial: 30 * time.Second, ResponseHeader: time.Minute}),
+		WithMaxInflightPerHost(4),
+		WithMaxRetries(5),
+	)
+}
+
+// withOptions returns the Option applying opts in order.
+func withOptions(opts ...Option) Option {
+	return func(c *Client) {
+		for _, opt := range opts {
+			opt(c)
+		}
+	}
+}
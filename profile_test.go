@@ -0,0 +1,44 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestProfiles should test that the profiles are overridden by the options following them.
+func TestProfiles(t *testing.T) {
+	c := New(ProfileCrawler(), WithMaxRetries(3))
+	if !c.ssrfGuard || c.politeness == nil || c.politeness.delay != time.Second || c.maxRetries != 3 {
+		t.Errorf("TestProfiles was incorrect, got: guard %v, %d retries, want: the crawler profile with 3 retries.", c.ssrfGuard, c.maxRetries)
+	}
+	if c := New(ProfileAPI()); c.httpClient.Timeout != 15*time.Second || c.maxRetries != 2 {
+		t.Errorf("TestProfiles was incorrect, got: %v, %d retries, want: the API profile.", c.httpClient.Timeout, c.maxRetries)
+	}
+	if c := New(ProfileBulkDownload()); c.httpClient.Timeout != 0 || c.maxInflightPerHost != 4 {
+		t.Errorf("TestProfiles was incorrect, got: %v, %d per host, want: the bulk download profile.", c.httpClient.Timeout, c.maxInflightPerHost)
+	}
+}
+
+// TestProfileAPIRetries should test that the API profile retries twice, with bounded waits.
+func TestProfileAPIRetries(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	c := New(ProfileAPI(), WithClock(clock), WithStatusHandler(http.StatusServiceUnavailable, func(*HTTPResponse) (StatusAction, error) {
+		return StatusRetry, nil
+	}))
+	if _, err := c.GetURL(ts.URL, nil); err == nil || hits != 3 {
+		t.Errorf("TestProfileAPIRetries was incorrect, got: %d hits, %v, want: 3 hits, an error.", hits, err)
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 2 || sleeps[0] != 10*time.Second {
+		t.Errorf("TestProfileAPIRetries was incorrect, got sleeps: %v, want: [10s 10s].", sleeps)
+	}
+}
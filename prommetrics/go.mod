@@ -0,0 +1,10 @@
+module github.com/italia/httpclient-lib-go/prommetrics
+
+go 1.14
+
+require (
+	github.com/italia/httpclient-lib-go v0.0.0-00010101000000-000000000000
+	github.com/prometheus/client_golang v1.11.1
+)
+
+replace github.com/italia/httpclient-lib-go => ../
@@ -0,0 +1,123 @@
+// Package prommetrics records the metrics of an httpclient.Client to Prometheus:
+//
+//	client := httpclient.New(httpclient.WithMetrics(prommetrics.New(prometheus.DefaultRegisterer)))
+//
+// The metrics are registered on their first use, with the label names of that use.
+// The package is a module of its own, so that the users of httpclient alone
+// don't depend on Prometheus.
+package prommetrics
+
+import (
+	"sort"
+	"sync"
+
+	httpclient "github.com/italia/httpclient-lib-go"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Recorder is an httpclient.MetricsRecorder backed by Prometheus collectors.
+type Recorder struct {
+	registerer prometheus.Registerer
+
+	mu         sync.Mutex
+	counters   map[string]*prometheus.CounterVec
+	histograms map[string]*prometheus.HistogramVec
+	gauges     map[string]*prometheus.GaugeVec
+}
+
+var _ httpclient.MetricsRecorder = (*Recorder)(nil)
+
+// New returns a Recorder registering its collectors on registerer.
+func New(registerer prometheus.Registerer) *Recorder {
+	return &Recorder{
+		registerer: registerer,
+		counters:   make(map[string]*prometheus.CounterVec),
+		histograms: make(map[string]*prometheus.HistogramVec),
+		gauges:     make(map[string]*prometheus.GaugeVec),
+	}
+}
+
+// IncCounter increments the counter name.
+func (r *Recorder) IncCounter(name string, labels map[string]string) {
+	r.mu.Lock()
+	vec, ok := r.counters[name]
+	if !ok {
+		vec = prometheus.NewCounterVec(prometheus.CounterOpts{Name: name, Help: help(name)}, labelNames(labels))
+		vec = r.register(vec).(*prometheus.CounterVec)
+		r.counters[name] = vec
+	}
+	r.mu.Unlock()
+
+	vec.With(labels).Inc()
+}
+
+// ObserveHistogram adds value to the histogram name, with the default buckets.
+func (r *Recorder) ObserveHistogram(name string, value float64, labels map[string]string) {
+	r.mu.Lock()
+	vec, ok := r.histograms[name]
+	if !ok {
+		vec = prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: name, Help: help(name)}, labelNames(labels))
+		vec = r.register(vec).(*prometheus.HistogramVec)
+		r.histograms[name] = vec
+	}
+	r.mu.Unlock()
+
+	vec.With(labels).Observe(value)
+}
+
+// SetGauge sets the gauge name to value.
+func (r *Recorder) SetGauge(name string, value float64, labels map[string]string) {
+	r.mu.Lock()
+	vec, ok := r.gauges[name]
+	if !ok {
+		vec = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: name, Help: help(name)}, labelNames(labels))
+		vec = r.register(vec).(*prometheus.GaugeVec)
+		r.gauges[name] = vec
+	}
+	r.mu.Unlock()
+
+	vec.With(labels).Set(value)
+}
+
+// register registers c, returning the collector already registered with the
+// same name if any, e.g. by another Recorder.
+func (r *Recorder) register(c prometheus.Collector) prometheus.Collector {
+	if r.registerer == nil {
+		return c
+	}
+	if err := r.registerer.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+		panic(err)
+	}
+
+	return c
+}
+
+// labelNames returns the sorted names of labels.
+func labelNames(labels map[string]string) []string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// help returns the help text of the metric name.
+func help(name string) string {
+	switch name {
+	case httpclient.MetricRequests:
+		return "Requests returned by the HTTP client."
+	case httpclient.MetricRetries:
+		return "Attempts retried by the HTTP client."
+	case httpclient.MetricRequestDuration:
+		return "Seconds taken by the requests of the HTTP client, retries included."
+	case httpclient.MetricRateLimitRemaining:
+		return "Last rate limit remaining reported by the host."
+	}
+
+	return name
+}
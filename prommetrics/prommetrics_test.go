@@ -0,0 +1,51 @@
+package prommetrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	httpclient "github.com/italia/httpclient-lib-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// TestRecorder should test that the requests of a Client are recorded to Prometheus.
+func TestRecorder(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "41")
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	registry := prometheus.NewRegistry()
+	c := httpclient.New(httpclient.WithMetrics(New(registry)))
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetURL(ts.URL, nil); err != nil {
+			t.Fatalf("TestRecorder was incorrect, got error: %v", err)
+		}
+	}
+
+	host := strings.TrimPrefix(ts.URL, "http://")
+	want := `
+# HELP httpclient_requests_total Requests returned by the HTTP client.
+# TYPE httpclient_requests_total counter
+httpclient_requests_total{host="` + host + `",method="GET",status="200"} 2
+# HELP httpclient_ratelimit_remaining Last rate limit remaining reported by the host.
+# TYPE httpclient_ratelimit_remaining gauge
+httpclient_ratelimit_remaining{host="` + host + `"} 41
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), httpclient.MetricRequests, httpclient.MetricRateLimitRemaining); err != nil {
+		t.Errorf("TestRecorder was incorrect, got: %v", err)
+	}
+	if n, err := testutil.GatherAndCount(registry, httpclient.MetricRequestDuration); err != nil || n != 1 {
+		t.Errorf("TestRecorder was incorrect, got: %d duration histograms, want: 1.", n)
+	}
+
+	// A second Recorder on the same registry shares the collectors.
+	New(registry).IncCounter(httpclient.MetricRequests, map[string]string{"host": host, "method": "GET", "status": "200"})
+	if n, err := testutil.GatherAndCount(registry, httpclient.MetricRequests); err != nil || n != 1 {
+		t.Errorf("TestRecorder was incorrect, got: %d request counters, want: 1.", n)
+	}
+}
@@ -0,0 +1,343 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// maxQueueBackoff bounds the wait before retrying a queued request.
+const maxQueueBackoff = time.Hour
+
+// QueuedRequest is a request of a Queue, persisted until delivered.
+type QueuedRequest struct {
+	ID      string            `json:"id"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    []byte            `json:"body,omitempty"`
+	// Attempts is the number of failed attempts, NextAttempt when to retry.
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	Created     time.Time `json:"created"`
+}
+
+// QueueStore persists the requests of a Queue across restarts. It must be
+// safe for concurrent use.
+type QueueStore interface {
+	// Save adds or replaces the request with the same ID.
+	Save(r QueuedRequest) error
+	// Delete removes the request with id, if any.
+	Delete(id string) error
+	// Load returns the requests saved.
+	Load() ([]QueuedRequest, error)
+}
+
+// FileStore is a QueueStore keeping each request in a JSON file of a directory.
+type FileStore struct {
+	dir string
+}
+
+// NewFileStore returns a FileStore keeping the requests in dir, created if missing.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &FileStore{dir: dir}, nil
+}
+
+// Save writes r to its file, atomically replacing the previous version.
+func (s *FileStore) Save(r QueuedRequest) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(s.dir, r.ID+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(r.ID))
+}
+
+// Delete removes the file of the request with id.
+func (s *FileStore) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Load reads the requests from the files of the directory.
+func (s *FileStore) Load() ([]QueuedRequest, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	requests := make([]QueuedRequest, 0, len(files))
+	for _, file := range files {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		var r QueuedRequest
+		if err := json.Unmarshal(b, &r); err != nil {
+			log.Warnf("Skipping the invalid queued request %s: %v", file, err)
+			continue
+		}
+		requests = append(requests, r)
+	}
+
+	return requests, nil
+}
+
+func (s *FileStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// Queue delivers fire-and-forget requests in the background, retrying them
+// with an exponential backoff until delivered, across restarts through its
+// QueueStore. A request is delivered by a 2xx response, and dropped to the
+// dead letter callback on the other 4xx statuses but 408 and 429.
+type Queue struct {
+	client      *Client
+	store       QueueStore
+	maxAttempts int
+	deadLetter  func(QueuedRequest, error)
+
+	mu      sync.Mutex
+	pending map[string]*QueuedRequest
+
+	wake      chan struct{}
+	closed    chan struct{}
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// QueueOption configures a Queue.
+type QueueOption func(*Queue)
+
+// WithQueueMaxAttempts drops the requests failed n times to the dead letter
+// callback. By default the requests are retried forever.
+func WithQueueMaxAttempts(n int) QueueOption {
+	return func(q *Queue) {
+		q.maxAttempts = n
+	}
+}
+
+// WithQueueDeadLetter sets the callback invoked with the requests dropped
+// and the error of their last attempt.
+func WithQueueDeadLetter(fn func(r QueuedRequest, err error)) QueueOption {
+	return func(q *Queue) {
+		q.deadLetter = fn
+	}
+}
+
+// NewQueue returns a Queue sending its requests through c, resuming the
+// requests left in store, and starts delivering them. Close stops it.
+func NewQueue(c *Client, store QueueStore, opts ...QueueOption) (*Queue, error) {
+	q := &Queue{
+		client:  c,
+		store:   store,
+		pending: make(map[string]*QueuedRequest),
+		wake:    make(chan struct{}, 1),
+		closed:  make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	requests, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	for i := range requests {
+		q.pending[requests[i].ID] = &requests[i]
+	}
+
+	go q.run()
+
+	return q, nil
+}
+
+// Enqueue persists a request and schedules its delivery, returning its ID.
+func (q *Queue) Enqueue(method, URL string, headers map[string]string, body []byte) (string, error) {
+	now := q.client.clock.Now()
+	r := QueuedRequest{
+		ID:          randomHex(16),
+		Method:      method,
+		URL:         URL,
+		Headers:     headers,
+		Body:        body,
+		NextAttempt: now,
+		Created:     now,
+	}
+	if err := q.store.Save(r); err != nil {
+		return "", err
+	}
+
+	q.mu.Lock()
+	q.pending[r.ID] = &r
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+
+	return r.ID, nil
+}
+
+// Len returns the number of requests waiting to be delivered.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+// Close stops the deliveries, waiting for the one in progress. The requests
+// left stay in the store. The Queue stops as well when its Client is closed.
+func (q *Queue) Close() {
+	q.closeOnce.Do(func() {
+		close(q.closed)
+	})
+	<-q.done
+}
+
+// run delivers the requests as they are due, until Close or the Client is closed.
+func (q *Queue) run() {
+	defer close(q.done)
+
+	for {
+		r, wait := q.next()
+		var due <-chan time.Time
+		if r != nil {
+			if wait <= 0 {
+				if !q.deliver(r) {
+					return
+				}
+				continue
+			}
+			due = q.client.clock.After(wait)
+		}
+
+		select {
+		case <-q.closed:
+			return
+		case <-q.client.closed:
+			return
+		case <-q.wake:
+		case <-due:
+		}
+	}
+}
+
+// next returns the request due first and the time to wait for it, nil if none.
+func (q *Queue) next() (*QueuedRequest, time.Duration) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var first *QueuedRequest
+	for _, r := range q.pending {
+		if first == nil || r.NextAttempt.Before(first.NextAttempt) {
+			first = r
+		}
+	}
+	if first == nil {
+		return nil, 0
+	}
+
+	return first, first.NextAttempt.Sub(q.client.clock.Now())
+}
+
+// deliver makes an attempt to deliver r, then removes it or schedules its
+// retry. It returns false if the Queue must stop, r left to the next start.
+func (q *Queue) deliver(r *QueuedRequest) bool {
+	select {
+	case <-q.closed:
+		return false
+	default:
+	}
+
+	var body io.Reader
+	if r.Body != nil {
+		body = bytes.NewReader(r.Body)
+	}
+	resp, err := q.client.Request(r.URL, r.Method, r.Headers, body)
+	code := resp.Status.Code
+	if err == nil && (code < 200 || code > 299) {
+		err = ErrInvalidStatus
+	}
+	if errors.Is(err, ErrClientClosed) {
+		return false
+	}
+
+	if dropped, ok := q.settle(r, code, err); ok && q.deadLetter != nil {
+		// Called without the lock, as it may use the Queue, e.g. to enqueue
+		// the request again.
+		q.deadLetter(dropped, err)
+	}
+
+	return true
+}
+
+// settle removes r if delivered or dropped, else schedules its retry. It
+// returns the request dropped and true if r failed permanently.
+func (q *Queue) settle(r *QueuedRequest, code int, err error) (QueuedRequest, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if err == nil {
+		q.remove(r)
+		return QueuedRequest{}, false
+	}
+
+	r.Attempts++
+	permanent := code >= 400 && code <= 499 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+	if permanent || (q.maxAttempts > 0 && r.Attempts >= q.maxAttempts) {
+		log.Warnf("Dropping the queued request %s after %d attempts: %v - Resource: %s", r.ID, r.Attempts, err, r.URL)
+		q.remove(r)
+		return *r, true
+	}
+
+	backoff := cappedBackoff(r.Attempts, maxQueueBackoff)
+	r.NextAttempt = q.client.clock.Now().Add(backoff)
+	log.Debugf("Queued request %s failed, retrying in %v: %v - Resource: %s", r.ID, backoff, err, r.URL)
+	if err := q.store.Save(*r); err != nil {
+		log.Warnf("Can't save the queued request %s: %v", r.ID, err)
+	}
+
+	return QueuedRequest{}, false
+}
+
+// remove removes r from the queue and the store. q.mu must be held.
+func (q *Queue) remove(r *QueuedRequest) {
+	delete(q.pending, r.ID)
+	if err := q.store.Delete(r.ID); err != nil {
+		log.Warnf("Can't delete the queued request %s: %v", r.ID, err)
+	}
+}
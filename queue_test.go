@@ -0,0 +1,160 @@
+package httpclient
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// waitQueue waits for q to be empty.
+func waitQueue(t *testing.T, q *Queue) {
+	deadline := time.Now().Add(5 * time.Second)
+	for q.Len() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("waitQueue: got %d requests left, want: 0.", q.Len())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestQueue should test that the queued requests are retried with backoff until delivered.
+func TestQueue(t *testing.T) {
+	var bodies []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("TestQueue was incorrect, got error: %v", err)
+	}
+
+	clock := newTestClock()
+	q, err := NewQueue(New(WithClock(clock)), store)
+	if err != nil {
+		t.Fatalf("TestQueue was incorrect, got error: %v", err)
+	}
+	defer q.Close()
+
+	if _, err := q.Enqueue("POST", ts.URL, nil, []byte(`{"reindex": true}`)); err != nil {
+		t.Fatalf("TestQueue was incorrect, got error: %v", err)
+	}
+	waitQueue(t, q)
+
+	if len(bodies) != 3 || bodies[2] != `{"reindex": true}` {
+		t.Errorf("TestQueue was incorrect, got bodies: %q, want: 3 attempts with the body.", bodies)
+	}
+	sleeps := clock.Sleeps()
+	if len(sleeps) != 2 || sleeps[0] != 500*time.Millisecond || sleeps[1] != 1500*time.Millisecond {
+		t.Errorf("TestQueue was incorrect, got waits: %v, want: [500ms 1.5s].", sleeps)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Errorf("TestQueue was incorrect, got files: %v, want: none.", files)
+	}
+}
+
+// TestQueueRestart should test that the requests left by a previous process are delivered,
+// and the permanent failures dropped to the dead letter callback.
+func TestQueueRestart(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, _ := NewFileStore(dir)
+	store.Save(QueuedRequest{ID: "left", Method: "GET", URL: ts.URL, Attempts: 4})
+	store.Save(QueuedRequest{ID: "gone", Method: "GET", URL: ts.URL + "/gone"})
+
+	dropped := make(chan QueuedRequest, 1)
+	q, err := NewQueue(New(WithClock(newTestClock())), store, WithQueueDeadLetter(func(r QueuedRequest, _ error) {
+		dropped <- r
+	}))
+	if err != nil {
+		t.Fatalf("TestQueueRestart was incorrect, got error: %v", err)
+	}
+	defer q.Close()
+	waitQueue(t, q)
+
+	select {
+	case r := <-dropped:
+		if r.ID != "gone" || r.Attempts != 1 {
+			t.Errorf("TestQueueRestart was incorrect, got dropped: %+v, want: the gone request.", r)
+		}
+	default:
+		t.Errorf("TestQueueRestart was incorrect, got no request dropped.")
+	}
+	if left, _ := store.Load(); len(left) != 0 {
+		t.Errorf("TestQueueRestart was incorrect, got: %+v left in the store, want: none.", left)
+	}
+}
+
+// TestQueueDeadLetterEnqueue should test that the dead letter callback can use the Queue,
+// and that the Queue stops when its Client is closed.
+func TestQueueDeadLetterEnqueue(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/bad" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "queue")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	store, _ := NewFileStore(dir)
+
+	c := New(WithClock(newTestClock()))
+	var q *Queue
+	requeued := make(chan string, 1)
+	q, err = NewQueue(c, store, WithQueueDeadLetter(func(r QueuedRequest, _ error) {
+		id, err := q.Enqueue(r.Method, ts.URL+"/fixed", r.Headers, r.Body)
+		if err != nil {
+			t.Errorf("TestQueueDeadLetterEnqueue was incorrect, got error: %v", err)
+		}
+		q.Len()
+		requeued <- id
+	}))
+	if err != nil {
+		t.Fatalf("TestQueueDeadLetterEnqueue was incorrect, got error: %v", err)
+	}
+
+	if _, err := q.Enqueue("POST", ts.URL+"/bad", nil, nil); err != nil {
+		t.Fatalf("TestQueueDeadLetterEnqueue was incorrect, got error: %v", err)
+	}
+	select {
+	case <-requeued:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("TestQueueDeadLetterEnqueue was incorrect, got: the dead letter callback blocked, want: the request enqueued again.")
+	}
+	waitQueue(t, q)
+
+	c.Close()
+	select {
+	case <-q.done:
+	case <-time.After(5 * time.Second):
+		t.Errorf("TestQueueDeadLetterEnqueue was incorrect, got: the Queue running, want: stopped with its Client.")
+	}
+}
@@ -0,0 +1,167 @@
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The headers of the IETF draft "RateLimit header fields for HTTP".
+const (
+	headerDraftLimit     = "RateLimit-Limit"
+	headerDraftRemaining = "RateLimit-Remaining"
+	headerDraftReset     = "RateLimit-Reset"
+	headerDraftRateLimit = "RateLimit"
+)
+
+// unixResetThreshold is the smallest RateLimit-Reset taken as a Unix time
+// rather than as seconds: about 2001, or 31 years of seconds.
+const unixResetThreshold = 1000000000
+
+// QuotaParser extracts the quota state from a response of an API, for the
+// header schemes other than the X-RateLimit-* ones of GitHub, see
+// WithQuotaParser. It reports false if resp carries no quota.
+type QuotaParser interface {
+	ParseQuota(resp *http.Response, now time.Time) (RateLimitInfo, bool)
+}
+
+// QuotaParserFunc is a function used as a QuotaParser.
+type QuotaParserFunc func(resp *http.Response, now time.Time) (RateLimitInfo, bool)
+
+// ParseQuota calls f(resp, now).
+func (f QuotaParserFunc) ParseQuota(resp *http.Response, now time.Time) (RateLimitInfo, bool) {
+	return f(resp, now)
+}
+
+// HeaderQuota is a QuotaParser reading the quota from the headers it names.
+// A response carries a quota if it has the Remaining header.
+type HeaderQuota struct {
+	Limit     string
+	Remaining string
+	Reset     string
+	// ResetSeconds tells that the Reset header holds the seconds until the
+	// reset, rather than its Unix time.
+	ResetSeconds bool
+}
+
+// GitLabQuota reads the RateLimit-* headers of GitLab.
+var GitLabQuota = HeaderQuota{
+	Limit:     "RateLimit-Limit",
+	Remaining: "RateLimit-Remaining",
+	Reset:     "RateLimit-Reset",
+}
+
+// ParseQuota returns the quota of the headers of resp.
+func (q HeaderQuota) ParseQuota(resp *http.Response, now time.Time) (RateLimitInfo, bool) {
+	remaining, err := strconv.Atoi(resp.Header.Get(q.Remaining))
+	if err != nil {
+		return RateLimitInfo{}, false
+	}
+
+	info := RateLimitInfo{Remaining: remaining}
+	if q.Limit != "" {
+		info.Limit, _ = strconv.Atoi(resp.Header.Get(q.Limit))
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get(q.Reset), 10, 64); err == nil {
+		if q.ResetSeconds {
+			info.Reset = now.Add(time.Duration(reset) * time.Second)
+		} else {
+			info.Reset = time.Unix(reset, 0)
+		}
+	}
+
+	return info, true
+}
+
+// DraftQuota reads the quota of the IETF draft "RateLimit header fields for
+// HTTP", sent by many API gateways: the RateLimit-Limit, RateLimit-Remaining
+// and RateLimit-Reset headers, the reset in seconds, or the single RateLimit
+// header like "limit=100, remaining=50, reset=30". The Client reads it
+// after the parsers of WithQuotaParser. A reset too large to be seconds is
+// taken as the Unix time sent by GitLab.
+var DraftQuota QuotaParser = QuotaParserFunc(parseDraftQuota)
+
+// parseDraftQuota returns the quota of the RateLimit headers of resp.
+func parseDraftQuota(resp *http.Response, now time.Time) (RateLimitInfo, bool) {
+	limit := resp.Header.Get(headerDraftLimit)
+	remaining := resp.Header.Get(headerDraftRemaining)
+	reset := resp.Header.Get(headerDraftReset)
+	if remaining == "" {
+		params := draftParams(resp.Header.Get(headerDraftRateLimit))
+		limit, remaining, reset = params["limit"], params["remaining"], params["reset"]
+	}
+
+	left, err := strconv.Atoi(draftValue(remaining))
+	if err != nil {
+		return RateLimitInfo{}, false
+	}
+
+	info := RateLimitInfo{Remaining: left}
+	info.Limit, _ = strconv.Atoi(draftValue(limit))
+	if seconds, err := strconv.ParseInt(draftValue(reset), 10, 64); err == nil {
+		if seconds >= unixResetThreshold {
+			info.Reset = time.Unix(seconds, 0)
+		} else {
+			info.Reset = now.Add(time.Duration(seconds) * time.Second)
+		}
+	}
+
+	return info, true
+}
+
+// draftValue returns the first item of a RateLimit header value, like 100
+// of "100, 100;w=60", without its parameters.
+func draftValue(v string) string {
+	if i := strings.IndexAny(v, ",;"); i >= 0 {
+		v = v[:i]
+	}
+
+	return strings.TrimSpace(v)
+}
+
+// draftParams returns the lowercased keys and the values of a RateLimit
+// header like "limit=100, remaining=50, reset=30".
+func draftParams(v string) map[string]string {
+	params := make(map[string]string)
+	for _, item := range strings.Split(v, ",") {
+		if i := strings.IndexByte(item, '='); i >= 0 {
+			params[strings.ToLower(strings.TrimSpace(item[:i]))] = strings.TrimSpace(item[i+1:])
+		}
+	}
+
+	return params
+}
+
+// WithQuotaParser adds the parsers extracting the quota from the responses,
+// tried in order before DraftQuota and the X-RateLimit-* headers. Their quota
+// is reported by RateLimit, and an exhausted quota makes the 429 and 403
+// responses wait until its reset, as the X-RateLimit-Reset of GitHub does.
+func WithQuotaParser(parsers ...QuotaParser) Option {
+	return func(c *Client) {
+		c.quotaParsers = append(c.quotaParsers, parsers...)
+	}
+}
+
+// parseQuota returns the quota of resp found by the first parser of the
+// Client reporting one, else by DraftQuota.
+func (c *Client) parseQuota(resp *http.Response) (RateLimitInfo, bool) {
+	for _, p := range c.quotaParsers {
+		if info, ok := p.ParseQuota(resp, c.clock.Now()); ok {
+			return info, true
+		}
+	}
+
+	return DraftQuota.ParseQuota(resp, c.clock.Now())
+}
+
+// quotaWait returns the wait until the reset of the quota of resp, found by
+// the parsers of the Client, zero if the quota isn't exhausted.
+func (c *Client) quotaWait(resp *http.Response) time.Duration {
+	info, ok := c.parseQuota(resp)
+	if !ok || info.Remaining > 0 || info.Reset.IsZero() {
+		return 0
+	}
+
+	return info.Reset.Sub(c.clock.Now())
+}
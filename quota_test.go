@@ -0,0 +1,117 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestWithQuotaParser should test that an exhausted quota of a custom scheme is waited for.
+func TestWithQuotaParser(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		w.Header().Set("X-Quota-Limit", "100")
+		if hits == 1 {
+			w.Header().Set("X-Quota-Left", "0")
+			w.Header().Set("X-Quota-Reset-In", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-Quota-Left", "99")
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+
+	clock := newTestClock()
+	c := New(WithClock(clock), WithQuotaParser(HeaderQuota{
+		Limit:        "X-Quota-Limit",
+		Remaining:    "X-Quota-Left",
+		Reset:        "X-Quota-Reset-In",
+		ResetSeconds: true,
+	}))
+
+	if _, err := c.GetURL(ts.URL, nil); err != nil || hits != 2 {
+		t.Fatalf("TestWithQuotaParser was incorrect, got: %d hits, %v, want: 2 hits, no error.", hits, err)
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 1 || sleeps[0] != 30*time.Second {
+		t.Errorf("TestWithQuotaParser was incorrect, got sleeps: %v, want: [30s].", sleeps)
+	}
+	if info, ok := c.RateLimit(u.Host); !ok || info.Limit != 100 || info.Remaining != 99 {
+		t.Errorf("TestWithQuotaParser was incorrect, got rate limit: %+v, %v, want: 99 of 100.", info, ok)
+	}
+}
+
+// TestGitLabQuota should test that the RateLimit-* headers of GitLab are parsed.
+func TestGitLabQuota(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("RateLimit-Limit", "600")
+	resp.Header.Set("RateLimit-Remaining", "0")
+	resp.Header.Set("RateLimit-Reset", strconv.FormatInt(now.Unix()+60, 10))
+
+	info, ok := GitLabQuota.ParseQuota(resp, now)
+	if !ok || info.Limit != 600 || info.Remaining != 0 || !info.Reset.Equal(now.Add(time.Minute)) {
+		t.Errorf("TestGitLabQuota was incorrect, got: %+v, %v, want: 0 of 600, reset in 1m.", info, ok)
+	}
+
+	if _, ok := GitLabQuota.ParseQuota(&http.Response{Header: http.Header{}}, now); ok {
+		t.Errorf("TestGitLabQuota was incorrect, got a quota from no headers.")
+	}
+}
+
+// TestDraftQuota should test that the RateLimit headers of the IETF draft are parsed.
+func TestDraftQuota(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	tests := []struct {
+		header http.Header
+		want   RateLimitInfo
+	}{
+		{http.Header{"Ratelimit-Limit": {"100, 100;w=60"}, "Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"30"}}, RateLimitInfo{Limit: 100, Reset: now.Add(30 * time.Second)}},
+		{http.Header{"Ratelimit": {"limit=10, remaining=4, reset=5"}}, RateLimitInfo{Limit: 10, Remaining: 4, Reset: now.Add(5 * time.Second)}},
+		{http.Header{"Ratelimit-Remaining": {"7"}, "Ratelimit-Reset": {"1600000060"}}, RateLimitInfo{Remaining: 7, Reset: now.Add(time.Minute)}},
+	}
+	for _, test := range tests {
+		info, ok := DraftQuota.ParseQuota(&http.Response{Header: test.header}, now)
+		if !ok || info.Limit != test.want.Limit || info.Remaining != test.want.Remaining || !info.Reset.Equal(test.want.Reset) {
+			t.Errorf("TestDraftQuota was incorrect, got: %+v, %v, want: %+v.", info, ok, test.want)
+		}
+	}
+	if _, ok := DraftQuota.ParseQuota(&http.Response{Header: http.Header{}}, now); ok {
+		t.Errorf("TestDraftQuota was incorrect, got: a quota, want: none without the headers.")
+	}
+}
+
+// TestDraftQuotaWait should test that an exhausted draft quota is waited for by default.
+func TestDraftQuotaWait(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		w.Header().Set("RateLimit-Limit", "50")
+		if hits == 1 {
+			w.Header().Set("RateLimit-Remaining", "0")
+			w.Header().Set("RateLimit-Reset", "12")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("RateLimit-Remaining", "49")
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+
+	clock := newTestClock()
+	c := New(WithClock(clock))
+
+	if _, err := c.GetURL(ts.URL, nil); err != nil || hits != 2 {
+		t.Fatalf("TestDraftQuotaWait was incorrect, got: %d hits, %v, want: 2 hits, no error.", hits, err)
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 1 || sleeps[0] != 12*time.Second {
+		t.Errorf("TestDraftQuotaWait was incorrect, got sleeps: %v, want: [12s].", sleeps)
+	}
+	if info, ok := c.RateLimit(u.Host); !ok || info.Limit != 50 || info.Remaining != 49 {
+		t.Errorf("TestDraftQuotaWait was incorrect, got rate limit: %+v, %v, want: 49 of 50.", info, ok)
+	}
+}
@@ -0,0 +1,91 @@
+package httpclient
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// rateLimitEntry is the last rate limit state of a host.
+type rateLimitEntry struct {
+	info RateLimitInfo
+	// last is the time it was reported.
+	last time.Time
+}
+
+// RateLimit returns the last rate limit state reported by host, e.g.
+// "api.github.com", through the parsers of WithQuotaParser, the RateLimit
+// headers of DraftQuota, the X-RateLimit-* headers or, on 429 and 403
+// responses without them, the Retry-After header. It reports false if host
+// never reported one. As for Stats, the states not reported for an hour are
+// dropped once there are more than 1024 hosts, and the least recently
+// reported ones once there are 8192.
+func (c *Client) RateLimit(host string) (RateLimitInfo, bool) {
+	c.rateLimitsMu.Lock()
+	defer c.rateLimitsMu.Unlock()
+
+	e, ok := c.rateLimits[host]
+
+	return e.info, ok
+}
+
+// recordRateLimit records the rate limit state reported by resp of host.
+func (c *Client) recordRateLimit(host string, resp *http.Response) {
+	info, ok := c.parseQuota(resp)
+	switch {
+	case ok:
+	case resp.Header.Get(headerRateLimit) != "" || resp.Header.Get(headerRateRemaining) != "":
+		info = ParseRateLimit(resp.Header)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusForbidden:
+		seconds, err := strconv.Atoi(resp.Header.Get(headerRetryAfter))
+		if err != nil {
+			return
+		}
+		info.Reset = c.clock.Now().Add(time.Duration(seconds) * time.Second)
+	default:
+		return
+	}
+
+	if c.metrics != nil {
+		c.metrics.SetGauge(MetricRateLimitRemaining, float64(info.Remaining), map[string]string{"host": host})
+	}
+
+	c.rateLimitsMu.Lock()
+	defer c.rateLimitsMu.Unlock()
+
+	now := c.clock.Now()
+	if c.rateLimits == nil {
+		c.rateLimits = make(map[string]rateLimitEntry)
+	}
+	if _, ok := c.rateLimits[host]; !ok {
+		if n := len(c.rateLimits); n >= maxStatsHosts || (n >= statsSweepSize && now.Sub(c.rateLimitsSwept) >= statsSweepInterval) {
+			c.sweepRateLimits(now)
+		}
+	}
+	c.rateLimits[host] = rateLimitEntry{info: info, last: now}
+}
+
+// sweepRateLimits drops the rate limits not reported for statsTTL, then the
+// least recently reported quarter if there are still maxStatsHosts, as
+// stats.sweep. c.rateLimitsMu must be held.
+func (c *Client) sweepRateLimits(now time.Time) {
+	c.rateLimitsSwept = now
+	for h, e := range c.rateLimits {
+		if now.Sub(e.last) >= statsTTL {
+			delete(c.rateLimits, h)
+		}
+	}
+	if len(c.rateLimits) < maxStatsHosts {
+		return
+	}
+
+	hosts := make([]string, 0, len(c.rateLimits))
+	for h := range c.rateLimits {
+		hosts = append(hosts, h)
+	}
+	sort.Slice(hosts, func(i, j int) bool { return c.rateLimits[hosts[i]].last.Before(c.rateLimits[hosts[j]].last) })
+	for _, h := range hosts[:len(hosts)/4] {
+		delete(c.rateLimits, h)
+	}
+}
@@ -0,0 +1,97 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestRateLimit should test that the last rate limit state of each host is kept.
+func TestRateLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/limited" {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		w.Header().Set("X-RateLimit-Reset", "1600003600")
+	}))
+	defer ts.Close()
+	u, _ := url.Parse(ts.URL)
+
+	clock := newTestClock()
+	c := New(WithClock(clock))
+	if _, ok := c.RateLimit(u.Host); ok {
+		t.Errorf("TestRateLimit was incorrect, got a state before any request.")
+	}
+
+	if _, err := c.GetURL(ts.URL, nil); err != nil {
+		t.Fatalf("TestRateLimit was incorrect, got error: %v", err)
+	}
+	info, ok := c.RateLimit(u.Host)
+	if !ok || info.Limit != 5000 || info.Remaining != 4999 || !info.Reset.Equal(time.Unix(1600003600, 0)) {
+		t.Errorf("TestRateLimit was incorrect, got: %+v, want: 4999/5000 until 1600003600.", info)
+	}
+
+	c.GetURL(ts.URL+"/limited", nil)
+	if info, _ := c.RateLimit(u.Host); info.Remaining != 0 || info.Reset.IsZero() {
+		t.Errorf("TestRateLimit was incorrect, got: %+v, want: none remaining.", info)
+	}
+}
+
+// TestSecondaryRateLimit should test that the GitHub secondary rate limits are waited for, unlike the other 403.
+func TestSecondaryRateLimit(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/private" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "Resource not accessible by integration"}`))
+			return
+		}
+		if hits++; hits == 1 {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"message": "You have exceeded a secondary rate limit. Please wait a few minutes before you try again."}`))
+		}
+	}))
+	defer ts.Close()
+
+	var waits []time.Duration
+	clock := newTestClock()
+	c := New(WithClock(clock), WithSecondaryRateLimitHandler(func(_ string, wait time.Duration) {
+		waits = append(waits, wait)
+	}))
+	if _, err := c.GetURL(ts.URL, nil); err != nil {
+		t.Fatalf("TestSecondaryRateLimit was incorrect, got error: %v", err)
+	}
+	if c.SecondaryRateLimits() != 1 || len(waits) != 1 || waits[0] != time.Minute {
+		t.Errorf("TestSecondaryRateLimit was incorrect, got: %d hits, waits %v, want: 1 hit, a minute wait.", c.SecondaryRateLimits(), waits)
+	}
+
+	if _, err := c.GetURL(ts.URL+"/private", nil); !errors.Is(err, ErrForbidden) || c.SecondaryRateLimits() != 1 {
+		t.Errorf("TestSecondaryRateLimit was incorrect, got: %v, want: %v.", err, ErrForbidden)
+	}
+}
+
+// TestRateLimitSweep should test that the rate limits not reported for statsTTL are
+// dropped once there are statsSweepSize hosts.
+func TestRateLimitSweep(t *testing.T) {
+	clock := newTestClock()
+	c := New(WithClock(clock))
+	resp := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	resp.Header.Set(headerRateRemaining, "10")
+
+	for i := 0; i < statsSweepSize; i++ {
+		c.recordRateLimit("idle"+strconv.Itoa(i), resp)
+	}
+	<-clock.After(statsTTL)
+	c.recordRateLimit("new", resp)
+	if _, ok := c.RateLimit("idle0"); ok || len(c.rateLimits) != 1 {
+		t.Errorf("TestRateLimitSweep was incorrect, got: %d hosts, want: 1 after sweeping the idle ones.", len(c.rateLimits))
+	}
+}
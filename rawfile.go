@@ -0,0 +1,114 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrRawFileNotFound is returned by GetRawFile when no candidate raw URL has the file.
+var ErrRawFileNotFound = errors.New("raw file not found")
+
+// RawFileURLs returns the candidate raw URLs of file at ref in the repository
+// at repoURL, most likely first. GitHub and Bitbucket have a single pattern,
+// while other hosts are assumed to be GitLab instances, old and new routes,
+// with the scheme of repoURL, plain HTTP included. An empty ref means the
+// default branch (HEAD).
+func RawFileURLs(repoURL, ref, file string) ([]string, error) {
+	scheme, host, project, err := parseRepoURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref == "" {
+		ref = "HEAD"
+	}
+	// The refs like "feature/x" keep their slashes, as the paths of the files.
+	path := escapeSegments(ref) + "/" + escapeSegments(strings.Trim(file, "/"))
+
+	switch host {
+	case "github.com":
+		return []string{"https://raw.githubusercontent.com/" + project + "/" + path}, nil
+	case "bitbucket.org":
+		return []string{"https://bitbucket.org/" + project + "/raw/" + path}, nil
+	default:
+		return []string{
+			scheme + "://" + host + "/" + project + "/-/raw/" + path,
+			scheme + "://" + host + "/" + project + "/raw/" + path,
+		}, nil
+	}
+}
+
+// escapeSegments escapes each segment of the slash separated path.
+func escapeSegments(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// GetRawFile retrieves file at ref in the repository at repoURL, trying the
+// candidate raw URLs returned by RawFileURLs in order. It returns the response
+// with the raw URL having the file, or ErrRawFileNotFound.
+func GetRawFile(repoURL, ref, file string, headers map[string]string) (HTTPResponse, string, error) {
+	return defaultClient.GetRawFile(repoURL, ref, file, headers)
+}
+
+// GetRawFile retrieves file at ref in the repository at repoURL, trying the
+// candidate raw URLs returned by RawFileURLs in order. It returns the response
+// with the raw URL having the file, or ErrRawFileNotFound.
+func (c *Client) GetRawFile(repoURL, ref, file string, headers map[string]string) (HTTPResponse, string, error) {
+	return GetRawFileWith(c, repoURL, ref, file, headers)
+}
+
+// GetRawFileWith is GetRawFile sending the requests through d, e.g. a fake of
+// the tests.
+func GetRawFileWith(d Doer, repoURL, ref, file string, headers map[string]string) (HTTPResponse, string, error) {
+	candidates, err := RawFileURLs(repoURL, ref, file)
+	if err != nil {
+		return HTTPResponse{}, "", err
+	}
+
+	var resp HTTPResponse
+	for _, rawURL := range candidates {
+		resp, err = d.Request(rawURL, "GET", headers, nil)
+		if err == nil && resp.Status.Code >= 200 && resp.Status.Code <= 299 {
+			return resp, rawURL, nil
+		}
+		if resp.Status.Code != http.StatusNotFound {
+			return resp, rawURL, err
+		}
+	}
+
+	return resp, "", fmt.Errorf("%w: %s", ErrRawFileNotFound, file)
+}
+
+// parseRepoURL returns the scheme of the raw URLs, host and project path of
+// a repository URL, in the http, https or the scp-like ssh form
+// (git@host:owner/repo.git), the latter served over https.
+func parseRepoURL(repoURL string) (scheme, host, project string, err error) {
+	if strings.HasPrefix(repoURL, "git@") && !strings.Contains(repoURL, "://") {
+		repoURL = "https://" + strings.Replace(strings.TrimPrefix(repoURL, "git@"), ":", "/", 1)
+	}
+
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", "", &URLError{URL: repoURL, Reason: err.Error()}
+	}
+
+	scheme = "https"
+	if strings.EqualFold(u.Scheme, "http") {
+		scheme = "http"
+	}
+	host = strings.TrimPrefix(strings.ToLower(u.Host), "www.")
+	project = strings.TrimSuffix(strings.Trim(u.Path, "/"), ".git")
+	if host == "" || strings.Count(project, "/") < 1 {
+		return "", "", "", &URLError{URL: repoURL, Reason: "not a repository URL"}
+	}
+
+	return scheme, host, project, nil
+}
@@ -0,0 +1,69 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestRawFileURLs should test the raw URL patterns of the forges.
+func TestRawFileURLs(t *testing.T) {
+	tests := []struct {
+		repo, ref, file string
+		want            []string
+	}{
+		{"https://github.com/italia/publiccode.yml.git", "", "publiccode.yml", []string{"https://raw.githubusercontent.com/italia/publiccode.yml/HEAD/publiccode.yml"}},
+		{"git@github.com:italia/developers-italia-backend.git", "main", "/docs/a b.md", []string{"https://raw.githubusercontent.com/italia/developers-italia-backend/main/docs/a%20b.md"}},
+		{"https://bitbucket.org/owner/repo/", "v1", "publiccode.yml", []string{"https://bitbucket.org/owner/repo/raw/v1/publiccode.yml"}},
+		{"https://github.com/italia/publiccode.yml", "feature/a b", "publiccode.yml", []string{"https://raw.githubusercontent.com/italia/publiccode.yml/feature/a%20b/publiccode.yml"}},
+		{"http://git.comune.example.it:8080/group/repo.git", "", "publiccode.yml", []string{
+			"http://git.comune.example.it:8080/group/repo/-/raw/HEAD/publiccode.yml",
+			"http://git.comune.example.it:8080/group/repo/raw/HEAD/publiccode.yml",
+		}},
+		{"https://gitlab.comune.example.it/group/sub/repo", "master", "publiccode.yml", []string{
+			"https://gitlab.comune.example.it/group/sub/repo/-/raw/master/publiccode.yml",
+			"https://gitlab.comune.example.it/group/sub/repo/raw/master/publiccode.yml",
+		}},
+	}
+
+	for _, test := range tests {
+		got, err := RawFileURLs(test.repo, test.ref, test.file)
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("TestRawFileURLs was incorrect for %s, got: %v (%v), want: %v.", test.repo, got, err, test.want)
+		}
+	}
+
+	if _, err := RawFileURLs("https://github.com/", "", "publiccode.yml"); err == nil {
+		t.Errorf("TestRawFileURLs was incorrect, got no error for a non repository URL")
+	}
+}
+
+// TestGetRawFile should test the fallback to the older GitLab route.
+func TestGetRawFile(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "/-/raw/") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, "name: Medusa\n")
+	}))
+	defer ts.Close()
+
+	u, _ := url.Parse(ts.URL)
+	c := New(WithTransport(ts.Client().Transport))
+
+	resp, rawURL, err := c.GetRawFile("https://"+u.Host+"/group/repo", "", "publiccode.yml", nil)
+	if err != nil || string(resp.Body) != "name: Medusa\n" || rawURL != "https://"+u.Host+"/group/repo/raw/HEAD/publiccode.yml" {
+		t.Errorf("TestGetRawFile was incorrect, got: %s from %s (%v)", resp.Body, rawURL, err)
+	}
+
+	ts.Config.Handler = http.NotFoundHandler()
+	if _, _, err := c.GetRawFile("https://"+u.Host+"/group/repo", "", "publiccode.yml", nil); !errors.Is(err, ErrRawFileNotFound) {
+		t.Errorf("TestGetRawFile was incorrect, got error: %v", err)
+	}
+}
@@ -0,0 +1,290 @@
+package httpclient
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// robotsTTL is the time robots.txt files are cached for.
+const robotsTTL = 24 * time.Hour
+
+// robotsFailureTTL is the time the failures to fetch a robots.txt are cached
+// for, disallowing the host.
+const robotsFailureTTL = 5 * time.Minute
+
+// robotsTimeout bounds the fetch of a robots.txt, which doesn't depend on the
+// request waiting for it.
+const robotsTimeout = 30 * time.Second
+
+// maxRobotsSize is the maximum size of the robots.txt files read (RFC 9309 asks for at least 500 KiB).
+const maxRobotsSize = 512 << 10
+
+// disallowAll are the rules of the hosts whose robots.txt can't be fetched.
+var disallowAll = robotsRules{disallow: []robotsPattern{newRobotsPattern("/")}}
+
+// ErrDisallowedByRobots is returned for the requests disallowed by the robots.txt of the host.
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// robots enforces the robots.txt rules of the hosts for a user agent.
+type robots struct {
+	userAgent string
+
+	mu    sync.Mutex
+	hosts map[string]*robotsHost
+}
+
+// robotsHost is the robots.txt state of a host. ready is closed once rules is fetched.
+type robotsHost struct {
+	ready   chan struct{}
+	rules   robotsRules
+	expires time.Time
+
+	mu          sync.Mutex
+	lastRequest time.Time
+}
+
+// robotsRules are the rules of the robots.txt group applying to the user agent.
+type robotsRules struct {
+	allow      []robotsPattern
+	disallow   []robotsPattern
+	crawlDelay time.Duration
+}
+
+// robotsPattern is an Allow or Disallow path pattern, where "*" matches any
+// sequence of characters and a trailing "$" anchors the end of the path.
+type robotsPattern struct {
+	raw string
+	re  *regexp.Regexp
+}
+
+func newRobotsPattern(raw string) robotsPattern {
+	anchored := strings.HasSuffix(raw, "$")
+	parts := strings.Split(strings.TrimSuffix(raw, "$"), "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if anchored {
+		expr += "$"
+	}
+
+	return robotsPattern{raw: raw, re: regexp.MustCompile(expr)}
+}
+
+// WithRobots makes the Client honor the robots.txt of each host for userAgent:
+// disallowed requests fail with ErrDisallowedByRobots without being sent, and
+// requests to the same host are spaced by the Crawl-delay.
+// robots.txt files are cached for 24 hours, the failures to fetch them, which
+// disallow the host, for 5 minutes.
+func WithRobots(userAgent string) Option {
+	return func(c *Client) {
+		c.robots = &robots{
+			userAgent: userAgent,
+			hosts:     make(map[string]*robotsHost),
+		}
+	}
+}
+
+// checkRobots returns ErrDisallowedByRobots if u is disallowed, otherwise it waits
+// for the crawl delay of the host.
+func (c *Client) checkRobots(ctx context.Context, u *url.URL) error {
+	host, err := c.robots.host(ctx, c, u)
+	if err != nil {
+		return err
+	}
+
+	if !host.rules.allowed(u.RequestURI()) {
+		return ErrDisallowedByRobots
+	}
+	if host.rules.crawlDelay <= 0 {
+		return nil
+	}
+
+	// Reserve the next slot, then wait for it.
+	host.mu.Lock()
+	now := c.clock.Now()
+	next := host.lastRequest.Add(host.rules.crawlDelay)
+	if next.Before(now) {
+		next = now
+	}
+	host.lastRequest = next
+	host.mu.Unlock()
+
+	return c.sleepContext(ctx, next.Sub(now))
+}
+
+// host returns the robots.txt state of the host of u, fetching it if needed,
+// or ctx.Err() if ctx is done while waiting for it.
+func (r *robots) host(ctx context.Context, c *Client, u *url.URL) (*robotsHost, error) {
+	key := u.Scheme + "://" + u.Host
+
+	r.mu.Lock()
+	host, ok := r.hosts[key]
+	if ok && !c.clock.Now().Before(host.expires) {
+		select {
+		case <-host.ready:
+			ok = false
+		default:
+		}
+	}
+	if !ok {
+		host = &robotsHost{ready: make(chan struct{})}
+		r.hosts[key] = host
+		r.mu.Unlock()
+
+		// The fetch is shared by the requests to the host, so that it isn't
+		// bound to the context of the first one.
+		fetchCtx, cancel := context.WithTimeout(context.Background(), robotsTimeout)
+		rules, ttl := r.fetch(fetchCtx, c, key+"/robots.txt")
+		cancel()
+		host.rules = rules
+		host.expires = c.clock.Now().Add(ttl)
+		close(host.ready)
+
+		return host, nil
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-host.ready:
+		return host, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetch retrieves and parses the robots.txt at URL, returning its rules and
+// the time to cache them for. As by RFC 9309, a missing file allows everything
+// while a server error disallows everything, for robotsFailureTTL only.
+func (r *robots) fetch(ctx context.Context, c *Client, URL string) (robotsRules, time.Duration) {
+	req, err := http.NewRequestWithContext(ctx, "GET", URL, nil)
+	if err != nil {
+		return robotsRules{}, robotsTTL
+	}
+	req.Header.Set("User-Agent", r.userAgent)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.Warnf("Can't fetch %s, disallowing the host: %v", URL, err)
+		return disallowAll, robotsFailureTTL
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		log.Warnf("Status: %s - Resource: %s, disallowing the host", resp.Status, URL)
+		return disallowAll, robotsFailureTTL
+	case resp.StatusCode >= 400:
+		return robotsRules{}, robotsTTL
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxRobotsSize))
+	if err != nil {
+		return disallowAll, robotsFailureTTL
+	}
+
+	return parseRobots(body, r.userAgent), robotsTTL
+}
+
+// parseRobots returns the rules of the group of body applying to userAgent,
+// falling back to the "*" group.
+func parseRobots(body []byte, userAgent string) robotsRules {
+	token := strings.ToLower(userAgent)
+	if i := strings.IndexAny(token, "/ "); i >= 0 {
+		token = token[:i]
+	}
+
+	var specific, wildcard robotsRules
+	var matchSpecific, matchWildcard, foundSpecific bool
+	inAgents := false
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		sep := strings.IndexByte(line, ':')
+		if sep < 0 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:sep]))
+		value := strings.TrimSpace(line[sep+1:])
+
+		if key == "user-agent" {
+			// Consecutive user-agent lines open the same group.
+			if !inAgents {
+				matchSpecific, matchWildcard = false, false
+			}
+			inAgents = true
+			agent := strings.ToLower(value)
+			if agent == "*" {
+				matchWildcard = true
+			} else if token != "" && strings.Contains(agent, token) {
+				matchSpecific, foundSpecific = true, true
+			}
+			continue
+		}
+		inAgents = false
+
+		if matchSpecific {
+			specific.add(key, value)
+		}
+		if matchWildcard {
+			wildcard.add(key, value)
+		}
+	}
+
+	if foundSpecific {
+		return specific
+	}
+
+	return wildcard
+}
+
+// add adds to r the rule of a robots.txt line.
+func (r *robotsRules) add(key, value string) {
+	switch key {
+	case "allow":
+		if value != "" {
+			r.allow = append(r.allow, newRobotsPattern(value))
+		}
+	case "disallow":
+		if value != "" {
+			r.disallow = append(r.disallow, newRobotsPattern(value))
+		}
+	case "crawl-delay":
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+			r.crawlDelay = time.Duration(seconds * float64(time.Second))
+		}
+	}
+}
+
+// allowed reports whether path is allowed: the longest matching rule wins,
+// Allow winning over Disallow on ties.
+func (r robotsRules) allowed(path string) bool {
+	longest := func(patterns []robotsPattern) int {
+		max := -1
+		for _, p := range patterns {
+			if len(p.raw) > max && p.re.MatchString(path) {
+				max = len(p.raw)
+			}
+		}
+		return max
+	}
+
+	return longest(r.allow) >= longest(r.disallow)
+}
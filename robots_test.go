@@ -0,0 +1,132 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+const robotsTxt = `# Example
+User-agent: otherbot
+Disallow: /
+
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.php$
+Crawl-delay: 2
+`
+
+// TestRobotsRules should test the matching of the rules of a robots.txt.
+func TestRobotsRules(t *testing.T) {
+	rules := parseRobots([]byte(robotsTxt), "developers-italia-crawler/1.0")
+
+	for path, want := range map[string]bool{
+		"/":                    true,
+		"/private":             false,
+		"/private/x":           false,
+		"/private/public/x":    true,
+		"/index.php":           false,
+		"/index.php.php":       false,
+		"/index.php?x=1":       true,
+		"/publiccode.yml":      true,
+		"/dir/publiccode.yml":  true,
+		"/dir/a.php/index.htm": true,
+	} {
+		if got := rules.allowed(path); got != want {
+			t.Errorf("TestRobotsRules was incorrect for %s, got: %v, want: %v.", path, got, want)
+		}
+	}
+	if rules.crawlDelay != 2*time.Second {
+		t.Errorf("TestRobotsRules was incorrect, got crawl delay: %v, want: %v.", rules.crawlDelay, 2*time.Second)
+	}
+
+	if parseRobots([]byte(robotsTxt), "otherbot").allowed("/publiccode.yml") {
+		t.Errorf("TestRobotsRules was incorrect, the specific group was not applied")
+	}
+}
+
+// TestWithRobots should test that disallowed requests are not sent and the crawl delay is applied.
+func TestWithRobots(t *testing.T) {
+	hits := map[string]int{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.URL.Path]++
+		if r.URL.Path == "/robots.txt" {
+			fmt.Fprint(w, robotsTxt)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	c := New(WithRobots("developers-italia-crawler"), WithClock(clock))
+
+	if _, err := c.GetURL(ts.URL+"/private/x", nil); !errors.Is(err, ErrDisallowedByRobots) {
+		t.Errorf("TestWithRobots was incorrect, got error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.GetURL(ts.URL+"/private/public/x", nil); err != nil {
+			t.Errorf("TestWithRobots was incorrect, got error: %v", err)
+		}
+	}
+
+	if hits["/robots.txt"] != 1 || hits["/private/x"] != 0 || hits["/private/public/x"] != 2 {
+		t.Errorf("TestWithRobots was incorrect, got hits: %v", hits)
+	}
+	sleeps := clock.Sleeps()
+	if len(sleeps) != 2 || sleeps[1] != 2*time.Second {
+		t.Errorf("TestWithRobots was incorrect, got sleeps: %v", sleeps)
+	}
+}
+
+// TestWithRobotsFailure should test that the failures to fetch a robots.txt disallow the host
+// for a short time only, and that the fetch isn't bound to the context of the request.
+func TestWithRobotsFailure(t *testing.T) {
+	down := true
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			hits++
+			if down {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, robotsTxt)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	c := New(WithRobots("developers-italia-crawler"), WithClock(clock), WithMaxRetries(0))
+
+	if _, err := c.GetURL(ts.URL+"/x", nil); !errors.Is(err, ErrDisallowedByRobots) {
+		t.Errorf("TestWithRobotsFailure was incorrect, got: %v, want: ErrDisallowedByRobots.", err)
+	}
+	down = false
+	if _, err := c.GetURL(ts.URL+"/x", nil); !errors.Is(err, ErrDisallowedByRobots) || hits != 1 {
+		t.Errorf("TestWithRobotsFailure was incorrect, got: %v, %d fetches, want: the failure cached.", err, hits)
+	}
+
+	<-clock.After(robotsFailureTTL)
+	if _, err := c.GetURL(ts.URL+"/x", nil); err != nil || hits != 2 {
+		t.Errorf("TestWithRobotsFailure was incorrect, got: %v, %d fetches, want: the robots.txt fetched again.", err, hits)
+	}
+
+	// A request canceled while the robots.txt is fetched doesn't fail the fetch.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	u, _ := url.Parse(strings.Replace(ts.URL, "127.0.0.1", "localhost", 1) + "/x")
+	host, err := c.robots.host(ctx, c, u)
+	if err != nil || !host.rules.allowed("/x") || hits != 3 {
+		t.Errorf("TestWithRobotsFailure was incorrect, got: %v, %d fetches, want: the robots.txt of localhost allowing /x.", err, hits)
+	}
+}
@@ -0,0 +1,108 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrSchemeRedirect is returned by the requests redirected from an http or
+// https URL to a scheme registered on the Client, e.g. a crawled site
+// redirecting to a file:// URL.
+var ErrSchemeRedirect = errors.New("redirect to a custom scheme")
+
+// maxRedirects is the number of redirects followed, as by http.Client.
+const maxRedirects = 10
+
+// WithFileURLs makes the Client serve the file:// URLs from the files under
+// root, e.g. to run a crawler against local fixtures through the same code
+// path as the real requests. A missing file is a 404 (Not Found), and the
+// Content-Type, Last-Modified and the ranges are handled as by http.FileServer.
+// The paths of the URLs can't escape root, and the http and https URLs can't
+// redirect to them, failing with ErrSchemeRedirect.
+func WithFileURLs(root string) Option {
+	return func(c *Client) {
+		c.registerScheme("file", http.NewFileTransport(http.Dir(root)))
+	}
+}
+
+// SchemeHandlerFunc is a function used as the http.RoundTripper of a scheme,
+// see WithSchemeHandler.
+type SchemeHandlerFunc func(req *http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f SchemeHandlerFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// WithSchemeHandler makes the Client send the requests to the URLs of a
+// scheme other than http and https through rt, e.g. "mock" in tests, so
+// that heterogeneous URLs go through the same Client and retries. The URLs
+// are passed to rt as they are, without normalization. rt can answer with a
+// redirect, followed by the Client, e.g. from s3:// to the presigned https URL,
+// while the redirects from http or https to the scheme fail with
+// ErrSchemeRedirect.
+func WithSchemeHandler(scheme string, rt http.RoundTripper) Option {
+	return func(c *Client) {
+		c.registerScheme(scheme, rt)
+	}
+}
+
+// registerScheme makes the Client send the requests to the URLs of scheme
+// through rt.
+func (c *Client) registerScheme(scheme string, rt http.RoundTripper) {
+	if c.schemes == nil {
+		c.schemes = make(map[string]http.RoundTripper)
+	}
+	c.schemes[strings.ToLower(scheme)] = rt
+}
+
+// customScheme reports whether u has a scheme registered on the Client.
+func (c *Client) customScheme(u *url.URL) bool {
+	return c.schemes[strings.ToLower(u.Scheme)] != nil
+}
+
+// checkRedirect is the CheckRedirect of the http.Client, refusing the
+// redirects from http or https to the registered schemes.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if !c.customScheme(req.URL) {
+		return nil
+	}
+	for _, r := range via {
+		if scheme := strings.ToLower(r.URL.Scheme); scheme == "http" || scheme == "https" {
+			return fmt.Errorf("%w: %s", ErrSchemeRedirect, req.URL.Scheme)
+		}
+	}
+
+	return nil
+}
+
+// normalizeURL normalizes URL as NormalizeURL does, leaving as they are the
+// URLs of the schemes registered on the Client.
+func (c *Client) normalizeURL(URL string) (string, error) {
+	if u, err := url.Parse(URL); err == nil && c.customScheme(u) {
+		return URL, nil
+	}
+
+	return NormalizeURL(URL)
+}
+
+// schemeTransport sends the requests of the registered schemes through their
+// RoundTripper, the others through base.
+type schemeTransport struct {
+	base    http.RoundTripper
+	schemes map[string]http.RoundTripper
+}
+
+func (t *schemeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if rt := t.schemes[strings.ToLower(req.URL.Scheme)]; rt != nil {
+		return rt.RoundTrip(req)
+	}
+
+	return t.base.RoundTrip(req)
+}
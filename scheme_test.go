@@ -0,0 +1,91 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWithFileURLs should test that the file:// URLs are served from the files of the root.
+func TestWithFileURLs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "httpclient-files")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "publiccode.yml"), []byte("name: test\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(WithFileURLs(dir))
+
+	resp, err := c.GetURL("file:///publiccode.yml", nil)
+	if err != nil || string(resp.Body) != "name: test\n" || resp.Status.Code != 200 || resp.LastModified.IsZero() {
+		t.Errorf("TestWithFileURLs was incorrect, got: %d %q, %v, want: 200 with the file.", resp.Status.Code, resp.Body, err)
+	}
+
+	if _, err := c.GetURL("file:///missing.yml", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("TestWithFileURLs was incorrect, got: %v, want: %v.", err, ErrNotFound)
+	}
+
+	// A remote server can't redirect to the local files.
+	ts := httptest.NewServer(http.RedirectHandler("file:///publiccode.yml", http.StatusFound))
+	defer ts.Close()
+	if resp, err := c.GetURL(ts.URL, nil); !errors.Is(err, ErrSchemeRedirect) || string(resp.Body) == "name: test\n" {
+		t.Errorf("TestWithFileURLs was incorrect, got: %q, %v, want: %v.", resp.Body, err, ErrSchemeRedirect)
+	}
+
+	var urlErr *URLError
+	if _, err := New().GetURL("file:///publiccode.yml", nil); !errors.As(err, &urlErr) {
+		t.Errorf("TestWithFileURLs was incorrect, got: %v, want: a *URLError without the option.", err)
+	}
+}
+
+// TestWithSchemeHandler should test the requests to the URLs of a custom
+// scheme, answered directly or with a redirect.
+func TestWithSchemeHandler(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("X-Amz-Signature") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, "object "+r.URL.Path)
+	}))
+	defer ts.Close()
+
+	mock := SchemeHandlerFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Status:     "200 OK",
+			Header:     http.Header{"Content-Type": {"text/plain"}},
+			Body:       ioutil.NopCloser(strings.NewReader("mocked " + req.URL.Opaque + req.URL.Path)),
+			Request:    req,
+		}, nil
+	})
+	presign := SchemeHandlerFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusTemporaryRedirect,
+			Status:     "307 Temporary Redirect",
+			Header:     http.Header{"Location": {ts.URL + "/" + req.URL.Host + req.URL.Path + "?X-Amz-Signature=abc"}},
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	})
+	c := New(WithSchemeHandler("mock", mock), WithSchemeHandler("S3", presign))
+
+	resp, err := c.GetURL("mock://fixtures/repo.json", nil)
+	if err != nil || string(resp.Body) != "mocked /repo.json" {
+		t.Errorf("TestWithSchemeHandler was incorrect, got: %q, %v, want: mocked /repo.json.", resp.Body, err)
+	}
+
+	resp, err = c.GetURL("s3://artifacts/build/1.tar.gz", nil)
+	if err != nil || string(resp.Body) != "object /artifacts/build/1.tar.gz" {
+		t.Errorf("TestWithSchemeHandler was incorrect, got: %q, %v, want: the presigned object.", resp.Body, err)
+	}
+}
@@ -0,0 +1,69 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// secondaryRateLimitWait is the wait after a secondary rate limit without
+// Retry-After, as GitHub asks for at least a minute.
+const secondaryRateLimitWait = time.Minute
+
+// secondaryRateLimitMessages are the messages of the bodies of the GitHub
+// secondary rate limit responses, lowercased.
+var secondaryRateLimitMessages = [][]byte{
+	[]byte("secondary rate limit"),
+	[]byte("abuse detection mechanism"),
+}
+
+// WithSecondaryRateLimitHandler sets fn to be called, with the host and the
+// wait, on each GitHub secondary rate limit hit.
+func WithSecondaryRateLimitHandler(fn func(host string, wait time.Duration)) Option {
+	return func(c *Client) {
+		c.onSecondaryRateLimit = fn
+	}
+}
+
+// SecondaryRateLimits returns the number of GitHub secondary rate limits hit by the Client.
+func (c *Client) SecondaryRateLimits() int64 {
+	return atomic.LoadInt64(&c.secondaryRateLimits)
+}
+
+// isSecondaryRateLimit reports whether resp is a GitHub secondary rate limit,
+// a 403 telling so in the body, unlike the 403 for missing permissions.
+func isSecondaryRateLimit(resp HTTPResponse) bool {
+	if resp.Status.Code != http.StatusForbidden {
+		return false
+	}
+	body := bytes.ToLower(resp.Body)
+	for _, msg := range secondaryRateLimitMessages {
+		if bytes.Contains(body, msg) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// statusSecondaryRateLimit waits as asked by a secondary rate limit response
+// to a request of u.
+func (c *Client) statusSecondaryRateLimit(ctx context.Context, u *url.URL, resp *http.Response, expBackoffAttempts int) (int, error) {
+	atomic.AddInt64(&c.secondaryRateLimits, 1)
+
+	wait := secondaryRateLimitWait
+	if seconds, err := strconv.Atoi(resp.Header.Get(headerRetryAfter)); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	}
+	log.Warnf("Secondary rate limit reached, sleep %v - Resource: %s", wait, u)
+	if c.onSecondaryRateLimit != nil {
+		c.onSecondaryRateLimit(u.Host, wait)
+	}
+	return expBackoffAttempts, c.backoffSleep(ctx, wait)
+}
@@ -0,0 +1,122 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxSitemapSize is the maximum (uncompressed) size of a sitemap, as by the protocol.
+const maxSitemapSize = 50 << 20
+
+// maxSitemapDepth is the maximum nesting of sitemap indexes followed.
+const maxSitemapDepth = 2
+
+// sitemapTimeLayouts are the W3C Datetime formats allowed for lastmod.
+var sitemapTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02"}
+
+// SitemapEntry is an URL listed in a sitemap.
+type SitemapEntry struct {
+	Loc string
+	// LastMod is the zero time if not set or invalid.
+	LastMod    time.Time
+	ChangeFreq string
+	// Priority is 0 if not set.
+	Priority float64
+}
+
+// sitemapURL is the <url> or <sitemap> element of a sitemap.
+type sitemapURL struct {
+	Loc        string  `xml:"loc"`
+	LastMod    string  `xml:"lastmod"`
+	ChangeFreq string  `xml:"changefreq"`
+	Priority   float64 `xml:"priority"`
+}
+
+// GetSitemap downloads the sitemap at URL, plain or gzipped, and calls fn with
+// each of its entries, stopping at the first error returned by fn.
+// The sitemaps listed by a sitemap index are downloaded in turn.
+func GetSitemap(URL string, headers map[string]string, fn func(SitemapEntry) error) error {
+	return defaultClient.GetSitemap(URL, headers, fn)
+}
+
+// GetSitemap downloads the sitemap at URL, plain or gzipped, and calls fn with
+// each of its entries, stopping at the first error returned by fn.
+// The sitemaps listed by a sitemap index are downloaded in turn.
+func (c *Client) GetSitemap(URL string, headers map[string]string, fn func(SitemapEntry) error) error {
+	return c.getSitemap(URL, headers, fn, 0)
+}
+
+func (c *Client) getSitemap(URL string, headers map[string]string, fn func(SitemapEntry) error, depth int) error {
+	resp, err := c.Request(URL, "GET", headers, nil, WithMaxBodySize(maxSitemapSize))
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = bytes.NewReader(resp.Body)
+	if bytes.HasPrefix(resp.Body, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = &limitedBody{ReadCloser: gz, remaining: maxSitemapSize}
+	}
+
+	decoder := xml.NewDecoder(r)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || (start.Name.Local != "url" && start.Name.Local != "sitemap") {
+			continue
+		}
+
+		var u sitemapURL
+		if err := decoder.DecodeElement(&u, &start); err != nil {
+			return err
+		}
+		loc := strings.TrimSpace(u.Loc)
+		if loc == "" {
+			continue
+		}
+
+		if start.Name.Local == "sitemap" {
+			if depth < maxSitemapDepth {
+				if err := c.getSitemap(loc, headers, fn, depth+1); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if err := fn(SitemapEntry{
+			Loc:        loc,
+			LastMod:    parseSitemapTime(u.LastMod),
+			ChangeFreq: strings.TrimSpace(u.ChangeFreq),
+			Priority:   u.Priority,
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+func parseSitemapTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range sitemapTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,49 @@
+package httpclient
+
+import (
+	"compress/gzip"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestGetSitemap should test that the entries of an index and its gzipped sitemaps are streamed.
+func TestGetSitemap(t *testing.T) {
+	var ts *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%s/pages.xml.gz</loc></sitemap>
+</sitemapindex>`, ts.URL)
+	})
+	mux.HandleFunc("/pages.xml.gz", func(w http.ResponseWriter, _ *http.Request) {
+		gz := gzip.NewWriter(w)
+		fmt.Fprint(gz, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/</loc><lastmod>2020-10-01</lastmod><priority>1.0</priority></url>
+  <url><loc>https://example.com/software</loc><lastmod>2020-10-02T10:00:00+02:00</lastmod></url>
+</urlset>`)
+		gz.Close()
+	})
+	ts = httptest.NewServer(mux)
+	defer ts.Close()
+
+	var entries []SitemapEntry
+	err := GetSitemap(ts.URL+"/sitemap.xml", nil, func(e SitemapEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("TestGetSitemap was incorrect, got: %+v (%v)", entries, err)
+	}
+
+	if entries[0].Loc != "https://example.com/" || entries[0].Priority != 1 || !entries[0].LastMod.Equal(time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("TestGetSitemap was incorrect, got: %+v", entries[0])
+	}
+	if entries[1].LastMod.Unix() != time.Date(2020, 10, 2, 8, 0, 0, 0, time.UTC).Unix() {
+		t.Errorf("TestGetSitemap was incorrect, got: %+v", entries[1])
+	}
+}
@@ -0,0 +1,33 @@
+package httpclient
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+)
+
+// slowDownCodes are the error codes in the bodies of the 503 (Service
+// Unavailable) responses of the S3 compatible object stores asking to slow down.
+var slowDownCodes = [][]byte{
+	[]byte("<Code>SlowDown</Code>"),
+	[]byte("<Code>RequestLimitExceeded</Code>"),
+}
+
+// isSlowDown reports whether resp is a 503 asking to slow down, like the
+// SlowDown of S3, unlike the 503 of a server down.
+func isSlowDown(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		return false
+	}
+
+	r := readResponse(resp)
+	// Give the body back to the handling of the status.
+	resp.Body = ioutil.NopCloser(bytes.NewReader(r.Body))
+	for _, code := range slowDownCodes {
+		if bytes.Contains(r.Body, code) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,47 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestSlowDown should test that the 503 SlowDown of S3 is retried after the
+// backoff, unlike the other 503.
+func TestSlowDown(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, "maintenance")
+			return
+		}
+		hits++
+		if hits < 3 {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>`)
+			return
+		}
+		fmt.Fprint(w, "artifact")
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	c := New(WithClock(clock))
+
+	resp, err := c.GetURL(ts.URL+"/artifact.tar.gz?X-Amz-Signature=abc", nil)
+	if err != nil || string(resp.Body) != "artifact" || hits != 3 {
+		t.Fatalf("TestSlowDown was incorrect, got: %d hits, %q, %v, want: 3 hits, artifact, no error.", hits, resp.Body, err)
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 2 {
+		t.Errorf("TestSlowDown was incorrect, got sleeps: %v, want: 2 backoffs.", sleeps)
+	}
+
+	resp, err = c.GetURL(ts.URL+"/down", nil)
+	if !errors.Is(err, ErrInvalidStatus) || string(resp.Body) != "maintenance" {
+		t.Errorf("TestSlowDown was incorrect, got: %q, %v, want: maintenance, %v.", resp.Body, err, ErrInvalidStatus)
+	}
+}
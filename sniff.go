@@ -0,0 +1,59 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrSniffRejected is returned by GetWithSniff when the caller aborts the download.
+var ErrSniffRejected = errors.New("download aborted after sniffing the body")
+
+// sniffer decides whether to download a body from its first bytes, see GetWithSniff.
+type sniffer struct {
+	len    int
+	decide func(head []byte, hdr http.Header) bool
+}
+
+// sniff reads the first bytes of the body of resp and asks s whether to go on.
+// It returns them and false if the download is aborted, otherwise it restores
+// them in front of the body.
+func (s *sniffer) sniff(resp *http.Response) ([]byte, bool, error) {
+	head := make([]byte, s.len)
+	n, err := io.ReadFull(resp.Body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, false, err
+	}
+	head = head[:n]
+
+	if !s.decide(head, resp.Header) {
+		return head, false, nil
+	}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(head), resp.Body), resp.Body}
+
+	return head, true, nil
+}
+
+// GetWithSniff retrieves an URL like GetURL, but reads only the first sniffLen
+// bytes of the body before asking decide whether to download the rest.
+// If decide returns false the connection is closed and the response, whose
+// Body holds only the first bytes, is returned with ErrSniffRejected.
+func GetWithSniff(URL string, headers map[string]string, sniffLen int, decide func(head []byte, hdr http.Header) bool, opts ...RequestOption) (HTTPResponse, error) {
+	return defaultClient.GetWithSniff(URL, headers, sniffLen, decide, opts...)
+}
+
+// GetWithSniff retrieves an URL like GetURL, but reads only the first sniffLen
+// bytes of the body before asking decide whether to download the rest.
+// If decide returns false the connection is closed and the response, whose
+// Body holds only the first bytes, is returned with ErrSniffRejected.
+func (c *Client) GetWithSniff(URL string, headers map[string]string, sniffLen int, decide func(head []byte, hdr http.Header) bool, opts ...RequestOption) (HTTPResponse, error) {
+	sniff := func(cfg *requestConfig) {
+		cfg.sniffer = &sniffer{len: sniffLen, decide: decide}
+	}
+
+	return c.Request(URL, "GET", headers, nil, append(opts, sniff)...)
+}
@@ -0,0 +1,41 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestGetWithSniff should test that the body is downloaded only if decide wants it.
+func TestGetWithSniff(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/binary" {
+			w.Write(append([]byte("\x7fELF"), bytes.Repeat([]byte{0}, 1<<20)...))
+			return
+		}
+		w.Write([]byte("name: software\n"))
+	}))
+	defer ts.Close()
+
+	text := func(head []byte, _ http.Header) bool {
+		return bytes.IndexByte(head, 0) < 0
+	}
+
+	resp, err := GetWithSniff(ts.URL+"/publiccode.yml", nil, 8, text)
+	if err != nil {
+		t.Fatalf("TestGetWithSniff was incorrect, got error: %v", err)
+	}
+	if string(resp.Body) != "name: software\n" {
+		t.Errorf("TestGetWithSniff was incorrect, got: %q, want: %q.", resp.Body, "name: software\n")
+	}
+
+	resp, err = GetWithSniff(ts.URL+"/binary", nil, 8, text)
+	if !errors.Is(err, ErrSniffRejected) {
+		t.Errorf("TestGetWithSniff was incorrect, got error: %v, want: %v.", err, ErrSniffRejected)
+	}
+	if len(resp.Body) != 8 || resp.Status.Code != http.StatusOK {
+		t.Errorf("TestGetWithSniff was incorrect, got: %d bytes with status %d, want: 8 bytes with status 200.", len(resp.Body), resp.Status.Code)
+	}
+}
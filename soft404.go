@@ -0,0 +1,59 @@
+package httpclient
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// ErrSoft404 is returned for the successful responses detected as error pages
+// by WithSoft404. It wraps ErrNotFound, so errors.Is(err, ErrNotFound) holds too.
+var ErrSoft404 = fmt.Errorf("soft 404: %w", ErrNotFound)
+
+// defaultSoft404Patterns match the titles of the usual "not found" pages.
+var defaultSoft404Patterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b404\b`),
+	regexp.MustCompile(`(?i)not\s+found`),
+	regexp.MustCompile(`(?i)(doesn't|does not|no longer) exist`),
+	regexp.MustCompile(`(?i)page (is )?(missing|unavailable)`),
+}
+
+var htmlTitle = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// WithSoft404 makes the successful HTML responses whose title matches one of
+// patterns fail with ErrSoft404, e.g. the "not found" pages served with a 200
+// by some forges for the missing raw files. Pages without a title are matched
+// by their first KB. Without patterns the usual "404" and "not found" titles
+// are detected.
+func WithSoft404(patterns ...*regexp.Regexp) RequestOption {
+	if len(patterns) == 0 {
+		patterns = defaultSoft404Patterns
+	}
+
+	return WithSoft404Func(func(resp HTTPResponse) bool {
+		if m, err := ParseContentType(resp.Headers.Get("Content-Type")); err != nil || !m.Matches("text/html") {
+			return false
+		}
+
+		head := resp.Body
+		if m := htmlTitle.FindSubmatch(head); m != nil {
+			head = m[1]
+		} else if len(head) > charsetSniffLen {
+			head = head[:charsetSniffLen]
+		}
+		for _, re := range patterns {
+			if re.Match(head) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// WithSoft404Func makes the successful responses for which isSoft404 returns
+// true fail with ErrSoft404.
+func WithSoft404Func(isSoft404 func(resp HTTPResponse) bool) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.soft404 = isSoft404
+	}
+}
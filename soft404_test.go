@@ -0,0 +1,43 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+)
+
+// TestWithSoft404 should test that the error pages served with a 200 are detected.
+func TestWithSoft404(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/publiccode.yml":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("name: not found"))
+		case "/moved":
+			w.Header().Set("Content-Type", "text/html")
+			w.Write([]byte("<html><title>Repository moved</title></html>"))
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte("<html><head><title>Page Not Found · GitLab</title></head></html>"))
+		}
+	}))
+	defer ts.Close()
+
+	resp, err := GetURL(ts.URL+"/missing", nil, WithSoft404())
+	if !errors.Is(err, ErrSoft404) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("TestWithSoft404 was incorrect, got error: %v, want: %v.", err, ErrSoft404)
+	}
+	if resp.Status.Code != http.StatusOK || resp.Body == nil {
+		t.Errorf("TestWithSoft404 was incorrect, got: %d with body %q, want: the 200 response.", resp.Status.Code, resp.Body)
+	}
+
+	if _, err := GetURL(ts.URL+"/publiccode.yml", nil, WithSoft404()); err != nil {
+		t.Errorf("TestWithSoft404 was incorrect, got error: %v for a file, want: nil.", err)
+	}
+
+	if _, err := GetURL(ts.URL+"/moved", nil, WithSoft404(regexp.MustCompile(`(?i)moved`))); !errors.Is(err, ErrSoft404) {
+		t.Errorf("TestWithSoft404 was incorrect, got error: %v with a custom pattern, want: %v.", err, ErrSoft404)
+	}
+}
@@ -0,0 +1,127 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+)
+
+// ErrBlockedAddress is returned by the requests to the addresses blocked by WithSSRFGuard.
+var ErrBlockedAddress = errors.New("address blocked by the SSRF guard")
+
+// blockedNets are the networks not reachable WithSSRFGuard: the loopback,
+// private, shared, link-local, unspecified and multicast addresses, the IETF
+// protocol assignments, the benchmarking ones and the IPv4 translated by NAT64.
+var blockedNets = parseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"64:ff9b::/96",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+)
+
+// WithSSRFGuard makes the requests to the loopback, private and link-local
+// addresses, like 127.0.0.1 or the 169.254.169.254 of the cloud metadata,
+// fail with ErrBlockedAddress, so that crawling URLs of third parties can't
+// reach the internal network. The addresses are checked as dialed, after
+// the DNS resolution and on each redirect, the proxy of WithProxy included.
+// As the proxy dials the hosts of the requests sent through it, these hosts
+// are resolved and checked before the request is proxied: the proxy could
+// still resolve them to other addresses, so that it should refuse the
+// internal addresses too. The connections of WithDialContext aren't checked.
+func WithSSRFGuard() Option {
+	return func(c *Client) {
+		c.ssrfGuard = true
+	}
+}
+
+// ssrfControl is the Control of the dialer rejecting the blocked addresses.
+func ssrfControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || blockedIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+
+	return nil
+}
+
+// ssrfProxy returns the Proxy of the transport failing the requests to the
+// blocked hosts with ErrBlockedAddress, before sending them through the proxy
+// returned by proxy. The hosts are resolved by resolve.
+func ssrfProxy(proxy func(*http.Request) (*url.URL, error), resolve func(ctx context.Context, host string) ([]net.IP, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		u, err := proxy(req)
+		if err != nil || u == nil {
+			return u, err
+		}
+
+		host := req.URL.Hostname()
+		ips := []net.IP{net.ParseIP(host)}
+		if ips[0] == nil {
+			if ips, err = resolve(req.Context(), host); err != nil {
+				return nil, err
+			}
+		}
+		for _, ip := range ips {
+			if blockedIP(ip) {
+				return nil, fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+			}
+		}
+
+		return u, nil
+	}
+}
+
+// resolveIP resolves host with the default resolver.
+func resolveIP(ctx context.Context, host string) ([]net.IP, error) {
+	ips, _, err := lookupIP(ctx, host)
+
+	return ips, err
+}
+
+// blockedIP reports whether ip is in one of the blockedNets.
+func blockedIP(ip net.IP) bool {
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, n := range blockedNets {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseCIDRs parses the networks in CIDR notation, panicking on the invalid ones.
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+
+	return nets
+}
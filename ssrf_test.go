@@ -0,0 +1,75 @@
+package httpclient
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestWithSSRFGuard should test that the requests to the internal addresses are blocked.
+func TestWithSSRFGuard(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+	}))
+	defer ts.Close()
+
+	if _, err := New(WithSSRFGuard()).GetURL(ts.URL, nil); !errors.Is(err, ErrBlockedAddress) || hits != 0 {
+		t.Errorf("TestWithSSRFGuard was incorrect, got: %v, %d hits, want: ErrBlockedAddress, no hits.", err, hits)
+	}
+	if _, err := New().GetURL(ts.URL, nil); err != nil || hits != 1 {
+		t.Errorf("TestWithSSRFGuard was incorrect, got: %v, %d hits, want: 1 hit without the guard.", err, hits)
+	}
+}
+
+// TestWithSSRFGuardProxy should test that the hosts of the requests sent through a proxy
+// are checked before proxying them.
+func TestWithSSRFGuardProxy(t *testing.T) {
+	var hosts []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hosts = append(hosts, r.URL.Host)
+	}))
+	defer proxy.Close()
+	proxyURL, _ := url.Parse(proxy.URL)
+
+	// The proxy is reached by the dialer of WithDialContext, not checked.
+	c := New(WithSSRFGuard(), WithProxy(proxyURL), WithDialContext((&net.Dialer{}).DialContext))
+	for _, u := range []string{"http://169.254.169.254/latest/meta-data/", "http://localhost/admin"} {
+		if _, err := c.GetURL(u, nil); !errors.Is(err, ErrBlockedAddress) {
+			t.Errorf("TestWithSSRFGuardProxy was incorrect for %s, got: %v, want: ErrBlockedAddress.", u, err)
+		}
+	}
+	if _, err := c.GetURL("http://8.8.8.8/", nil); err != nil {
+		t.Errorf("TestWithSSRFGuardProxy was incorrect, got: %v, want: the public address proxied.", err)
+	}
+	if len(hosts) != 1 || hosts[0] != "8.8.8.8" {
+		t.Errorf("TestWithSSRFGuardProxy was incorrect, got proxied: %v, want: [8.8.8.8].", hosts)
+	}
+}
+
+// TestBlockedIP should test the addresses blocked by the SSRF guard.
+func TestBlockedIP(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1":          true,
+		"10.1.2.3":           true,
+		"172.20.0.1":         true,
+		"192.168.1.1":        true,
+		"169.254.169.254":    true,
+		"::1":                true,
+		"fd00::1":            true,
+		"::ffff:127.0.0.1":   true,
+		"198.18.0.1":         true,
+		"64:ff9b::a9fe:a9fe": true,
+		"8.8.8.8":            false,
+		"172.32.0.1":         false,
+		"2001:4860::8888":    false,
+	}
+	for ip, want := range tests {
+		if got := blockedIP(net.ParseIP(ip)); got != want {
+			t.Errorf("TestBlockedIP was incorrect for %s, got: %v, want: %v.", ip, got, want)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package httpclient
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// latencyWindow is the number of the last latencies of each host kept for the percentiles.
+const latencyWindow = 100
+
+// healthWindow is the number of the last attempts to each host its health is computed on.
+const healthWindow = 100
+
+// statsSweepSize is the number of hosts after which the ones idle for statsTTL
+// are swept, at most every statsSweepInterval.
+const statsSweepSize = 1024
+
+// statsSweepInterval is the minimum time between two sweeps of the idle hosts.
+const statsSweepInterval = time.Minute
+
+// statsTTL is the time after its last attempt the statistics of a host are kept for.
+const statsTTL = time.Hour
+
+// maxStatsHosts is the number of hosts whose statistics are kept, the least
+// recently used ones dropped above it.
+const maxStatsHosts = 8192
+
+// minHealthSamples is the number of attempts needed to classify a host as not healthy.
+const minHealthSamples = 5
+
+// Default error rates of WithHealthThresholds.
+const (
+	defaultDegradedErrorRate  = 0.1
+	defaultUnhealthyErrorRate = 0.5
+)
+
+// Health is the health of a host, classified by the error rate of its last attempts.
+type Health int
+
+const (
+	// HealthHealthy hosts have an error rate below the degraded threshold.
+	HealthHealthy Health = iota
+	// HealthDegraded hosts have an error rate below the unhealthy threshold.
+	HealthDegraded
+	// HealthUnhealthy hosts have an error rate from the unhealthy threshold up.
+	HealthUnhealthy
+)
+
+func (h Health) String() string {
+	switch h {
+	case HealthHealthy:
+		return "healthy"
+	case HealthDegraded:
+		return "degraded"
+	case HealthUnhealthy:
+		return "unhealthy"
+	}
+
+	return "Health(" + strconv.Itoa(int(h)) + ")"
+}
+
+// HostStats are the statistics of the requests to a host, see Client.Stats.
+type HostStats struct {
+	// Samples is the number of latencies the percentiles are computed on,
+	// the last ones up to 100.
+	Samples int
+	// P50, P90 and P99 are the percentiles of the latency of the attempts,
+	// from sending the request to receiving the response headers.
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	// Attempts and Errors are the last attempts, up to 100, and the failed
+	// ones among them: transport errors and 5xx statuses.
+	Attempts int
+	Errors   int
+	Health   Health
+}
+
+// hostStats are the rolling statistics of a host.
+type hostStats struct {
+	latencies [latencyWindow]time.Duration
+	// next is the index of the next latency in the ring, n the latencies in it.
+	next, n int
+
+	// failed are the outcomes of the last attempts, in a ring as latencies.
+	failed                        [healthWindow]bool
+	nextOutcome, outcomes, errors int
+
+	// last is the time of the last attempt.
+	last time.Time
+}
+
+// stats holds the hostStats of the hosts.
+type stats struct {
+	mu    sync.Mutex
+	hosts map[string]*hostStats
+	// swept is the time of the last sweep.
+	swept time.Time
+}
+
+// Stats returns the statistics of the requests to each host, e.g. to
+// deprioritize the slow hosts. The statistics of the hosts without attempts
+// for an hour are dropped once there are more than 1024 hosts, and the least
+// recently used ones once there are 8192, so that crawling doesn't grow them
+// without bounds.
+func (c *Client) Stats() map[string]HostStats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	out := make(map[string]HostStats, len(c.stats.hosts))
+	for host, s := range c.stats.hosts {
+		sorted := append([]time.Duration(nil), s.latencies[:s.n]...)
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		out[host] = HostStats{
+			Samples:  s.n,
+			P50:      percentile(sorted, 50),
+			P90:      percentile(sorted, 90),
+			P99:      percentile(sorted, 99),
+			Attempts: s.outcomes,
+			Errors:   s.errors,
+			Health:   c.health(s),
+		}
+	}
+
+	return out
+}
+
+// recordLatency records the latency of an attempt to host.
+func (c *Client) recordLatency(host string, latency time.Duration) {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	s := c.stats.host(host, c.clock.Now())
+	s.latencies[s.next] = latency
+	s.next = (s.next + 1) % latencyWindow
+	if s.n < latencyWindow {
+		s.n++
+	}
+}
+
+// host returns the hostStats of host, adding them if missing, and marks them
+// used at now. st.mu must be held.
+func (st *stats) host(host string, now time.Time) *hostStats {
+	if st.hosts == nil {
+		st.hosts = make(map[string]*hostStats)
+	}
+	s, ok := st.hosts[host]
+	if !ok {
+		if n := len(st.hosts); n >= maxStatsHosts || (n >= statsSweepSize && now.Sub(st.swept) >= statsSweepInterval) {
+			st.sweep(now)
+		}
+		s = &hostStats{}
+		st.hosts[host] = s
+	}
+	s.last = now
+
+	return s
+}
+
+// sweep drops the hosts idle for statsTTL, then the least recently used
+// quarter if there are still maxStatsHosts. st.mu must be held.
+func (st *stats) sweep(now time.Time) {
+	st.swept = now
+	for h, s := range st.hosts {
+		if now.Sub(s.last) >= statsTTL {
+			delete(st.hosts, h)
+		}
+	}
+	if len(st.hosts) < maxStatsHosts {
+		return
+	}
+
+	hosts := make([]string, 0, len(st.hosts))
+	for h := range st.hosts {
+		hosts = append(hosts, h)
+	}
+	sort.Slice(hosts, func(i, j int) bool { return st.hosts[hosts[i]].last.Before(st.hosts[hosts[j]].last) })
+	for _, h := range hosts[:len(hosts)/4] {
+		delete(st.hosts, h)
+	}
+}
+
+// WithHealthThresholds sets the error rates, between 0 and 1, from which a
+// host is degraded and unhealthy. Defaults are 0.1 and 0.5.
+func WithHealthThresholds(degraded, unhealthy float64) Option {
+	return func(c *Client) {
+		c.degradedErrorRate = degraded
+		c.unhealthyErrorRate = unhealthy
+	}
+}
+
+// Health returns the health of host, e.g. "api.github.com", as by the error
+// rate of its last attempts. Hosts without enough attempts are healthy.
+func (c *Client) Health(host string) Health {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	s, ok := c.stats.hosts[host]
+	if !ok {
+		return HealthHealthy
+	}
+
+	return c.health(s)
+}
+
+// health classifies s. c.stats.mu must be held.
+func (c *Client) health(s *hostStats) Health {
+	if s.outcomes < minHealthSamples {
+		return HealthHealthy
+	}
+
+	rate := float64(s.errors) / float64(s.outcomes)
+	switch {
+	case rate >= c.unhealthyErrorRate:
+		return HealthUnhealthy
+	case rate >= c.degradedErrorRate:
+		return HealthDegraded
+	default:
+		return HealthHealthy
+	}
+}
+
+// recordOutcome records whether an attempt to host failed.
+func (c *Client) recordOutcome(host string, failed bool) {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	s := c.stats.host(host, c.clock.Now())
+	if s.outcomes == healthWindow && s.failed[s.nextOutcome] {
+		s.errors--
+	}
+	s.failed[s.nextOutcome] = failed
+	if failed {
+		s.errors++
+	}
+	s.nextOutcome = (s.nextOutcome + 1) % healthWindow
+	if s.outcomes < healthWindow {
+		s.outcomes++
+	}
+}
+
+// percentile returns the p-th percentile of sorted, by the nearest rank.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+
+	return sorted[rank-1]
+}
@@ -0,0 +1,109 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStats should test that the latency percentiles of each host are computed on the last requests.
+func TestStats(t *testing.T) {
+	clock := newTestClock()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		d, _ := time.ParseDuration(r.URL.Query().Get("latency"))
+		// The server takes its time advancing the clock of the Client.
+		<-clock.After(d)
+	}))
+	defer ts.Close()
+
+	c := New(WithClock(clock))
+	// The first 10 requests fall out of the window.
+	for i := 0; i < 10+latencyWindow; i++ {
+		latency := "10s"
+		if i >= 10 {
+			latency = (time.Duration(i-9) * time.Millisecond).String()
+		}
+		if _, err := c.GetURL(ts.URL+"/?latency="+latency, nil); err != nil {
+			t.Fatalf("TestStats was incorrect, got error: %v", err)
+		}
+	}
+
+	got, ok := c.Stats()[strings.TrimPrefix(ts.URL, "http://")]
+	want := HostStats{Samples: 100, P50: 50 * time.Millisecond, P90: 90 * time.Millisecond, P99: 99 * time.Millisecond, Attempts: 100}
+	if !ok || got != want {
+		t.Errorf("TestStats was incorrect, got: %+v, want: %+v.", got, want)
+	}
+}
+
+// TestHealth should test that the hosts are classified by the error rate of their last attempts.
+func TestHealth(t *testing.T) {
+	failures := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			failures++
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "http://")
+
+	c := New()
+	tests := []struct {
+		ok, fail int
+		want     Health
+	}{
+		{0, 3, HealthHealthy}, // Too few attempts.
+		{7, 0, HealthDegraded},
+		{0, 5, HealthUnhealthy},
+		{10, 0, HealthDegraded},
+	}
+	for _, test := range tests {
+		for i := 0; i < test.ok; i++ {
+			c.GetURL(ts.URL, nil)
+		}
+		for i := 0; i < test.fail; i++ {
+			c.GetURL(ts.URL+"/fail", nil)
+		}
+		if got := c.Health(host); got != test.want {
+			t.Errorf("TestHealth was incorrect, got: %v after %d failures, want: %v.", got, failures, test.want)
+		}
+	}
+
+	if s := c.Stats()[host]; s.Attempts != 25 || s.Errors != 8 || s.Health != HealthDegraded {
+		t.Errorf("TestHealth was incorrect, got: %+v, want: 8 errors in 25 attempts.", s)
+	}
+	if got := c.Health("unknown.example"); got != HealthHealthy {
+		t.Errorf("TestHealth was incorrect, got: %v for an unknown host, want: %v.", got, HealthHealthy)
+	}
+}
+
+// TestStatsSweep should test that the statistics of the idle and least recently used hosts are dropped.
+func TestStatsSweep(t *testing.T) {
+	clock := newTestClock()
+	c := New(WithClock(clock))
+
+	for i := 0; i < statsSweepSize; i++ {
+		c.recordOutcome("idle"+strconv.Itoa(i), false)
+	}
+	<-clock.After(statsTTL)
+	c.recordOutcome("new", false)
+	if got := len(c.Stats()); got != 1 {
+		t.Errorf("TestStatsSweep was incorrect, got: %d hosts, want: 1 after sweeping the idle ones.", got)
+	}
+
+	for i := 1; i < maxStatsHosts; i++ {
+		<-clock.After(time.Millisecond)
+		c.recordOutcome("host"+strconv.Itoa(i), false)
+	}
+	c.recordOutcome("last", false)
+	stats := c.Stats()
+	if _, ok := stats["new"]; ok || len(stats) != maxStatsHosts-maxStatsHosts/4+1 {
+		t.Errorf("TestStatsSweep was incorrect, got: %d hosts, want: %d without the least recently used.", len(stats), maxStatsHosts-maxStatsHosts/4+1)
+	}
+	if _, ok := stats["last"]; !ok {
+		t.Errorf("TestStatsSweep was incorrect, got: the last host dropped, want: kept.")
+	}
+}
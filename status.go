@@ -1,7 +1,7 @@
 package httpclient
 
 import (
-	"fmt"
+	"context"
 	"io/ioutil"
 	"net/http"
 	"strconv"
@@ -20,6 +20,8 @@ const (
 	headerRetryAfter    = "Retry-After"
 	headerRateReset     = "X-RateLimit-Reset"
 	headerRateRemaining = "X-RateLimit-Remaining"
+	// headerFromCache is set by caching transports, e.g. github.com/gregjones/httpcache.
+	headerFromCache = "X-From-Cache"
 )
 
 // statusOK returns an HTTPResponse with the data from response.
@@ -27,11 +29,7 @@ func statusOK(resp *http.Response) (HTTPResponse, error) {
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		log.Errorf(err.Error())
-		return HTTPResponse{
-			Body:    nil,
-			Status:  ResponseStatus{Text: resp.Status, Code: resp.StatusCode},
-			Headers: resp.Header,
-		}, err
+		return newHTTPResponse(resp, nil), err
 	}
 
 	err = resp.Body.Close()
@@ -39,24 +37,70 @@ func statusOK(resp *http.Response) (HTTPResponse, error) {
 		log.Errorf(err.Error())
 	}
 
-	return HTTPResponse{
-		Body:    body,
-		Status:  ResponseStatus{Text: resp.Status, Code: resp.StatusCode},
-		Headers: resp.Header,
-	}, nil
+	return newHTTPResponse(resp, body), nil
 }
 
 // statusNotFound returns an HTTPResponse with the data from response.
-func statusNotFound(resp *http.Response) (HTTPResponse, error) {
-	return HTTPResponse{
-		Body:    nil,
-		Status:  ResponseStatus{Text: resp.Status, Code: resp.StatusCode},
-		Headers: resp.Header,
-	}, fmt.Errorf("not found")
+func (c *Client) statusNotFound(resp *http.Response) (HTTPResponse, error) {
+	return c.statusError(resp, ErrNotFound)
+}
+
+// statusError returns an HTTPResponse with the data from response and err
+// wrapped as by responseError.
+func (c *Client) statusError(resp *http.Response, err error) (HTTPResponse, error) {
+	r := readResponse(resp)
+	return r, c.responseError(r, err, nil)
+}
+
+// responseError wraps err in an *HTTPError with an excerpt of the body of resp
+// and the attempts made, itself wrapped in a *ProblemError if resp carries
+// problem details.
+func (c *Client) responseError(resp HTTPResponse, err error, attempts []Attempt) error {
+	excerpt := resp.Body
+	if len(excerpt) > c.errorBodyExcerpt {
+		excerpt = excerpt[:c.errorBodyExcerpt]
+	}
+
+	return problemError(resp, &HTTPError{
+		StatusCode:  resp.Status.Code,
+		Status:      resp.Status.Text,
+		BodyExcerpt: excerpt,
+		Attempts:    attempts,
+		Err:         err,
+	})
+}
+
+// readResponse returns an HTTPResponse with the data from response.
+// The body read until an error, if any, is kept.
+func readResponse(resp *http.Response) HTTPResponse {
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Errorf(err.Error())
+	}
+
+	return newHTTPResponse(resp, body)
+}
+
+// newHTTPResponse returns an HTTPResponse with the data from response and body.
+func newHTTPResponse(resp *http.Response, body []byte) HTTPResponse {
+	r := HTTPResponse{
+		Body:      body,
+		Status:    ResponseStatus{Text: resp.Status, Code: resp.StatusCode},
+		Headers:   resp.Header,
+		FromCache: resp.Header.Get(headerFromCache) != "",
+	}
+	r.setCacheValidators(resp.Header)
+	if resp.Request != nil {
+		r.Method = resp.Request.Method
+		r.URL = resp.Request.URL.String()
+	}
+	r.Deprecation = parseDeprecation(r)
+
+	return r
 }
 
 // statusTooManyRequests returns an HTTPResponse with the data from response.
-func statusTooManyRequests(resp *http.Response, expBackoffAttempts int) (int, error) {
+func (c *Client) statusTooManyRequests(ctx context.Context, resp *http.Response, expBackoffAttempts int) (int, error) {
 	// If Retry-after Header is set, use the header value.
 	if retryAfter := resp.Header.Get(headerRetryAfter); retryAfter != "" {
 		log.Infof("Waiting: %s seconds. (The value of %s)", retryAfter, headerRetryAfter)
@@ -64,21 +108,22 @@ func statusTooManyRequests(resp *http.Response, expBackoffAttempts int) (int, er
 		if err != nil {
 			log.Warn(err)
 		}
-		time.Sleep(time.Second * time.Duration(secondsAfterRetry))
-		return expBackoffAttempts, nil
+		return expBackoffAttempts, c.backoffSleep(ctx, time.Second*time.Duration(secondsAfterRetry))
 	}
 	// Calculate ExpBackoff
 	expBackoffWait := expBackoffCalc(expBackoffAttempts)
 	// Perform a backoff sleep time.
 	sleep := time.Duration(expBackoffWait) * time.Second
 	log.Infof("Rate limit reached, sleep %v \n", sleep)
-	time.Sleep(sleep)
+	if err := c.backoffSleep(ctx, sleep); err != nil {
+		return expBackoffAttempts, err
+	}
 
 	return expBackoffAttempts + 1, nil
 }
 
 // statusForbidden returns an HTTPResponse with the data from response.
-func statusForbidden(resp *http.Response, expBackoffAttempts int) (int, error) {
+func (c *Client) statusForbidden(ctx context.Context, resp *http.Response, expBackoffAttempts int) (int, error) {
 	// If Retry-after is set, use that value.
 	if retryAfter := resp.Header.Get(headerRetryAfter); retryAfter != "" {
 		log.Infof("Waiting: %s seconds. (The value of %s)", retryAfter, headerRetryAfter)
@@ -86,8 +131,7 @@ func statusForbidden(resp *http.Response, expBackoffAttempts int) (int, error) {
 		if err != nil {
 			log.Warn(err)
 		}
-		time.Sleep(time.Second * time.Duration(secondsAfterRetry))
-		return expBackoffAttempts, nil
+		return expBackoffAttempts, c.backoffSleep(ctx, time.Second*time.Duration(secondsAfterRetry))
 	}
 
 	// If X-rateLimit-remaining
@@ -100,21 +144,55 @@ func statusForbidden(resp *http.Response, expBackoffAttempts int) (int, error) {
 			}
 			if rateRemaining != 0 {
 				// In this case there is another StatusForbidden and i should skip.
-				return expBackoffAttempts, fmt.Errorf("forbidden resource")
+				return expBackoffAttempts, ErrForbidden
 			}
 
 			retryEpoch, err := strconv.Atoi(reset)
 			if err != nil {
 				log.Warn(err)
 			}
-			secondsAfterRetry := int64(retryEpoch) - time.Now().Unix()
+			secondsAfterRetry := int64(retryEpoch) - c.clock.Now().Unix()
 			log.Infof("Waiting %s seconds for %s. (The difference between header %s and time.Now())", strconv.FormatInt(secondsAfterRetry, 10), headerRateReset, reset)
-			time.Sleep(time.Second * time.Duration(secondsAfterRetry))
-			return expBackoffAttempts, nil
+			return expBackoffAttempts, c.backoffSleep(ctx, time.Second*time.Duration(secondsAfterRetry))
 		}
 	}
 
 	// Generic forbidden.
-	return expBackoffAttempts, fmt.Errorf("forbidden resource")
+	return expBackoffAttempts, ErrForbidden
+
+}
+
+// WithBackoffBounds bounds each wait before retrying a request to at least min
+// and at most max, whatever Retry-After or X-RateLimit-Reset ask for.
+// A zero max leaves the waits unbounded.
+func WithBackoffBounds(min, max time.Duration) Option {
+	return func(c *Client) {
+		c.minBackoff = min
+		c.maxBackoff = max
+	}
+}
+
+// WithMaxRetries retries at most n times the failed attempts of each request,
+// returning the last failure once they are exhausted. Zero disables the
+// retries. The exponential backoff keeps growing with the retries, see
+// WithBackoffBounds to bound it. By default a request is retried until its
+// backoff reaches about 2 minutes.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) {
+		c.maxRetries = n
+	}
+}
+
+// backoffSleep sleeps for d, bounded as by WithBackoffBounds, returning
+// ctx.Err() as soon as ctx is done.
+func (c *Client) backoffSleep(ctx context.Context, d time.Duration) error {
+	if d < c.minBackoff {
+		d = c.minBackoff
+	}
+	if c.maxBackoff > 0 && d > c.maxBackoff {
+		log.Warnf("Waiting %v in place of %v, the maximum backoff", c.maxBackoff, d)
+		d = c.maxBackoff
+	}
 
+	return c.sleepContext(ctx, d)
 }
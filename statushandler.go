@@ -0,0 +1,35 @@
+package httpclient
+
+// StatusAction is the decision of a StatusHandler about a response.
+type StatusAction int
+
+const (
+	// StatusDefault leaves the response to the built-in handling of its status.
+	StatusDefault StatusAction = iota
+	// StatusRetry retries the request as after a 429 (Too Many Requests):
+	// after the Retry-After of the response, or else the exponential backoff.
+	StatusRetry
+	// StatusStop returns the response, failing with the error of the handler
+	// wrapped in an *HTTPError, or successfully if there's none.
+	StatusStop
+)
+
+// StatusHandler handles the responses of a status code, see WithStatusHandler.
+// It gets the response with its body read, and can change it to transform
+// the response returned by StatusStop. The error returned goes with StatusStop,
+// or with StatusRetry the error returned if the retries are exhausted,
+// ErrRateLimited if nil.
+type StatusHandler func(resp *HTTPResponse) (StatusAction, error)
+
+// WithStatusHandler registers h to handle the responses with status code,
+// e.g. to parse the error body of an API or to retry a vendor specific rate
+// limiting status. The handlers of the 2xx statuses are ignored, like those
+// of the statuses left to the caller, e.g. the 304 (Not Modified) of Poll.
+func WithStatusHandler(code int, h StatusHandler) Option {
+	return func(c *Client) {
+		if c.statusHandlers == nil {
+			c.statusHandlers = make(map[int]StatusHandler)
+		}
+		c.statusHandlers[code] = h
+	}
+}
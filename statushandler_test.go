@@ -0,0 +1,101 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestStatusHandlerRetry should test that a status retried by its handler waits as a 429.
+func TestStatusHandlerRetry(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		hits++
+		if hits < 3 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(420)
+			return
+		}
+		fmt.Fprint(w, "calm")
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	c := New(WithClock(clock), WithStatusHandler(420, func(*HTTPResponse) (StatusAction, error) {
+		return StatusRetry, nil
+	}))
+
+	resp, err := c.GetURL(ts.URL, nil)
+	if err != nil || string(resp.Body) != "calm" || hits != 3 {
+		t.Fatalf("TestStatusHandlerRetry was incorrect, got: %d hits, %q, %v, want: 3 hits, calm, no error.", hits, resp.Body, err)
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 2 || sleeps[0] != 7*time.Second {
+		t.Errorf("TestStatusHandlerRetry was incorrect, got sleeps: %v, want: twice 7s.", sleeps)
+	}
+}
+
+// TestStatusHandlerStop should test that a handler ends the request with its error or its response.
+func TestStatusHandlerStop(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/conflict" {
+			w.WriteHeader(http.StatusConflict)
+			fmt.Fprint(w, `{"message":"branch already exists"}`)
+			return
+		}
+		w.WriteHeader(http.StatusGone)
+		fmt.Fprint(w, "gone")
+	}))
+	defer ts.Close()
+
+	errConflict := errors.New("conflict")
+	c := New(
+		WithStatusHandler(http.StatusConflict, func(resp *HTTPResponse) (StatusAction, error) {
+			if strings.Contains(string(resp.Body), "already exists") {
+				return StatusStop, errConflict
+			}
+			return StatusDefault, nil
+		}),
+		WithStatusHandler(http.StatusGone, func(resp *HTTPResponse) (StatusAction, error) {
+			resp.Body = []byte("tombstone")
+			return StatusStop, nil
+		}),
+	)
+
+	resp, err := c.GetURL(ts.URL+"/conflict", nil)
+	var httpErr *HTTPError
+	if !errors.Is(err, errConflict) || !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusConflict {
+		t.Errorf("TestStatusHandlerStop was incorrect, got: %v, want: the conflict error in an *HTTPError.", err)
+	}
+	if resp.Status.Code != http.StatusConflict {
+		t.Errorf("TestStatusHandlerStop was incorrect, got status: %d, want: 409.", resp.Status.Code)
+	}
+
+	resp, err = c.GetURL(ts.URL+"/gone", nil)
+	if err != nil || string(resp.Body) != "tombstone" {
+		t.Errorf("TestStatusHandlerStop was incorrect, got: %q, %v, want: tombstone, no error.", resp.Body, err)
+	}
+}
+
+// TestStatusHandlerDefault should test that StatusDefault keeps the built-in handling and the body.
+func TestStatusHandlerDefault(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "missing")
+	}))
+	defer ts.Close()
+
+	called := false
+	c := New(WithStatusHandler(http.StatusNotFound, func(*HTTPResponse) (StatusAction, error) {
+		called = true
+		return StatusDefault, nil
+	}))
+
+	resp, err := c.GetURL(ts.URL, nil)
+	if !called || !errors.Is(err, ErrNotFound) || string(resp.Body) != "missing" {
+		t.Errorf("TestStatusHandlerDefault was incorrect, got: %v, %q, %v, want: called, missing, ErrNotFound.", called, resp.Body, err)
+	}
+}
@@ -0,0 +1,103 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// ErrStalled is returned when no bytes of a body arrived within the timeout of WithStallTimeout.
+var ErrStalled = errors.New("transfer stalled")
+
+// Timeouts are the timeouts of the phases of the requests. Zero values keep
+// the defaults of the Client.
+type Timeouts struct {
+	// Dial bounds establishing the TCP connection, 30s by default.
+	Dial time.Duration
+	// TLSHandshake bounds the TLS handshake, 10s by default.
+	TLSHandshake time.Duration
+	// ResponseHeader bounds waiting for the response headers once the
+	// request is sent.
+	ResponseHeader time.Duration
+	// IdleConn is how long an unused keep-alive connection is kept in the
+	// pool, 90s by default. It doesn't bound the reads, see IdleRead.
+	IdleConn time.Duration
+	// IdleRead bounds the time without any byte of a response body, as
+	// WithStallTimeout does, which takes precedence. Zero means no limit.
+	IdleRead time.Duration
+	// Total bounds the whole request, body included. Zero means no limit:
+	// slow bodies of responses starting in time are read in full.
+	Total time.Duration
+}
+
+// WithTimeouts sets the timeouts of each phase of the requests in place of the
+// overall 60s timeout of the Client, so that unreachable hosts fail fast while
+// long downloads still succeed. With WithTransport only Total and IdleRead
+// apply.
+func WithTimeouts(t Timeouts) Option {
+	return func(c *Client) {
+		c.timeouts = &t
+	}
+}
+
+// WithStallTimeout aborts the attempts whose response body doesn't receive any
+// byte for d, failing with ErrStalled. Unlike Timeouts.Total it doesn't bound
+// the whole transfer, so that long downloads succeed while dead connections
+// are detected quickly. The stalled idempotent requests are retried.
+func WithStallTimeout(d time.Duration) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.stallTimeout = d
+	}
+}
+
+// stallBody cancels its attempt when no bytes are read for timeout.
+type stallBody struct {
+	io.ReadCloser
+	timeout time.Duration
+	timer   *time.Timer
+	stalled int32
+}
+
+func newStallBody(body io.ReadCloser, timeout time.Duration, cancel context.CancelFunc) *stallBody {
+	b := &stallBody{ReadCloser: body, timeout: timeout}
+	b.timer = time.AfterFunc(timeout, func() {
+		atomic.StoreInt32(&b.stalled, 1)
+		cancel()
+	})
+
+	return b
+}
+
+func (b *stallBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 && atomic.LoadInt32(&b.stalled) == 0 {
+		b.timer.Reset(b.timeout)
+	}
+	if err != nil && err != io.EOF && atomic.LoadInt32(&b.stalled) == 1 {
+		err = fmt.Errorf("%w: no data for %v", ErrStalled, b.timeout)
+	}
+
+	return n, err
+}
+
+// pause stops the timer while waiting with wait, e.g. for the bandwidth
+// limits, the body not being read then.
+func (b *stallBody) pause(wait func() error) error {
+	if !b.timer.Stop() {
+		return wait()
+	}
+	err := wait()
+	if atomic.LoadInt32(&b.stalled) == 0 {
+		b.timer.Reset(b.timeout)
+	}
+
+	return err
+}
+
+func (b *stallBody) Close() error {
+	b.timer.Stop()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,120 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWithTimeouts should test that the response header timeout fails fast
+// while a slow body is read in full.
+func TestWithTimeouts(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-header" {
+			time.Sleep(200 * time.Millisecond)
+			return
+		}
+		for i := 0; i < 3; i++ {
+			w.Write([]byte("chunk"))
+			w.(http.Flusher).Flush()
+			time.Sleep(100 * time.Millisecond)
+		}
+	}))
+	defer ts.Close()
+
+	c := New(WithTimeouts(Timeouts{ResponseHeader: 50 * time.Millisecond}))
+	if _, err := c.GetURL(ts.URL+"/slow-header", nil); err == nil {
+		t.Errorf("TestWithTimeouts was incorrect, got: <nil>, want: a timeout error.")
+	}
+
+	resp, err := c.GetURL(ts.URL+"/slow-body", nil)
+	if err != nil || string(resp.Body) != "chunkchunkchunk" {
+		t.Errorf("TestWithTimeouts was incorrect, got: %q (%v), want: \"chunkchunkchunk\".", resp.Body, err)
+	}
+}
+
+// TestWithTimeoutPerAttempt should test that the timeout applies to each attempt.
+func TestWithTimeoutPerAttempt(t *testing.T) {
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(60 * time.Millisecond)
+		if hits++; hits < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+
+	// Three attempts of 60ms each, within 100ms each.
+	resp, err := GetURL(ts.URL, nil, WithTimeout(100*time.Millisecond))
+	if err != nil || resp.Attempts != 3 {
+		t.Errorf("TestWithTimeoutPerAttempt was incorrect, got: %d attempts (%v), want: 3.", resp.Attempts, err)
+	}
+}
+
+// TestWithStallTimeout should test that a stalled body is aborted and retried, while a slow one is read.
+func TestWithStallTimeout(t *testing.T) {
+	var stalls int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stall := r.URL.Path == "/stall" && atomic.AddInt32(&stalls, 1) == 1 || r.URL.Path == "/dead"
+		w.Header().Set("Content-Length", "10")
+		for i := 0; i < 10; i++ {
+			w.Write([]byte("x"))
+			w.(http.Flusher).Flush()
+			switch {
+			case stall && i == 4:
+				time.Sleep(400 * time.Millisecond)
+				return
+			case r.URL.Path == "/slow":
+				time.Sleep(20 * time.Millisecond)
+			}
+		}
+	}))
+	defer ts.Close()
+
+	c := New(WithClock(newTestClock()))
+	// The first attempt stalls halfway.
+	resp, err := c.GetURL(ts.URL+"/stall", nil, WithStallTimeout(100*time.Millisecond))
+	if err != nil || len(resp.Body) != 10 || resp.Attempts != 2 {
+		t.Errorf("TestWithStallTimeout was incorrect, got: %d bytes after %d attempts, error: %v, want: the body at the second attempt.", len(resp.Body), resp.Attempts, err)
+	}
+
+	resp, err = c.GetURL(ts.URL+"/slow", nil, WithStallTimeout(100*time.Millisecond))
+	if err != nil || len(resp.Body) != 10 {
+		t.Errorf("TestWithStallTimeout was incorrect, got: %d bytes, error: %v, want: the slow body in full.", len(resp.Body), err)
+	}
+
+	_, err = c.PostURL(ts.URL+"/dead", nil, nil, WithStallTimeout(100*time.Millisecond))
+	if !errors.Is(err, ErrStalled) {
+		t.Errorf("TestWithStallTimeout was incorrect, got error: %v, want: %v.", err, ErrStalled)
+	}
+}
+
+// TestTimeoutsIdleRead should test that a body without any byte for the idle read timeout
+// fails, while a slow one is read.
+func TestTimeoutsIdleRead(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "3")
+		for i := 0; i < 3; i++ {
+			w.Write([]byte("x"))
+			w.(http.Flusher).Flush()
+			if r.URL.Path == "/dead" {
+				time.Sleep(400 * time.Millisecond)
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+	defer ts.Close()
+
+	c := New(WithClock(newTestClock()), WithMaxRetries(0), WithTimeouts(Timeouts{IdleRead: 100 * time.Millisecond}))
+	if resp, err := c.GetURL(ts.URL+"/slow", nil); err != nil || string(resp.Body) != "xxx" {
+		t.Errorf("TestTimeoutsIdleRead was incorrect, got: %q (%v), want: \"xxx\".", resp.Body, err)
+	}
+	if _, err := c.GetURL(ts.URL+"/dead", nil); !errors.Is(err, ErrStalled) {
+		t.Errorf("TestTimeoutsIdleRead was incorrect, got error: %v, want: %v.", err, ErrStalled)
+	}
+}
@@ -0,0 +1,94 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+)
+
+const (
+	headerTraceParent = "Traceparent"
+	headerTraceState  = "Tracestate"
+)
+
+// traceParentFormat matches a version 00 traceparent header (W3C Trace Context).
+var traceParentFormat = regexp.MustCompile(`^00-([0-9a-f]{32})-[0-9a-f]{16}-([0-9a-f]{2})$`)
+
+// traceKey is the context key of the trace context propagated by ContextWithTrace.
+type traceKey struct{}
+
+// trace is the W3C trace context of a request.
+type trace struct {
+	id    string
+	flags string
+	state string
+}
+
+// WithTraceContext sends the W3C Trace Context traceparent and tracestate
+// headers, with a new parent id for each attempt. The trace is the one of the
+// traceparent header of the request, else the one of the context (see
+// ContextWithTrace), else a new sampled one. Its id is set in
+// HTTPResponse.TraceID and in the events of the request.
+func WithTraceContext() Option {
+	return func(c *Client) {
+		c.traceContext = true
+	}
+}
+
+// ContextWithTrace returns a copy of ctx carrying the trace of traceparent and
+// tracestate, e.g. from the incoming request of a server, propagated by the
+// requests made with ctx by the Clients WithTraceContext. An invalid
+// traceparent is ignored.
+func ContextWithTrace(ctx context.Context, traceparent, tracestate string) context.Context {
+	t, ok := parseTraceParent(traceparent)
+	if !ok {
+		return ctx
+	}
+	t.state = tracestate
+
+	return context.WithValue(ctx, traceKey{}, t)
+}
+
+// parseTraceParent returns the trace of the traceparent header value.
+func parseTraceParent(traceparent string) (trace, bool) {
+	m := traceParentFormat.FindStringSubmatch(traceparent)
+	if m == nil || m[1] == "00000000000000000000000000000000" {
+		return trace{}, false
+	}
+
+	return trace{id: m[1], flags: m[2]}, true
+}
+
+// requestTrace returns the trace of a request with header and ctx.
+func requestTrace(ctx context.Context, header http.Header) trace {
+	if t, ok := parseTraceParent(header.Get(headerTraceParent)); ok {
+		t.state = header.Get(headerTraceState)
+		return t
+	}
+	if t, ok := ctx.Value(traceKey{}).(trace); ok {
+		return t
+	}
+
+	return trace{id: randomHex(16), flags: "01"}
+}
+
+// setHeader sets the trace context headers of an attempt, with a new parent id.
+func (t trace) setHeader(h http.Header) {
+	h.Set(headerTraceParent, "00-"+t.id+"-"+randomHex(8)+"-"+t.flags)
+	if t.state != "" {
+		h.Set(headerTraceState, t.state)
+	}
+}
+
+// randomHex returns n random bytes, hex encoded.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand doesn't fail on the supported platforms.
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,68 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestWithTraceContext should test that a trace is generated, with a parent id per attempt.
+func TestWithTraceContext(t *testing.T) {
+	var parents []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		parents = append(parents, r.Header.Get("Traceparent"))
+		if len(parents) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+
+	var buf bytes.Buffer
+	c := New(WithClock(newTestClock()), WithTraceContext(), WithJSONLog(&buf))
+	resp, err := c.GetURL(ts.URL, nil)
+	if err != nil {
+		t.Fatalf("TestWithTraceContext was incorrect, got error: %v", err)
+	}
+
+	if len(resp.TraceID) != 32 || len(parents) != 2 {
+		t.Fatalf("TestWithTraceContext was incorrect, got trace id: %q and traceparents: %v.", resp.TraceID, parents)
+	}
+	for _, p := range parents {
+		if !traceParentFormat.MatchString(p) || !strings.HasPrefix(p, "00-"+resp.TraceID+"-") || !strings.HasSuffix(p, "-01") {
+			t.Errorf("TestWithTraceContext was incorrect, got traceparent: %q, want: the trace %s.", p, resp.TraceID)
+		}
+	}
+	if parents[0] == parents[1] {
+		t.Errorf("TestWithTraceContext was incorrect, got the same parent id for both attempts: %s.", parents[0])
+	}
+	if !strings.Contains(buf.String(), `"trace_id":"`+resp.TraceID+`"`) {
+		t.Errorf("TestWithTraceContext was incorrect, got events: %s, want: the trace id.", buf.String())
+	}
+}
+
+// TestContextWithTrace should test that the trace of the context is propagated with its state.
+func TestContextWithTrace(t *testing.T) {
+	var header http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+	}))
+	defer ts.Close()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	ctx := ContextWithTrace(context.Background(), "00-"+traceID+"-00f067aa0ba902b7-00", "congo=t61rcWkgMzE")
+	resp, err := New(WithTraceContext()).RequestContext(ctx, ts.URL, "GET", nil, nil)
+	if err != nil {
+		t.Fatalf("TestContextWithTrace was incorrect, got error: %v", err)
+	}
+
+	if resp.TraceID != traceID || !strings.HasSuffix(header.Get("Traceparent"), "-00") || strings.Contains(header.Get("Traceparent"), "00f067aa0ba902b7") {
+		t.Errorf("TestContextWithTrace was incorrect, got: %s, want: the trace %s with a new parent id.", header.Get("Traceparent"), traceID)
+	}
+	if header.Get("Tracestate") != "congo=t61rcWkgMzE" {
+		t.Errorf("TestContextWithTrace was incorrect, got tracestate: %q, want: %q.", header.Get("Tracestate"), "congo=t61rcWkgMzE")
+	}
+}
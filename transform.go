@@ -0,0 +1,101 @@
+package httpclient
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// xssiPrefixes are the prefixes guarding JSON responses against XSSI, as sent
+// e.g. by Gerrit and the Google APIs.
+var xssiPrefixes = []string{")]}'\n", ")]}',\n", ")]}'", "while(1);", "for(;;);"}
+
+// ResponseTransformer transforms a response before its body is read, e.g. to
+// decrypt or decompress it, replacing resp.Body. A transformer changing the
+// size of the body should set resp.ContentLength to -1, and remove the headers
+// no longer true, like Content-Encoding. The error makes the request fail.
+type ResponseTransformer func(resp *http.Response) error
+
+// WithResponseTransformer adds the transformers applied, in order, to every
+// response of the Client, errors included, before its body is checked, read
+// or streamed to the caller. The maximum body size of WithMaxBodySize applies
+// to the transformed body.
+func WithResponseTransformer(transformers ...ResponseTransformer) Option {
+	return func(c *Client) {
+		c.transformers = append(c.transformers, transformers...)
+	}
+}
+
+// RequestTransformer transforms the body of a request, e.g. to encrypt or sign
+// it, returning the body sent. It gets the request being sent, to read its
+// method and URL and to set its headers, and the body given by the caller.
+// The error makes the request fail.
+type RequestTransformer func(req *http.Request, body []byte) ([]byte, error)
+
+// WithRequestTransformer adds the transformers applied, in order, to the
+// bodies of the requests of the Client, the streamed ones excepted. They are
+// applied again on each attempt, from the original body, so that nonces and
+// timestamps are fresh and the retries are signed as the first attempt.
+func WithRequestTransformer(transformers ...RequestTransformer) Option {
+	return func(c *Client) {
+		c.reqTransformers = append(c.reqTransformers, transformers...)
+	}
+}
+
+// StripXSSIPrefix is a ResponseTransformer removing from the bodies the
+// prefixes guarding against XSSI, like ")]}'" followed by a newline.
+func StripXSSIPrefix(resp *http.Response) error {
+	r := bufio.NewReader(resp.Body)
+	head, _ := r.Peek(len("while(1);"))
+	for _, prefix := range xssiPrefixes {
+		if bytes.HasPrefix(head, []byte(prefix)) {
+			r.Discard(len(prefix))
+			resp.ContentLength = -1
+			break
+		}
+	}
+	resp.Body = &transformedBody{Reader: r, Closer: resp.Body}
+
+	return nil
+}
+
+// transformedBody is a transformed body, closing the original one.
+type transformedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// transformRequest applies the request transformers of the Client to the
+// body of req, returning the body set to be sent.
+func (c *Client) transformRequest(req *http.Request, body []byte) ([]byte, error) {
+	if len(c.reqTransformers) == 0 {
+		return body, nil
+	}
+
+	for _, t := range c.reqTransformers {
+		var err error
+		if body, err = t(req, body); err != nil {
+			return nil, err
+		}
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+
+	return body, nil
+}
+
+// transform applies the transformers of the Client to resp.
+func (c *Client) transform(resp *http.Response) error {
+	for _, t := range c.transformers {
+		if err := t(resp); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
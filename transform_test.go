@@ -0,0 +1,117 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestStripXSSIPrefix should test that the XSSI prefixes are removed from the bodies.
+func TestStripXSSIPrefix(t *testing.T) {
+	bodies := map[string]string{
+		"/gerrit": ")]}'\n{\"id\":1}",
+		"/while":  "while(1);{\"id\":1}",
+		"/plain":  "{\"id\":1}",
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, bodies[r.URL.Path])
+	}))
+	defer ts.Close()
+
+	c := New(WithResponseTransformer(StripXSSIPrefix))
+	for path := range bodies {
+		resp, err := c.GetURL(ts.URL+path, nil)
+		if err != nil || string(resp.Body) != `{"id":1}` {
+			t.Errorf("TestStripXSSIPrefix was incorrect for %s, got: %q, %v, want: %s.", path, resp.Body, err, `{"id":1}`)
+		}
+	}
+}
+
+// TestWithResponseTransformer should test that the transformers are applied in
+// order, and that their errors fail the request.
+func TestWithResponseTransformer(t *testing.T) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	fmt.Fprint(zw, ")]}'\nsecret")
+	zw.Close()
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.Header().Set("Content-Encoding", "x-zlib")
+			fmt.Fprint(w, "not zlib")
+			return
+		}
+		w.Header().Set("Content-Encoding", "x-zlib")
+		w.Write(compressed.Bytes())
+	}))
+	defer ts.Close()
+
+	inflate := func(resp *http.Response) error {
+		if resp.Header.Get("Content-Encoding") != "x-zlib" {
+			return nil
+		}
+		zr, err := zlib.NewReader(resp.Body)
+		if err != nil {
+			return err
+		}
+		resp.Body = &transformedBody{Reader: zr, Closer: resp.Body}
+		resp.Header.Del("Content-Encoding")
+		resp.ContentLength = -1
+		return nil
+	}
+	c := New(WithResponseTransformer(inflate, StripXSSIPrefix))
+
+	resp, err := c.GetURL(ts.URL, nil)
+	if err != nil || string(resp.Body) != "secret" || resp.Headers.Get("Content-Encoding") != "" {
+		t.Errorf("TestWithResponseTransformer was incorrect, got: %q, %v, want: secret.", resp.Body, err)
+	}
+
+	_, err = c.GetURL(ts.URL+"/broken", nil)
+	if !errors.Is(err, zlib.ErrHeader) {
+		t.Errorf("TestWithResponseTransformer was incorrect, got: %v, want: %v.", err, zlib.ErrHeader)
+	}
+}
+
+// TestWithRequestTransformer should test that the bodies are transformed again on each attempt.
+func TestWithRequestTransformer(t *testing.T) {
+	var bodies, nonces []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		nonces = append(nonces, r.Header.Get("X-Nonce"))
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer ts.Close()
+
+	nonce := 0
+	sign := func(req *http.Request, body []byte) ([]byte, error) {
+		nonce++
+		req.Header.Set("X-Nonce", fmt.Sprint(nonce))
+		return []byte(base64.StdEncoding.EncodeToString(body)), nil
+	}
+	c := New(WithClock(newTestClock()), WithRequestTransformer(sign))
+
+	if _, err := c.Request(ts.URL, "POST", nil, strings.NewReader("payload")); err != nil {
+		t.Fatalf("TestWithRequestTransformer was incorrect, got error: %v", err)
+	}
+	want := base64.StdEncoding.EncodeToString([]byte("payload"))
+	if len(bodies) != 2 || bodies[0] != want || bodies[1] != want || nonces[0] != "1" || nonces[1] != "2" {
+		t.Errorf("TestWithRequestTransformer was incorrect, got: %v with nonces %v, want: twice %s with nonces 1 and 2.", bodies, nonces, want)
+	}
+
+	errSign := errors.New("no key")
+	c = New(WithRequestTransformer(func(*http.Request, []byte) ([]byte, error) { return nil, errSign }))
+	if _, err := c.Request(ts.URL, "POST", nil, strings.NewReader("payload")); !errors.Is(err, errSign) {
+		t.Errorf("TestWithRequestTransformer was incorrect, got: %v, want: %v.", err, errSign)
+	}
+}
@@ -0,0 +1,69 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// WithExpectContinue sends the bodies of at least minSize bytes with the
+// "Expect: 100-continue" header, waiting up to timeout for the server to
+// accept them before transmitting, so that the large uploads rejected by
+// the server (e.g. for a missing authorization) are not sent at all.
+// It is ignored with WithTransport.
+func WithExpectContinue(minSize int64, timeout time.Duration) Option {
+	return func(c *Client) {
+		c.expectContinue = minSize
+		c.expectContinueTimeout = timeout
+	}
+}
+
+// setExpectContinue sets the Expect header on req if its body is large enough
+// or, with a negative size, of unknown length.
+func (c *Client) setExpectContinue(req *http.Request, size int64) {
+	if c.expectContinueTimeout > 0 && (size < 0 || size >= c.expectContinue) {
+		req.Header.Set("Expect", "100-continue")
+	}
+}
+
+// UploadStream sends body, of unknown length, with the chunked transfer encoding
+// and without reading it in memory, e.g. an archive being generated.
+// onChunk, if not nil, is called with the size of each chunk read from body and
+// the bytes read so far. The request isn't retried, since body can't be sent
+// again, and WithTrailer is ignored.
+func UploadStream(URL, verb string, headers map[string]string, body io.Reader, onChunk func(n int, sent int64), opts ...RequestOption) (HTTPResponse, error) {
+	return defaultClient.UploadStream(URL, verb, headers, body, onChunk, opts...)
+}
+
+// UploadStream sends body, of unknown length, with the chunked transfer encoding
+// and without reading it in memory, e.g. an archive being generated.
+// onChunk, if not nil, is called with the size of each chunk read from body and
+// the bytes read so far. The request isn't retried, since body can't be sent
+// again, and WithTrailer is ignored.
+func (c *Client) UploadStream(URL, verb string, headers map[string]string, body io.Reader, onChunk func(n int, sent int64), opts ...RequestOption) (HTTPResponse, error) {
+	if onChunk == nil {
+		onChunk = func(int, int64) {}
+	}
+	stream := func(cfg *requestConfig) {
+		cfg.onChunk = onChunk
+	}
+
+	return c.Request(URL, verb, headers, body, append(opts, stream)...)
+}
+
+// chunkReader reports to onChunk the chunks read from r.
+type chunkReader struct {
+	r       io.Reader
+	sent    int64
+	onChunk func(n int, sent int64)
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.sent += int64(n)
+		r.onChunk(n, r.sent)
+	}
+
+	return n, err
+}
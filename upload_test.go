@@ -0,0 +1,66 @@
+package httpclient
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestExpectContinue should test that the large bodies are sent with Expect: 100-continue.
+func TestExpectContinue(t *testing.T) {
+	var expect []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		expect = append(expect, r.Header.Get("Expect"))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer ts.Close()
+
+	c := New(WithExpectContinue(1<<10, time.Second))
+	if _, err := c.PostURL(ts.URL, nil, bytes.NewReader(make([]byte, 1<<20))); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("TestExpectContinue was incorrect, got: %v, want: %v.", err, ErrInvalidStatus)
+	}
+	if _, err := c.PostURL(ts.URL, nil, strings.NewReader("small")); !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("TestExpectContinue was incorrect, got: %v, want: %v.", err, ErrInvalidStatus)
+	}
+
+	if len(expect) != 2 || expect[0] != "100-continue" || expect[1] != "" {
+		t.Errorf("TestExpectContinue was incorrect, got: %q, want: [\"100-continue\" \"\"].", expect)
+	}
+}
+
+// TestUploadStream should test that a body of unknown length is sent chunked.
+func TestUploadStream(t *testing.T) {
+	var got []byte
+	var encoding []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.TransferEncoding
+		got, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer ts.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		for i := 0; i < 3; i++ {
+			fmt.Fprintf(pw, "chunk %d\n", i)
+		}
+		pw.Close()
+	}()
+
+	var sent int64
+	_, err := UploadStream(ts.URL, "PUT", nil, pr, func(_ int, n int64) { sent = n })
+	if err != nil {
+		t.Fatalf("TestUploadStream was incorrect, got error: %v", err)
+	}
+
+	r := "chunk 0\nchunk 1\nchunk 2\n"
+	if string(got) != r || sent != int64(len(r)) || len(encoding) != 1 || encoding[0] != "chunked" {
+		t.Errorf("TestUploadStream was incorrect, got: %q (%d bytes, %v), want: %q chunked.", got, sent, encoding, r)
+	}
+}
@@ -0,0 +1,100 @@
+package httpclient
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// allowedSchemes are the URL schemes the Client sends requests to.
+var allowedSchemes = map[string]bool{"http": true, "https": true}
+
+// defaultPorts are the ports stripped from the URLs of the given scheme.
+var defaultPorts = map[string]string{"http": "80", "https": "443"}
+
+// URLError is returned when an URL is not valid for a request.
+type URLError struct {
+	URL    string
+	Reason string
+}
+
+func (e *URLError) Error() string {
+	return "invalid URL " + e.URL + ": " + e.Reason
+}
+
+// NormalizeURL validates rawURL and returns its normalized form, suitable
+// as cache or deduplication key: scheme and host are lowercased, international
+// host names are punycoded, the default port, duplicate slashes, dot segments
+// and the fragment are removed.
+// The returned error, if any, is a *URLError.
+func NormalizeURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", &URLError{URL: rawURL, Reason: err.Error()}
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if !allowedSchemes[u.Scheme] {
+		return "", &URLError{URL: rawURL, Reason: "unsupported scheme " + u.Scheme}
+	}
+
+	host, port := u.Hostname(), u.Port()
+	if host == "" {
+		return "", &URLError{URL: rawURL, Reason: "missing host"}
+	}
+	if net.ParseIP(host) == nil {
+		host = toASCIIHost(host)
+	}
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	u.Host = host
+
+	cleaned := cleanPath(u.EscapedPath())
+	if u.Path, err = url.PathUnescape(cleaned); err != nil {
+		return "", &URLError{URL: rawURL, Reason: err.Error()}
+	}
+	u.RawPath = cleaned
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	return u.String(), nil
+}
+
+// cleanPath removes duplicate slashes and dot segments (RFC 3986, section 5.2.4)
+// from an escaped path, keeping the trailing slash.
+func cleanPath(p string) string {
+	segments := strings.Split(p, "/")
+	out := make([]string, 0, len(segments))
+	trailing := false
+
+	for _, s := range segments {
+		trailing = false
+		switch s {
+		case "":
+			trailing = true
+		case ".":
+			trailing = true
+		case "..":
+			if len(out) > 0 {
+				out = out[:len(out)-1]
+			}
+			trailing = true
+		default:
+			out = append(out, s)
+		}
+	}
+
+	cleaned := "/" + strings.Join(out, "/")
+	if trailing && len(out) > 0 {
+		cleaned += "/"
+	}
+
+	return cleaned
+}
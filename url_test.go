@@ -0,0 +1,40 @@
+package httpclient
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestNormalizeURL should test the normalization of valid URLs.
+func TestNormalizeURL(t *testing.T) {
+	for in, want := range map[string]string{
+		"HTTP://Example.COM":                  "http://example.com/",
+		"https://example.com:443/a":           "https://example.com/a",
+		"http://example.com:8080/a":           "http://example.com:8080/a",
+		"http://example.com//a///b/":          "http://example.com/a/b/",
+		"http://example.com/a/./b/../c":       "http://example.com/a/c",
+		"http://example.com/a/b/..":           "http://example.com/a/",
+		"http://example.com/../a":             "http://example.com/a",
+		"http://example.com/a%2Fb?q=1#frag":   "http://example.com/a%2Fb?q=1",
+		"https://comune.città.it/file":        "https://comune.xn--citt-3na.it/file",
+		"http://bücher.example/":              "http://xn--bcher-kva.example/",
+		"http://[::1]:80/a":                   "http://[::1]/a",
+		"https://raw.github.com/a/b?x=%20y#z": "https://raw.github.com/a/b?x=%20y",
+	} {
+		got, err := NormalizeURL(in)
+		if err != nil || got != want {
+			t.Errorf("TestNormalizeURL was incorrect for %s, got: %s (%v), want: %s.", in, got, err, want)
+		}
+	}
+}
+
+// TestNormalizeInvalidURL should test that invalid URLs return a *URLError.
+func TestNormalizeInvalidURL(t *testing.T) {
+	for _, in := range []string{"hktp://incorrectprotocol.url", "ftp://example.com", "http://", "http://a b.com/%zz"} {
+		_, err := NormalizeURL(in)
+		var urlErr *URLError
+		if !errors.As(err, &urlErr) {
+			t.Errorf("TestNormalizeInvalidURL was incorrect for %s, got error: %v", in, err)
+		}
+	}
+}
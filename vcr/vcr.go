@@ -0,0 +1,265 @@
+// Package vcr records HTTP interactions to cassette files and replays them,
+// so tests don't depend on live servers.
+//
+// A Recorder is an http.RoundTripper to plug into a client:
+//
+//	rec, err := vcr.New("testdata/github.json", vcr.ModeReplay)
+//	...
+//	defer rec.Stop()
+//	client := httpclient.New(httpclient.WithTransport(rec))
+package vcr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Mode is the working mode of a Recorder.
+type Mode int
+
+const (
+	// ModeReplay serves the requests from the cassette only.
+	ModeReplay Mode = iota
+	// ModeRecord performs the real requests and records them to the cassette.
+	ModeRecord
+)
+
+// redacted replaces the values of the scrubbed headers.
+const redacted = "[REDACTED]"
+
+// ErrInteractionNotFound is returned in replay mode when the cassette
+// holds no interaction matching the request.
+var ErrInteractionNotFound = errors.New("vcr: interaction not found")
+
+// DefaultScrubbedHeaders are the headers holding secrets, never written to cassettes.
+var DefaultScrubbedHeaders = []string{"Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization", "X-Api-Key", "Private-Token"}
+
+// Cassette is the recorded sequence of interactions.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request with its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request.
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	Status  string      `json:"status"`
+	Code    int         `json:"code"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    Body        `json:"body,omitempty"`
+}
+
+// Body is a recorded body. It is written as a string when it's valid UTF-8,
+// as base64 otherwise.
+type Body []byte
+
+// MarshalJSON implements json.Marshaler.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	if err != nil {
+		return err
+	}
+	*b = decoded
+
+	return nil
+}
+
+// Recorder is an http.RoundTripper recording or replaying interactions.
+type Recorder struct {
+	// Transport performs the real requests in record mode.
+	// If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+	// ScrubHeaders are the headers whose values are redacted in the cassette.
+	ScrubHeaders []string
+
+	path string
+	mode Mode
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New returns a Recorder for the cassette at path. In replay mode the cassette
+// is loaded from path, in record mode it's written to path by Stop.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		ScrubHeaders: DefaultScrubbedHeaders,
+		path:         path,
+		mode:         mode,
+	}
+
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("vcr: invalid cassette %s: %w", path, err)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.mode == ModeReplay {
+		return r.replay(req)
+	}
+
+	return r.record(req)
+}
+
+// Stop writes the cassette in record mode. It's a no-op in replay mode.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(r.path, data, 0644)
+}
+
+// replay returns the first unused interaction matching method and URL of req.
+func (r *Recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != req.URL.String() {
+			continue
+		}
+		r.used[i] = true
+
+		return &http.Response{
+			Status:        interaction.Response.Status,
+			StatusCode:    interaction.Response.Code,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Headers.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, req.Method, req.URL)
+}
+
+// record performs req with the real transport and appends the interaction to the cassette.
+func (r *Recorder) record(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		// A RoundTripper must not modify the request of the caller.
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Headers: r.scrub(req.Header),
+			Body:    reqBody,
+		},
+		Response: Response{
+			Status:  resp.Status,
+			Code:    resp.StatusCode,
+			Headers: r.scrub(resp.Header),
+			Body:    respBody,
+		},
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// scrub returns a copy of h with the values of ScrubHeaders redacted.
+func (r *Recorder) scrub(h http.Header) http.Header {
+	scrubbed := h.Clone()
+	for name := range scrubbed {
+		for _, secret := range r.ScrubHeaders {
+			if strings.EqualFold(name, secret) {
+				for i := range scrubbed[name] {
+					scrubbed[name][i] = redacted
+				}
+			}
+		}
+	}
+
+	return scrubbed
+}
@@ -0,0 +1,79 @@
+package vcr
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	httpclient "github.com/italia/httpclient-lib-go"
+)
+
+// TestRecordAndReplay should test that a recorded interaction is replayed without the server.
+func TestRecordAndReplay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret")
+		fmt.Fprint(w, "{\"data\": \"example data\"}")
+	}))
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = httpclient.New(httpclient.WithTransport(rec)).GetURL(ts.URL, map[string]string{"Authorization": "token secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	ts.Close()
+
+	rep, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := httpclient.New(httpclient.WithTransport(rep)).GetURL(ts.URL, nil)
+	r := "{\"data\": \"example data\"}"
+	if err != nil || string(resp.Body) != r {
+		t.Errorf("TestRecordAndReplay was incorrect, got: %s (%v), want: %s.", resp.Body, err, r)
+	}
+
+	interaction := rep.cassette.Interactions[0]
+	if interaction.Request.Headers.Get("Authorization") != redacted || resp.Headers.Get("Set-Cookie") != redacted {
+		t.Errorf("TestRecordAndReplay was incorrect, secrets were recorded: %v %v", interaction.Request.Headers, resp.Headers)
+	}
+
+	if _, err := httpclient.New(httpclient.WithTransport(rep)).GetURL(ts.URL, nil); err == nil {
+		t.Errorf("TestRecordAndReplay was incorrect, got no error replaying a consumed interaction")
+	}
+}
+
+// TestRecordRequestUnmodified should test that the request of the caller isn't modified when recorded.
+func TestRecordRequestUnmodified(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	}))
+	defer ts.Close()
+
+	rec, err := New(filepath.Join(t.TempDir(), "cassette.json"), ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := ioutil.NopCloser(strings.NewReader("payload"))
+	req, _ := http.NewRequest("POST", ts.URL, body)
+	resp, err := rec.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if req.Body != body || string(rec.cassette.Interactions[0].Request.Body) != "payload" {
+		t.Errorf("TestRecordRequestUnmodified was incorrect, got: the body of the request replaced or not recorded, want: the body recorded, the request unmodified.")
+	}
+}
@@ -0,0 +1,19 @@
+package httpclient
+
+// WithHost sends the request with the Host header host in place of the host
+// of the URL, e.g. to reach a virtual host through the address of a load
+// balancer. The redirects are sent to the host of their URL.
+func WithHost(host string) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.host = host
+	}
+}
+
+// WithTLSServerName makes the Client send name as the TLS server name (SNI)
+// and verify the certificates for name, in place of the host of the URL,
+// e.g. for the fronted setups. It is ignored with WithTransport.
+func WithTLSServerName(name string) Option {
+	return func(c *Client) {
+		c.tlsServerName = name
+	}
+}
@@ -0,0 +1,46 @@
+package httpclient
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestWithHost should test that the Host header replaces the host of the URL.
+func TestWithHost(t *testing.T) {
+	var host string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+	}))
+	defer ts.Close()
+
+	if _, err := GetURL(ts.URL, nil, WithHost("developers.italia.it")); err != nil || host != "developers.italia.it" {
+		t.Errorf("TestWithHost was incorrect, got: %s, %v, want: developers.italia.it.", host, err)
+	}
+}
+
+// TestWithTLSServerName should test that the server name is sent as SNI.
+func TestWithTLSServerName(t *testing.T) {
+	var mu sync.Mutex
+	var serverName string
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	ts.TLS = &tls.Config{GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		mu.Lock()
+		serverName = hello.ServerName
+		mu.Unlock()
+		return nil, nil
+	}}
+	ts.StartTLS()
+	defer ts.Close()
+
+	// The certificate of the test server isn't trusted: only the handshake matters.
+	New(WithTLSServerName("origin.example.com")).GetURL(ts.URL, nil)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if serverName != "origin.example.com" {
+		t.Errorf("TestWithTLSServerName was incorrect, got: %q, want: origin.example.com.", serverName)
+	}
+}
@@ -0,0 +1,61 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultWatchInterval is the default interval between the fetches of Watch.
+const defaultWatchInterval = 5 * time.Minute
+
+// WithWatchInterval sets the interval between the fetches of Watch. Default is 5 minutes.
+func WithWatchInterval(d time.Duration) RequestOption {
+	return func(cfg *requestConfig) {
+		cfg.watchInterval = d
+	}
+}
+
+// Watch polls URL as Poll does, calling onChange with the first response and
+// then only with the responses whose content changed: those not answered
+// with a 304 (Not Modified) to the conditional requests and, for the servers
+// without ETag and Last-Modified, whose body has a different hash.
+// The failed fetches are logged and retried. The returned Poller stops it.
+func Watch(ctx context.Context, URL string, headers map[string]string, onChange func(HTTPResponse), opts ...RequestOption) *Poller {
+	return defaultClient.Watch(ctx, URL, headers, onChange, opts...)
+}
+
+// Watch polls URL as Poll does, calling onChange with the first response and
+// then only with the responses whose content changed: those not answered
+// with a 304 (Not Modified) to the conditional requests and, for the servers
+// without ETag and Last-Modified, whose body has a different hash.
+// The failed fetches are logged and retried. The returned Poller stops it.
+func (c *Client) Watch(ctx context.Context, URL string, headers map[string]string, onChange func(HTTPResponse), opts ...RequestOption) *Poller {
+	interval := newRequestConfig(opts).watchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	var last []byte
+	return c.Poll(ctx, URL, headers, interval, func(resp HTTPResponse, err error) {
+		if err != nil {
+			log.Warnf("Watch fetch failed: %v - Resource: %s", err, URL)
+			return
+		}
+		if resp.Status.Code == http.StatusNotModified {
+			return
+		}
+
+		sum := sha256.Sum256(resp.Body)
+		if last != nil && bytes.Equal(sum[:], last) {
+			log.Debugf("Watch fetched the same content - Resource: %s", URL)
+			return
+		}
+		last = sum[:]
+		onChange(resp)
+	}, opts...)
+}
@@ -0,0 +1,42 @@
+package httpclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestWatch should test that onChange is called only when the content changes,
+// as told by the validators or else by the body hash.
+func TestWatch(t *testing.T) {
+	// The server without validators serves v1 twice, then v2.
+	bodies := []string{"v1", "v1", "v2", "v2"}
+	hits := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if hits < len(bodies) {
+			w.Write([]byte(bodies[hits]))
+		}
+		hits++
+	}))
+	defer ts.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var changes []string
+	p := New(WithClock(newTestClock())).Watch(ctx, ts.URL, nil, func(resp HTTPResponse) {
+		if changes = append(changes, string(resp.Body)); len(changes) == 3 {
+			cancel()
+		}
+	}, WithWatchInterval(time.Hour))
+
+	select {
+	case <-p.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("TestWatch was incorrect, the watch didn't stop.")
+	}
+
+	if len(changes) != 3 || changes[0] != "v1" || changes[1] != "v2" || changes[2] != "" || hits != 5 {
+		t.Errorf("TestWatch was incorrect, got changes: %q after %d fetches, want: [v1 v2 \"\"] after 5.", changes, hits)
+	}
+}
@@ -0,0 +1,186 @@
+package httpclient
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultWebhookAttempts is the default number of delivery attempts of a webhook.
+const defaultWebhookAttempts = 5
+
+// defaultSignatureHeader is the default header carrying the webhook signature.
+const defaultSignatureHeader = "X-Signature-256"
+
+// ErrDeliveryFailed is returned when a webhook couldn't be delivered within the attempts.
+var ErrDeliveryFailed = errors.New("webhook delivery failed")
+
+// Delivery is the log of the delivery of a webhook payload.
+type Delivery struct {
+	URL      string
+	Payload  []byte
+	Attempts []DeliveryAttempt
+}
+
+// DeliveryAttempt is a single attempt to deliver a webhook.
+type DeliveryAttempt struct {
+	Time time.Time
+	// Status is the status code of the response, -1 if there was none.
+	Status int
+	Err    error
+	// Wait is the time waited before the attempt.
+	Wait time.Duration
+}
+
+// WebhookSender delivers signed webhook payloads, retrying with an exponential
+// backoff kept per endpoint, so that a failing endpoint slows down all its deliveries.
+type WebhookSender struct {
+	client          *Client
+	secret          []byte
+	maxAttempts     int
+	signatureHeader string
+	deadLetter      func(Delivery)
+
+	mu        sync.Mutex
+	endpoints map[string]*endpointBackoff
+}
+
+// endpointBackoff is the backoff state of a webhook endpoint.
+type endpointBackoff struct {
+	failures    int
+	nextAttempt time.Time
+}
+
+// WebhookOption configures a WebhookSender.
+type WebhookOption func(*WebhookSender)
+
+// WithWebhookAttempts sets the number of delivery attempts. Default is 5.
+func WithWebhookAttempts(n int) WebhookOption {
+	return func(s *WebhookSender) {
+		s.maxAttempts = n
+	}
+}
+
+// WithSignatureHeader sets the header carrying the signature. Default is X-Signature-256.
+func WithSignatureHeader(name string) WebhookOption {
+	return func(s *WebhookSender) {
+		s.signatureHeader = name
+	}
+}
+
+// WithDeadLetter sets the callback invoked with the deliveries failed after
+// exhausting the attempts.
+func WithDeadLetter(fn func(Delivery)) WebhookOption {
+	return func(s *WebhookSender) {
+		s.deadLetter = fn
+	}
+}
+
+// NewWebhookSender returns a WebhookSender delivering through c, signing the
+// payloads with the HMAC-SHA256 of secret.
+func NewWebhookSender(c *Client, secret []byte, opts ...WebhookOption) *WebhookSender {
+	s := &WebhookSender{
+		client:          c,
+		secret:          secret,
+		maxAttempts:     defaultWebhookAttempts,
+		signatureHeader: defaultSignatureHeader,
+		endpoints:       make(map[string]*endpointBackoff),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Sign returns the signature of payload, in the form "sha256=<hex digest>".
+func (s *WebhookSender) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send POSTs payload to URL with its signature, retrying until a 2xx response
+// or the attempts are exhausted. In the latter case the dead letter callback
+// is invoked and ErrDeliveryFailed is returned.
+func (s *WebhookSender) Send(URL string, payload []byte, headers map[string]string) (Delivery, error) {
+	delivery := Delivery{URL: URL, Payload: payload}
+
+	h := map[string]string{"Content-Type": "application/json"}
+	for k, v := range headers {
+		h[k] = v
+	}
+	h[s.signatureHeader] = s.Sign(payload)
+
+	for i := 0; i < s.maxAttempts; i++ {
+		wait := s.wait(URL)
+		if wait > 0 {
+			log.Infof("Webhook backoff, sleep %v - Resource: %s", wait, URL)
+			s.client.sleep(wait)
+		}
+
+		resp, err := s.client.PostURL(URL, h, bytes.NewReader(payload))
+		attempt := DeliveryAttempt{
+			Time:   s.client.clock.Now(),
+			Status: resp.Status.Code,
+			Err:    err,
+			Wait:   wait,
+		}
+		delivery.Attempts = append(delivery.Attempts, attempt)
+
+		if err == nil && resp.Status.Code >= 200 && resp.Status.Code <= 299 {
+			s.succeeded(URL)
+			return delivery, nil
+		}
+		log.Debugf("Webhook attempt %d failed: %d %v - Resource: %s", i+1, attempt.Status, err, URL)
+		s.failed(URL)
+	}
+
+	if s.deadLetter != nil {
+		s.deadLetter(delivery)
+	}
+
+	return delivery, fmt.Errorf("%w after %d attempts", ErrDeliveryFailed, len(delivery.Attempts))
+}
+
+// wait returns how long to wait before the next attempt to the endpoint.
+func (s *WebhookSender) wait(URL string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.endpoints[URL]
+	if !ok {
+		return 0
+	}
+
+	return e.nextAttempt.Sub(s.client.clock.Now())
+}
+
+func (s *WebhookSender) succeeded(URL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.endpoints, URL)
+}
+
+func (s *WebhookSender) failed(URL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.endpoints[URL]
+	if !ok {
+		e = &endpointBackoff{}
+		s.endpoints[URL] = e
+	}
+	e.failures++
+	backoff := time.Duration(expBackoffCalc(e.failures) * float64(time.Second))
+	e.nextAttempt = s.client.clock.Now().Add(backoff)
+}
@@ -0,0 +1,56 @@
+package httpclient
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// TestWebhookSender should test that a webhook is retried until delivered, signed.
+func TestWebhookSender(t *testing.T) {
+	var hits int32
+	var signature string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get("X-Signature-256")
+		if atomic.AddInt32(&hits, 1) <= 2 {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	clock := newTestClock()
+	s := NewWebhookSender(New(WithClock(clock)), []byte("secret"))
+	payload := []byte("{\"event\": \"push\"}")
+
+	delivery, err := s.Send(ts.URL, payload, nil)
+	if err != nil || len(delivery.Attempts) != 3 {
+		t.Errorf("TestWebhookSender was incorrect, got: %d attempts (%v), want: %d.", len(delivery.Attempts), err, 3)
+	}
+	if signature != s.Sign(payload) {
+		t.Errorf("TestWebhookSender was incorrect, got signature: %s, want: %s.", signature, s.Sign(payload))
+	}
+	if delivery.Attempts[2].Wait <= 0 {
+		t.Errorf("TestWebhookSender was incorrect, no backoff before the last attempt: %+v", delivery.Attempts)
+	}
+}
+
+// TestWebhookSenderDeadLetter should test that undeliverable webhooks are dead lettered.
+func TestWebhookSenderDeadLetter(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer ts.Close()
+
+	var dead []Delivery
+	s := NewWebhookSender(New(WithClock(newTestClock())), []byte("secret"),
+		WithWebhookAttempts(3),
+		WithDeadLetter(func(d Delivery) { dead = append(dead, d) }),
+	)
+
+	_, err := s.Send(ts.URL, []byte("{}"), nil)
+	if !errors.Is(err, ErrDeliveryFailed) || len(dead) != 1 || len(dead[0].Attempts) != 3 {
+		t.Errorf("TestWebhookSenderDeadLetter was incorrect, got: %v dead letters (%v)", dead, err)
+	}
+}
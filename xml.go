@@ -0,0 +1,104 @@
+package httpclient
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// xmlAccept is the Accept header sent by the XML helpers.
+const xmlAccept = "application/xml, text/xml;q=0.9, */*;q=0.1"
+
+// GetXML retrieves the XML document at URL and decodes it into out.
+// Documents in a charset other than UTF-8 are transcoded first, see ToUTF8.
+func GetXML(URL string, headers map[string]string, out interface{}) (HTTPResponse, error) {
+	return defaultClient.GetXML(URL, headers, out)
+}
+
+// PostXML sends in encoded as XML to URL and decodes the XML response into out.
+// If out is nil the response body is not decoded.
+func PostXML(URL string, headers map[string]string, in interface{}, out interface{}) (HTTPResponse, error) {
+	return defaultClient.PostXML(URL, headers, in, out)
+}
+
+// GetXML retrieves the XML document at URL and decodes it into out.
+// Documents in a charset other than UTF-8 are transcoded first, see ToUTF8.
+func (c *Client) GetXML(URL string, headers map[string]string, out interface{}) (HTTPResponse, error) {
+	return GetXMLWith(c, URL, headers, out)
+}
+
+// GetXMLWith is GetXML sending the request through d, e.g. a fake of the tests.
+func GetXMLWith(d Doer, URL string, headers map[string]string, out interface{}) (HTTPResponse, error) {
+	resp, err := d.Request(URL, "GET", xmlHeaders(headers, false), nil)
+	if err != nil {
+		return resp, err
+	}
+
+	return resp, decodeXML(resp, out)
+}
+
+// PostXML sends in encoded as XML to URL and decodes the XML response into out.
+// If out is nil the response body is not decoded.
+func (c *Client) PostXML(URL string, headers map[string]string, in interface{}, out interface{}) (HTTPResponse, error) {
+	return PostXMLWith(c, URL, headers, in, out)
+}
+
+// PostXMLWith is PostXML sending the request through d, e.g. a fake of the tests.
+func PostXMLWith(d Doer, URL string, headers map[string]string, in interface{}, out interface{}) (HTTPResponse, error) {
+	body, err := xml.Marshal(in)
+	if err != nil {
+		return HTTPResponse{}, err
+	}
+
+	resp, err := d.Request(URL, "POST", xmlHeaders(headers, true), bytes.NewReader(append([]byte(xml.Header), body...)))
+	if err != nil || out == nil {
+		return resp, err
+	}
+
+	return resp, decodeXML(resp, out)
+}
+
+// xmlHeaders returns headers with the defaults of the XML helpers.
+func xmlHeaders(headers map[string]string, hasBody bool) map[string]string {
+	h := map[string]string{"Accept": xmlAccept}
+	if hasBody {
+		h["Content-Type"] = "application/xml; charset=utf-8"
+	}
+	for k, v := range headers {
+		h[k] = v
+	}
+
+	return h
+}
+
+// isXMLMediaType reports whether mediaType is an XML one, e.g. application/atom+xml.
+func isXMLMediaType(mediaType string) bool {
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// decodeXML decodes the body of resp into out, checking its Content-Type if any.
+func decodeXML(resp HTTPResponse, out interface{}) error {
+	contentType := resp.Headers.Get("Content-Type")
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || !isXMLMediaType(mediaType) {
+			return fmt.Errorf("unexpected Content-Type %s for XML", contentType)
+		}
+	}
+
+	body, err := ToUTF8(resp.Body, contentType)
+	if err != nil {
+		return err
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	// The body is UTF-8 already, whatever the encoding declaration says.
+	decoder.CharsetReader = func(_ string, r io.Reader) (io.Reader, error) {
+		return r, nil
+	}
+
+	return decoder.Decode(out)
+}
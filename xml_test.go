@@ -0,0 +1,58 @@
+package httpclient
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// feed is a minimal Atom feed.
+type feed struct {
+	XMLName xml.Name `xml:"feed"`
+	Title   string   `xml:"title"`
+}
+
+// TestGetXML should test that an XML document in ISO-8859-1 is decoded.
+func TestGetXML(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.Write(append([]byte("<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><feed><title>Novit"), 0xE0, '<', '/', 't', 'i', 't', 'l', 'e', '>', '<', '/', 'f', 'e', 'e', 'd', '>'))
+	}))
+	defer ts.Close()
+
+	var out feed
+	_, err := GetXML(ts.URL, nil, &out)
+	if err != nil || out.Title != "Novità" {
+		t.Errorf("TestGetXML was incorrect, got: %q (%v), want: %q.", out.Title, err, "Novità")
+	}
+}
+
+// TestGetXMLContentType should test that non XML responses are rejected.
+func TestGetXMLContentType(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+	}))
+	defer ts.Close()
+
+	if _, err := GetXML(ts.URL, nil, &feed{}); err == nil {
+		t.Errorf("TestGetXMLContentType was incorrect, got no error for a JSON response")
+	}
+}
+
+// TestPostXML should test that the request is sent as XML.
+func TestPostXML(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+		w.Write(body)
+	}))
+	defer ts.Close()
+
+	var out feed
+	_, err := PostXML(ts.URL, nil, feed{Title: "echo"}, &out)
+	if err != nil || out.Title != "echo" {
+		t.Errorf("TestPostXML was incorrect, got: %q (%v), want: %q.", out.Title, err, "echo")
+	}
+}
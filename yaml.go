@@ -0,0 +1,84 @@
+package httpclient
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// maxYAMLSize is the maximum size of the documents fetched by GetYAML.
+const maxYAMLSize = 4 << 20
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+):`)
+
+// YAMLError is returned when a document fetched by GetYAML fails to decode.
+type YAMLError struct {
+	URL string
+	// Line is the line of the first error, 0 if unknown. There is no column:
+	// the errors of gopkg.in/yaml.v2 report the line only.
+	Line int
+	// Source is the text of Line.
+	Source string
+	Err    error
+}
+
+func (e *YAMLError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("invalid YAML %s: %v", e.URL, e.Err)
+	}
+
+	return fmt.Sprintf("invalid YAML %s at line %d (%q): %v", e.URL, e.Line, e.Source, e.Err)
+}
+
+func (e *YAMLError) Unwrap() error {
+	return e.Err
+}
+
+// GetYAML retrieves the YAML document at URL, e.g. a publiccode.yml file,
+// and decodes it into out. Documents larger than 4 MB fail with ErrBodyTooLarge,
+// documents not in UTF-8 are transcoded first (see ToUTF8) and decoding errors
+// are returned as *YAMLError.
+func GetYAML(URL string, headers map[string]string, out interface{}) (HTTPResponse, error) {
+	return defaultClient.GetYAML(URL, headers, out)
+}
+
+// GetYAML retrieves the YAML document at URL, e.g. a publiccode.yml file,
+// and decodes it into out. Documents larger than 4 MB fail with ErrBodyTooLarge,
+// documents not in UTF-8 are transcoded first (see ToUTF8) and decoding errors
+// are returned as *YAMLError.
+func (c *Client) GetYAML(URL string, headers map[string]string, out interface{}) (HTTPResponse, error) {
+	return GetYAMLWith(c, URL, headers, out)
+}
+
+// GetYAMLWith is GetYAML sending the request through d, e.g. a fake of the tests.
+func GetYAMLWith(d Doer, URL string, headers map[string]string, out interface{}) (HTTPResponse, error) {
+	resp, err := d.Request(URL, "GET", headers, nil, WithMaxBodySize(maxYAMLSize), WithUTF8())
+	if err != nil {
+		return resp, err
+	}
+
+	if err := yaml.Unmarshal(resp.Body, out); err != nil {
+		return resp, newYAMLError(URL, resp.Body, err)
+	}
+
+	return resp, nil
+}
+
+// newYAMLError returns the *YAMLError for err, locating its line in body.
+func newYAMLError(URL string, body []byte, err error) *YAMLError {
+	yamlErr := &YAMLError{URL: URL, Err: err}
+
+	m := yamlErrorLine.FindStringSubmatch(err.Error())
+	if m == nil {
+		return yamlErr
+	}
+	yamlErr.Line, _ = strconv.Atoi(m[1])
+	if lines := bytes.Split(body, []byte("\n")); yamlErr.Line > 0 && yamlErr.Line <= len(lines) {
+		yamlErr.Source = string(bytes.TrimRight(lines[yamlErr.Line-1], "\r"))
+	}
+
+	return yamlErr
+}
@@ -0,0 +1,55 @@
+package httpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestGetYAML should test that a YAML document is decoded.
+func TestGetYAML(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "publiccodeYmlVersion: \"0.2\"\nname: Medusa\n")
+	}))
+	defer ts.Close()
+
+	var out struct {
+		Version string `yaml:"publiccodeYmlVersion"`
+		Name    string `yaml:"name"`
+	}
+	_, err := GetYAML(ts.URL, nil, &out)
+	if err != nil || out.Version != "0.2" || out.Name != "Medusa" {
+		t.Errorf("TestGetYAML was incorrect, got: %+v (%v)", out, err)
+	}
+}
+
+// TestGetYAMLError should test that decoding errors carry the offending line.
+func TestGetYAMLError(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "name: Medusa\nurl: a: b\n")
+	}))
+	defer ts.Close()
+
+	var out map[string]interface{}
+	_, err := GetYAML(ts.URL, nil, &out)
+	var yamlErr *YAMLError
+	if !errors.As(err, &yamlErr) || yamlErr.Line != 2 || yamlErr.Source != "url: a: b" {
+		t.Errorf("TestGetYAMLError was incorrect, got error: %v", err)
+	}
+}
+
+// TestGetYAMLTooLarge should test that documents over the size limit are rejected.
+func TestGetYAMLTooLarge(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fmt.Fprint(w, "name: "+strings.Repeat("a", maxYAMLSize))
+	}))
+	defer ts.Close()
+
+	var out map[string]interface{}
+	if _, err := GetYAML(ts.URL, nil, &out); !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("TestGetYAMLTooLarge was incorrect, got error: %v", err)
+	}
+}